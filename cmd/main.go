@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -9,10 +11,13 @@ import (
 	"library/internal/controller"
 	"library/internal/repository"
 	"library/internal/service"
+	"library/models"
 	"library/server"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	_ "library/docs" // import generated docs
 )
@@ -32,6 +37,16 @@ import (
 // @host localhost:8080
 // @BasePath /api
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login, as "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description API key from /api-key, as "ApiKey <key>"
+
 func main() {
 	if err := initConfig(); err != nil {
 		logrus.Fatalf("error initializing configs: %s", err.Error())
@@ -41,30 +56,96 @@ func main() {
 		logrus.Fatalf("error loading env variables: %s", err.Error())
 	}
 
-	// init DB
-	db, err := repository.NewPostgresDB(repository.Config{
-		Host:     viper.GetString("db.host"),
-		Port:     viper.GetString("db.port"),
-		Username: viper.GetString("db.username"),
-		DBName:   viper.GetString("db.dbname"),
-		SSLMode:  viper.GetString("db.sslmode"),
-		Password: os.Getenv("DB_PASSWORD"),
-	})
-	if err != nil {
-		logrus.Fatalf("failed to initialize db: %s", err.Error())
+	tokenSecret := os.Getenv("JWT_SECRET")
+	if tokenSecret == "" {
+		logrus.Fatal("JWT_SECRET is not set")
+	}
+
+	// library [--storage=postgres | sqlite | memory] [migrate ... | role ...]
+	storage := flag.String("storage", viper.GetString("db.driver"), "where data is kept: postgres, sqlite, or memory to run without a database; defaults to db.driver")
+	flag.Parse()
+	args := flag.Args()
+
+	// init repositories
+	var repos *repository.Repository
+	switch *storage {
+	case repository.DriverPostgres, repository.DriverSQLite:
+		// init DB
+		db, err := repository.NewDB(repository.Config{
+			Driver:   *storage,
+			Path:     viper.GetString("db.path"),
+			Host:     viper.GetString("db.host"),
+			Port:     viper.GetString("db.port"),
+			Username: viper.GetString("db.username"),
+			DBName:   viper.GetString("db.dbname"),
+			SSLMode:  viper.GetString("db.sslmode"),
+			Password: os.Getenv("DB_PASSWORD"),
+		})
+		if err != nil {
+			logrus.Fatalf("failed to initialize db: %s", err.Error())
+		}
+
+		migrator, err := repository.NewMigrator(db)
+		if err != nil {
+			logrus.Fatalf("failed to load migrations: %s", err.Error())
+		}
+
+		// library migrate [up | down [steps] | status]
+		if len(args) > 0 && args[0] == "migrate" {
+			if err := runMigrate(migrator, args[1:]); err != nil {
+				logrus.Fatalf("migrate: %s", err.Error())
+			}
+			return
+		}
+
+		// library role <email> <patron | librarian | admin>
+		if len(args) > 0 && args[0] == "role" {
+			if err := runRole(repository.NewRepository(db), args[1:]); err != nil {
+				logrus.Fatalf("role: %s", err.Error())
+			}
+			return
+		}
+
+		if viper.GetBool("db.migrate_on_start") {
+			if _, err := migrator.Up(); err != nil {
+				logrus.Fatalf("failed to migrate db: %s", err.Error())
+			}
+		}
+
+		repos = repository.NewRepository(db)
+	case "memory":
+		if len(args) > 0 {
+			logrus.Fatalf("%s needs a database and cannot be run with --storage=memory", args[0])
+		}
+		logrus.Warn("keeping data in memory, it is lost when the server stops")
+		repos = repository.NewMemoryRepository()
+	default:
+		logrus.Fatalf("unknown storage %q, expected postgres, sqlite or memory", *storage)
 	}
 
 	// generate fake data
-	if err := data.InitData(db); err != nil {
+	if err := data.InitData(context.Background(), repos); err != nil {
 		logrus.Fatalf("failed to initialize data: %v", err)
 	}
 
-	// init repositories
-	repos := repository.NewRepository(db)
 	// init service
-	services := service.NewService(repos)
+	services := service.NewService(repos, service.Config{
+		DefaultPolicy: models.LoanPolicy{
+			Name:        "default",
+			MaxLoans:    viper.GetInt("rent.max_loans"),
+			LoanDays:    viper.GetInt("rent.loan_days"),
+			MaxRenewals: viper.GetInt("rent.max_renewals"),
+		},
+		PickupWindow:    time.Duration(viper.GetInt("holds.pickup_days")) * 24 * time.Hour,
+		FineDailyRate:   viper.GetInt64("fines.daily_rate"),
+		FineCap:         viper.GetInt64("fines.max_amount"),
+		FineThreshold:   viper.GetInt64("fines.block_threshold"),
+		TokenSecret:     []byte(tokenSecret),
+		AccessTokenTTL:  time.Duration(viper.GetInt("auth.access_token_minutes")) * time.Minute,
+		RefreshTokenTTL: time.Duration(viper.GetInt("auth.refresh_token_days")) * 24 * time.Hour,
+	})
 	// init controller
-	handlers := controller.NewHandler(services)
+	handlers := controller.NewHandler(services, time.Duration(viper.GetInt("db.query_timeout_seconds"))*time.Second)
 
 	// run http server
 	srv := new(server.Server)
@@ -88,6 +169,73 @@ func main() {
 
 }
 
+func runMigrate(migrator *repository.Migrator, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			logrus.Printf("applied %04d_%s", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			logrus.Print("no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := migrator.Down(steps)
+		for _, migration := range rolledBack {
+			logrus.Printf("rolled back %04d_%s", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, expected up, down or status", command)
+	}
+}
+
+// runRole gives the user with an email a role. It is how the first admin is
+// made, who can then give roles over the API.
+func runRole(repos *repository.Repository, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected an email and a role")
+	}
+
+	ctx := context.Background()
+	user, err := repos.Users.GetByEmail(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("user %s: %w", args[0], err)
+	}
+	if err := service.NewUsersService(repos.Users).SetRole(ctx, user.ID, args[1]); err != nil {
+		return err
+	}
+	logrus.Printf("%s is now %s", user.Email, args[1])
+	return nil
+}
+
 func initConfig() error {
 	viper.AddConfigPath("configs")
 	viper.SetConfigName("config")
@@ -1,97 +1,142 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"github.com/brianvoe/gofakeit/v6"
-	"gorm.io/gorm"
+	"library/internal/isbn"
+	"library/internal/repository"
 	"library/models"
 	"time"
 )
 
-func InitData(db *gorm.DB) error {
+// InitData fills empty tables with made-up authors, books, copies and
+// users. It goes through the repositories, so it works for any storage.
+func InitData(ctx context.Context, repos *repository.Repository) error {
 	// Initialize authors
-	if err := initAuthors(db); err != nil {
+	if err := initAuthors(ctx, repos); err != nil {
 		return err
 	}
 	// Initialize books
-	if err := initBooks(db); err != nil {
+	if err := initBooks(ctx, repos); err != nil {
+		return err
+	}
+	// Initialize book copies
+	if err := initCopies(ctx, repos); err != nil {
 		return err
 	}
 	// Initialize users
-	if err := initUsers(db); err != nil {
+	if err := initUsers(ctx, repos); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func initAuthors(db *gorm.DB) error {
-	var count int64
-	db.Model(&models.Author{}).Count(&count)
+func initAuthors(ctx context.Context, repos *repository.Repository) error {
+	_, count, err := repos.Authors.GetAll(ctx, models.AuthorQuery{Page: 1, PageSize: 1})
+	if err != nil {
+		return err
+	}
 	if count == 0 {
-		authors := make([]models.Author, 10)
 		for i := 0; i < 10; i++ {
-			authors[i] = models.Author{
+			author := models.Author{
 				Name: gofakeit.Name(),
 			}
-		}
-		if err := db.Create(&authors).Error; err != nil {
-			return fmt.Errorf("failed to create authors: %w", err)
+			if err := repos.Authors.Create(ctx, author); err != nil {
+				return fmt.Errorf("failed to create authors: %w", err)
+			}
 		}
 	}
 	return nil
 }
 
-func initBooks(db *gorm.DB) error {
-	var count int64
-	db.Model(&models.Book{}).Count(&count)
+func initBooks(ctx context.Context, repos *repository.Repository) error {
+	_, count, err := repos.Books.GetAll(ctx, models.BookQuery{Page: 1, PageSize: 1})
+	if err != nil {
+		return err
+	}
 	if count == 0 {
-		var authors []models.Author
-		db.Find(&authors)
+		authors, _, err := repos.Authors.GetAll(ctx, models.AuthorQuery{Page: 1, PageSize: 1000})
+		if err != nil {
+			return err
+		}
 		if len(authors) == 0 {
 			return fmt.Errorf("no authors found to assign books")
 		}
 
-		books := make([]models.Book, 100)
 		for i := 0; i < 100; i++ {
-			books[i] = models.Book{
+			book := models.Book{
 				Title:       gofakeit.BookTitle(),
-				AuthorID:    authors[gofakeit.Number(0, len(authors)-1)].ID,
 				PublishedAt: gofakeit.DateRange(time.Now().AddDate(-10, 0, 0), time.Now()),
 				ISBN:        generateISBN(),
+				Contributors: []models.BookContributor{
+					{AuthorID: authors[gofakeit.Number(0, len(authors)-1)].ID, Role: models.RoleAuthor, Position: 1},
+				},
+			}
+			if err := repos.Books.Create(ctx, book); err != nil {
+				return fmt.Errorf("failed to create books: %w", err)
 			}
 		}
-		if err := db.Create(&books).Error; err != nil {
-			return fmt.Errorf("failed to create books: %w", err)
+	}
+	return nil
+}
+
+// initCopies gives every book copies when no book has any.
+func initCopies(ctx context.Context, repos *repository.Repository) error {
+	books, _, err := repos.Books.GetAll(ctx, models.BookQuery{Page: 1, PageSize: 1000})
+	if err != nil {
+		return err
+	}
+	for _, book := range books {
+		copies, err := repos.Copies.GetByBook(ctx, book.ID)
+		if err != nil {
+			return err
+		}
+		if len(copies) > 0 {
+			return nil
+		}
+	}
+
+	for _, book := range books {
+		copiesPerBook := gofakeit.Number(1, 3)
+		for n := 1; n <= copiesPerBook; n++ {
+			bookCopy := models.BookCopy{
+				BookID:        book.ID,
+				Barcode:       fmt.Sprintf("%07d%02d", book.ID, n),
+				ShelfLocation: fmt.Sprintf("%s-%d", gofakeit.LetterN(1), gofakeit.Number(1, 20)),
+				Condition:     models.CopyConditionGood,
+			}
+			if err := repos.Copies.Create(ctx, bookCopy); err != nil {
+				return fmt.Errorf("failed to create book copies: %w", err)
+			}
 		}
 	}
 	return nil
 }
 
-func initUsers(db *gorm.DB) error {
-	var count int64
-	db.Model(&models.User{}).Count(&count)
+func initUsers(ctx context.Context, repos *repository.Repository) error {
+	_, count, err := repos.Users.GetAll(ctx, models.UserQuery{Page: 1, PageSize: 1})
+	if err != nil {
+		return err
+	}
 	if count == 0 {
-		users := make([]models.User, 50)
 		for i := 0; i < 50; i++ {
-			users[i] = models.User{
+			user := models.User{
 				Name:  gofakeit.Name(),
 				Email: gofakeit.Email(),
 			}
-		}
-		if err := db.Create(&users).Error; err != nil {
-			return fmt.Errorf("failed to create users: %w", err)
+			if err := repos.Users.Create(ctx, user); err != nil {
+				return fmt.Errorf("failed to create users: %w", err)
+			}
 		}
 	}
 	return nil
 }
 
+// generateISBN makes up a valid ISBN-13 in the English-language group, in
+// the form the catalogue stores.
 func generateISBN() string {
-	return fmt.Sprintf("%d-%d-%d-%d-%d",
-		gofakeit.Number(100, 999),
-		gofakeit.Number(1000, 9999),
-		gofakeit.Number(100, 999),
-		gofakeit.Number(10, 99),
-		gofakeit.Number(1000, 9999),
-	)
+	body := fmt.Sprintf("9780%08d", gofakeit.Number(0, 99999999))
+	return body + string(isbn.CheckDigit13(body))
 }
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// APIKeyInput is the body of an API key creation. Scopes are permissions
+// such as catalog:read or loans:write; a key without ExpiresAt never
+// expires.
+type APIKeyInput struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyResponse is an API key as the API shows it, without the key itself.
+type APIKeyResponse struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	CreatedByID *int       `json:"created_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+}
+
+func apiKeyResponse(key models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      key.Scopes,
+		CreatedByID: key.CreatedByID,
+		CreatedAt:   key.CreatedAt,
+		ExpiresAt:   key.ExpiresAt,
+		RevokedAt:   key.RevokedAt,
+		LastUsedAt:  key.LastUsedAt,
+	}
+}
+
+// CreatedAPIKeyResponse is a new API key together with the key itself,
+// which is shown only once.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// CreateAPIKey @Summary Create API Key
+// @Tags api-keys
+// @Description Create an API key for another system, scoped to the permissions given. The key is in the response and cannot be retrieved again.
+// @ID create-api-key
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   key  body    APIKeyInput     true        "API Key Info"
+// @Success 201 {object} CreatedAPIKeyResponse
+// @Failure 422 {object} ErrorResponse "name, scopes or expiry is not valid"
+// @Router /api-key [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var input APIKeyInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	key := models.APIKey{Name: input.Name, Scopes: input.Scopes, ExpiresAt: input.ExpiresAt}
+	if creator := identityOf(c).UserID; creator != 0 {
+		key.CreatedByID = &creator
+	}
+
+	created, secret, err := h.Services.APIKeys.Create(c.Request.Context(), key)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAPIKeyResponse{APIKeyResponse: apiKeyResponse(created), Key: secret})
+}
+
+// GetAllAPIKeys @Summary Get All API Keys
+// @Tags api-keys
+// @Description Get list of all API keys, including revoked and expired ones
+// @ID get-all-api-keys
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} APIKeyResponse
+// @Router /api-key [get]
+func (h *Handler) GetAllAPIKeys(c *gin.Context) {
+	keys, err := h.Services.APIKeys.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, apiKeyResponse(key))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey @Summary Revoke API Key
+// @Tags api-keys
+// @Description Revoke an API key by ID. Requests made with it are refused from then on.
+// @ID revoke-api-key
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "API Key ID"
+// @Success 200 {object} map[string]string "status: API key revoked"
+// @Failure 409 {object} ErrorResponse "API key is already revoked"
+// @Router /api-key/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid API key ID")
+		return
+	}
+
+	if err := h.Services.APIKeys.Revoke(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "API key revoked"})
+}
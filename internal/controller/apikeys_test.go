@@ -0,0 +1,121 @@
+package controller_test
+
+import (
+	"bytes"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			APIKeys: mockAPIKeyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/api-key", signedInAs(ctrl, handler, models.Identity{UserID: 1, Role: models.RoleAdmin}), handler.CreateAPIKey)
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	admin := 1
+	key := models.APIKey{Name: "kiosk", Scopes: []string{"catalog:read", "loans:write"}, ExpiresAt: &expiresAt, CreatedByID: &admin}
+	created := key
+	created.ID, created.Prefix, created.KeyHash = 5, "lib_abcdefgh", "hash"
+	mockAPIKeyService.EXPECT().Create(gomock.Any(), key).Return(created, "lib_abcdefghijk", nil)
+
+	body := `{"name":"kiosk","scopes":["catalog:read","loans:write"],"expires_at":"2030-01-01T00:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/api-key", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{
+		"id": 5,
+		"name": "kiosk",
+		"prefix": "lib_abcdefgh",
+		"scopes": ["catalog:read", "loans:write"],
+		"created_by_id": 1,
+		"created_at": "0001-01-01T00:00:00Z",
+		"expires_at": "2030-01-01T00:00:00Z",
+		"revoked_at": null,
+		"last_used_at": null,
+		"key": "lib_abcdefghijk"
+	}`, w.Body.String())
+}
+
+func TestHandler_createAPIKey_UnknownScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			APIKeys: mockAPIKeyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/api-key", signedInAs(ctrl, handler, models.Identity{UserID: 1, Role: models.RoleAdmin}), handler.CreateAPIKey)
+
+	mockAPIKeyService.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Return(models.APIKey{}, "", &service.Error{Kind: service.ErrValidation, Code: "unknown_scope", Message: `unknown scope "everything"`})
+
+	req, _ := http.NewRequest("POST", "/api-key", bytes.NewBufferString(`{"name":"kiosk","scopes":["everything"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown_scope")
+}
+
+func TestHandler_revokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"revoked", nil, http.StatusOK},
+		{"already revoked", &service.Error{Kind: service.ErrConflict, Code: "key_revoked", Message: "API key is already revoked"}, http.StatusConflict},
+		{"not found", &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "API key not found"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+			handler := &controller.Handler{
+				Services: &service.Service{
+					APIKeys: mockAPIKeyService,
+				},
+			}
+
+			r := setupRouter()
+			r.DELETE("/api-key/:id", handler.RevokeAPIKey)
+
+			mockAPIKeyService.EXPECT().Revoke(gomock.Any(), 5).Return(tt.err)
+
+			req, _ := http.NewRequest("DELETE", "/api-key/5", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// identityKey is where Authenticate keeps the identity of the caller in the
+// gin context.
+const identityKey = "identity"
+
+// RegisterInput is the body of a registration.
+type RegisterInput struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginInput is the body of a login.
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshInput is the body of a token refresh.
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse carries the tokens issued on login and refresh. ExpiresIn
+// is the lifetime of the access token in seconds.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func tokenResponse(tokens models.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+// Register @Summary Register
+// @Tags auth
+// @Description Create a user account that can log in with the password given. Passwords are 8 to 72 bytes long.
+// @ID register
+// @Accept  json
+// @Produce  json
+// @Param   user  body    RegisterInput     true        "Account Info"
+// @Success 201 {object} map[string]string "status: user registered"
+// @Failure 409 {object} ErrorResponse "email is taken"
+// @Failure 422 {object} ErrorResponse "name, email or password is not valid"
+// @Router /auth/register [post]
+func (h *Handler) Register(c *gin.Context) {
+	var input RegisterInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Auth.Register(c.Request.Context(), models.User{Name: input.Name, Email: input.Email}, input.Password); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "user registered"})
+}
+
+// Login @Summary Log In
+// @Tags auth
+// @Description Exchange an email and password for an access token and a refresh token
+// @ID login
+// @Accept  json
+// @Produce  json
+// @Param   credentials  body    LoginInput     true        "Credentials"
+// @Success 200 {object} TokenResponse
+// @Failure 401 {object} ErrorResponse "wrong email or password"
+// @Router /auth/login [post]
+func (h *Handler) Login(c *gin.Context) {
+	var input LoginInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	tokens, err := h.Services.Auth.Login(c.Request.Context(), input.Email, input.Password)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse(tokens))
+}
+
+// RefreshToken @Summary Refresh Tokens
+// @Tags auth
+// @Description Exchange a refresh token for a new access token and refresh token
+// @ID refresh-token
+// @Accept  json
+// @Produce  json
+// @Param   token  body    RefreshInput     true        "Refresh Token"
+// @Success 200 {object} TokenResponse
+// @Failure 401 {object} ErrorResponse "refresh token is not valid or has expired"
+// @Router /auth/refresh [post]
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var input RefreshInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	tokens, err := h.Services.Auth.Refresh(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse(tokens))
+}
+
+// Authenticate is middleware that only lets through requests with a valid
+// bearer access token or API key and records who made them for the handlers
+// after it.
+func (h *Handler) Authenticate(c *gin.Context) {
+	if h.authenticate(c) {
+		c.Next()
+	}
+}
+
+// authenticate records who made a request with a valid bearer access token
+// or API key. Other requests are aborted with a 401.
+func (h *Handler) authenticate(c *gin.Context) bool {
+	scheme, credentials, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+	credentials = strings.TrimSpace(credentials)
+
+	var (
+		identity models.Identity
+		err      error
+	)
+	switch {
+	case credentials != "" && strings.EqualFold(scheme, "Bearer"):
+		identity, err = h.Services.Auth.Authenticate(c.Request.Context(), credentials)
+	case credentials != "" && strings.EqualFold(scheme, "ApiKey"):
+		identity, err = h.Services.APIKeys.Authenticate(c.Request.Context(), credentials)
+	default:
+		challenge(c, "")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Code: "unauthenticated", Error: "a bearer token or API key is required"})
+		return false
+	}
+	if err != nil {
+		challenge(c, "invalid_token")
+		errorResponse(c, err)
+		c.Abort()
+		return false
+	}
+
+	c.Set(identityKey, identity)
+	return true
+}
+
+// challenge sets the WWW-Authenticate header of a 401, with one challenge
+// for each scheme authenticate accepts.
+func challenge(c *gin.Context, errorCode string) {
+	params := `realm="library"`
+	if errorCode != "" {
+		params += `, error="` + errorCode + `"`
+	}
+	c.Writer.Header().Add("WWW-Authenticate", "Bearer "+params)
+	c.Writer.Header().Add("WWW-Authenticate", "ApiKey "+params)
+}
+
+// identityOf returns who made a request that passed Authenticate.
+func identityOf(c *gin.Context) models.Identity {
+	return c.MustGet(identityKey).(models.Identity)
+}
@@ -0,0 +1,319 @@
+package controller_test
+
+import (
+	"bytes"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// signedIn returns the Authenticate middleware of handler, set up to accept
+// the bearer token "token" as the patron userID.
+func signedIn(ctrl *gomock.Controller, handler *controller.Handler, userID int) gin.HandlerFunc {
+	return signedInAs(ctrl, handler, models.Identity{UserID: userID, Role: models.RolePatron})
+}
+
+// signedInAs is signedIn for any identity.
+func signedInAs(ctrl *gomock.Controller, handler *controller.Handler, identity models.Identity) gin.HandlerFunc {
+	mockAuthService := service.NewMockAuth(ctrl)
+	mockAuthService.EXPECT().Authenticate(gomock.Any(), "token").Return(identity, nil).AnyTimes()
+	handler.Services.Auth = mockAuthService
+	return handler.Authenticate
+}
+
+func TestHandler_register(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/auth/register", handler.Register)
+
+	mockAuthService.EXPECT().Register(gomock.Any(), models.User{Name: "Ada", Email: "ada@example.com"}, "correct horse").Return(nil)
+
+	body := `{"name":"Ada","email":"ada@example.com","password":"correct horse"}`
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "user registered")
+}
+
+func TestHandler_register_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/auth/register", handler.Register)
+
+	mockAuthService.EXPECT().Register(gomock.Any(), gomock.Any(), "short").
+		Return(&service.Error{Kind: service.ErrValidation, Code: "password_too_short", Message: "password must be at least 8 characters"})
+
+	body := `{"name":"Ada","email":"ada@example.com","password":"short"}`
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"code":"password_too_short","error":"password must be at least 8 characters"}`, w.Body.String())
+}
+
+func TestHandler_login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/auth/login", handler.Login)
+
+	mockAuthService.EXPECT().Login(gomock.Any(), "ada@example.com", "correct horse").
+		Return(models.TokenPair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}, nil)
+
+	body := `{"email":"ada@example.com","password":"correct horse"}`
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":900}`, w.Body.String())
+}
+
+func TestHandler_login_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/auth/login", handler.Login)
+
+	mockAuthService.EXPECT().Login(gomock.Any(), "ada@example.com", "wrong").
+		Return(models.TokenPair{}, &service.Error{Kind: service.ErrUnauthenticated, Code: "invalid_credentials", Message: "wrong email or password"})
+
+	body := `{"email":"ada@example.com","password":"wrong"}`
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"code":"invalid_credentials","error":"wrong email or password"}`, w.Body.String())
+}
+
+func TestHandler_refreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/auth/refresh", handler.RefreshToken)
+
+	mockAuthService.EXPECT().Refresh(gomock.Any(), "refresh").
+		Return(models.TokenPair{AccessToken: "access 2", RefreshToken: "refresh 2", ExpiresIn: 15 * time.Minute}, nil)
+
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"refresh"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"access_token":"access 2"`)
+}
+
+func TestHandler_authenticate_MissingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: service.NewMockAuth(ctrl),
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", handler.Authenticate, handler.RentBook)
+
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"book_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="library"`, w.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(t, `{"code":"unauthenticated","error":"a bearer token or API key is required"}`, w.Body.String())
+}
+
+func TestHandler_authenticate_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Auth: mockAuthService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", handler.Authenticate, handler.RentBook)
+
+	mockAuthService.EXPECT().Authenticate(gomock.Any(), "expired").
+		Return(models.Identity{}, &service.Error{Kind: service.ErrUnauthenticated, Code: "token_expired", Message: "access token has expired"})
+
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"book_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer expired")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "token_expired")
+}
+
+func TestHandler_rentBook_ForSomeoneElse(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		status int
+	}{
+		{"patron", models.RolePatron, http.StatusForbidden},
+		{"librarian", models.RoleLibrarian, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockBookService := service.NewMockBooks(ctrl)
+			handler := &controller.Handler{
+				Services: &service.Service{
+					Books: mockBookService,
+				},
+			}
+
+			r := setupRouter()
+			r.POST("/rent", signedInAs(ctrl, handler, models.Identity{UserID: 1, Role: tt.role}), handler.RentBook)
+
+			if tt.status == http.StatusOK {
+				mockBookService.EXPECT().RentBook(gomock.Any(), 7, 2, 0).Return(nil)
+			}
+
+			req, _ := http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"user_id":7,"book_id":2}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHandler_rentBook_WithAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			APIKeys: mockAPIKeyService,
+			Books:   mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", handler.Authenticate, handler.RentBook)
+
+	kiosk := models.Identity{APIKeyID: 3, Scopes: []string{"loans:write"}}
+	mockAPIKeyService.EXPECT().Authenticate(gomock.Any(), "lib_secret").Return(kiosk, nil).Times(2)
+	mockBookService.EXPECT().RentBook(gomock.Any(), 7, 2, 0).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"user_id":7,"book_id":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "ApiKey lib_secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// a key is nobody, so it must say who the book is for
+	req, _ = http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"book_id":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "ApiKey lib_secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "user_id_required")
+}
+
+func TestHandler_authenticate_ExpiredAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			APIKeys: mockAPIKeyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", handler.Authenticate, handler.RentBook)
+
+	mockAPIKeyService.EXPECT().Authenticate(gomock.Any(), "lib_old").
+		Return(models.Identity{}, &service.Error{Kind: service.ErrUnauthenticated, Code: "api_key_expired", Message: "API key has expired"})
+
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBufferString(`{"book_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "ApiKey lib_old")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, []string{`Bearer realm="library", error="invalid_token"`, `ApiKey realm="library", error="invalid_token"`}, w.Header().Values("WWW-Authenticate"))
+	assert.JSONEq(t, `{"code":"api_key_expired","error":"API key has expired"}`, w.Body.String())
+}
@@ -3,11 +3,60 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"library/models"
 )
 
+// AuthorInput is the body of an author creation or update.
+type AuthorInput struct {
+	Name string `json:"name" binding:"required,notblank,max=200"`
+}
+
+// AuthorResponse is an author as the API shows it.
+type AuthorResponse struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func authorResponse(author models.Author) AuthorResponse {
+	return AuthorResponse{ID: author.ID, Name: author.Name, DeletedAt: author.DeletedAt}
+}
+
+// AuthorPageResponse is a page of the author listing. Next links to the
+// following page and is empty on the last one.
+type AuthorPageResponse struct {
+	Authors  []AuthorResponse `json:"authors"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+	Next     string           `json:"next"`
+}
+
+func authorPageResponse(c *gin.Context, authors models.AuthorPage) AuthorPageResponse {
+	response := AuthorPageResponse{
+		Authors:  make([]AuthorResponse, 0, len(authors.Authors)),
+		Total:    authors.Total,
+		Page:     authors.Page,
+		PageSize: authors.PageSize,
+		Next:     nextPage(c, authors.Page, authors.PageSize, authors.Total),
+	}
+	for _, author := range authors.Authors {
+		response.Authors = append(response.Authors, authorResponse(author))
+	}
+	return response
+}
+
+// CreditResponse is a book an author contributed to, with their role on it.
+type CreditResponse struct {
+	BookID   int    `json:"book_id"`
+	Title    string `json:"title"`
+	Role     string `json:"role"`
+	Position int    `json:"position"`
+}
+
 // GetAuthorByID @Summary Get Author by ID
 // @Tags author
 // @Description Get author details by ID
@@ -15,60 +64,108 @@ import (
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "Author ID"
-// @Success 200 {object} models.Author
+// @Success 200 {object} AuthorResponse
 // @Router /author/{id} [get]
 func (h *Handler) GetAuthorByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		badRequest(c, "invalid_id", "invalid author ID")
+		return
+	}
+
+	author, err := h.Services.Authors.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authorResponse(author))
+}
+
+// GetAuthorBooks @Summary Get Author Books
+// @Tags authors
+// @Description Get the books an author contributed to, one entry per book and role
+// @ID get-author-books
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Author ID"
+// @Success 200 {array} CreditResponse
+// @Router /author/{id}/books [get]
+func (h *Handler) GetAuthorBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid author ID")
 		return
 	}
 
-	author, err := h.Services.Authors.GetByID(id)
+	credits, err := h.Services.Authors.GetBooks(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		errorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, author)
+	response := make([]CreditResponse, 0, len(credits))
+	for _, credit := range credits {
+		response = append(response, CreditResponse{
+			BookID:   credit.BookID,
+			Title:    credit.Book.Title,
+			Role:     credit.Role,
+			Position: credit.Position,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetAllAuthors @Summary Get All Authors
 // @Tags author
-// @Description Get list of all authors
+// @Description Get a page of authors, optionally filtered by name and sorted
 // @ID get-all-authors
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} models.Author
+// @Param   name    query    string     false        "Part of the name, case-insensitive"
+// @Param   sort    query    string     false        "id or name, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Authors per page, at most 100"
+// @Success 200 {object} AuthorPageResponse
 // @Router /author [get]
 func (h *Handler) GetAllAuthors(c *gin.Context) {
-	authors, err := h.Services.Authors.GetAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	query := models.AuthorQuery{Name: c.Query("name"), Sort: c.Query("sort")}
+
+	var err error
+	if query.Page, query.PageSize, err = pageParams(c); err != nil {
+		badRequest(c, "invalid_query", err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, authors)
+	authors, err := h.Services.Authors.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authorPageResponse(c, authors))
 }
 
 // CreateAuthor @Summary Create Author
 // @Tags author
 // @Description Create a new author
 // @ID create-author
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
-// @Param   author  body    models.Author     true        "Author Info"
+// @Param   author  body    AuthorInput     true        "Author Info"
 // @Success 201 {object} map[string]string "status: author created"
+// @Failure 422 {object} ErrorResponse "name is not valid"
 // @Router /author [post]
 func (h *Handler) CreateAuthor(c *gin.Context) {
-	var input models.Author
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	var input AuthorInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	if err := h.Services.Authors.Create(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Authors.Create(c.Request.Context(), models.Author{Name: input.Name}); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -77,30 +174,72 @@ func (h *Handler) CreateAuthor(c *gin.Context) {
 
 // UpdateAuthor @Summary Update Author
 // @Tags author
-// @Description Update author details by ID
+// @Description Replace an author by ID. Use PATCH to change only some fields.
 // @ID update-author
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id      path    int     true        "Author ID"
-// @Param   author  body    models.Author     true        "Author Info"
+// @Param   author  body    AuthorInput     true        "Author Info"
 // @Success 200 {object} map[string]string "status: author updated"
+// @Failure 422 {object} ErrorResponse "name is not valid"
 // @Router /author/{id} [put]
 func (h *Handler) UpdateAuthor(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		badRequest(c, "invalid_id", "invalid author ID")
 		return
 	}
 
-	var input models.Author
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	var input AuthorInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	input.ID = id
-	if err := h.Services.Authors.Update(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Authors.Update(c.Request.Context(), models.Author{ID: id, Name: input.Name}); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "author updated"})
+}
+
+// PatchAuthor @Summary Patch Author
+// @Tags author
+// @Description Change some fields of an author by ID with a JSON merge patch (RFC 7386), or a JSON Patch (RFC 6902) sent as application/json-patch+json. The patched author is checked as on PUT.
+// @ID patch-author
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json,application/merge-patch+json,application/json-patch+json
+// @Produce  json
+// @Param   id      path    int     true        "Author ID"
+// @Param   patch   body    AuthorInput     true        "Fields to change"
+// @Success 200 {object} map[string]string "status: author updated"
+// @Failure 409 {object} ErrorResponse "a test operation of the patch failed"
+// @Failure 415 {object} ErrorResponse "body is not a patch"
+// @Failure 422 {object} ErrorResponse "patched name is not valid"
+// @Router /author/{id} [patch]
+func (h *Handler) PatchAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid author ID")
+		return
+	}
+
+	author, err := h.Services.Authors.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	var input AuthorInput
+	if !bindPatch(c, AuthorInput{Name: author.Name}, &input) {
+		return
+	}
+
+	if err := h.Services.Authors.Update(c.Request.Context(), models.Author{ID: id, Name: input.Name}); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -109,24 +248,85 @@ func (h *Handler) UpdateAuthor(c *gin.Context) {
 
 // DeleteAuthor @Summary Delete Author
 // @Tags author
-// @Description Delete author by ID
+// @Description Archive author by ID, together with the books they contributed to. An admin can restore them.
 // @ID delete-author
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "Author ID"
 // @Success 200 {object} map[string]string "status: author deleted"
+// @Failure 409 {object} ErrorResponse "a book of the author is out on loan or on hold"
 // @Router /author/{id} [delete]
 func (h *Handler) DeleteAuthor(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid author ID"})
+		badRequest(c, "invalid_id", "invalid author ID")
 		return
 	}
 
-	if err := h.Services.Authors.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Authors.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "author deleted"})
 }
+
+// GetDeletedAuthors @Summary Get Deleted Authors
+// @Tags author
+// @Description Get a page of archived authors, filtered and sorted as the author listing
+// @ID get-deleted-authors
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   name    query    string     false        "Part of the name, case-insensitive"
+// @Param   sort    query    string     false        "id or name, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Authors per page, at most 100"
+// @Success 200 {object} AuthorPageResponse
+// @Router /author/deleted [get]
+func (h *Handler) GetDeletedAuthors(c *gin.Context) {
+	query := models.AuthorQuery{Name: c.Query("name"), Deleted: true, Sort: c.Query("sort")}
+
+	var err error
+	if query.Page, query.PageSize, err = pageParams(c); err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+
+	authors, err := h.Services.Authors.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, authorPageResponse(c, authors))
+}
+
+// RestoreAuthor @Summary Restore Author
+// @Tags author
+// @Description Bring back an archived author by ID, with the books that were archived with them
+// @ID restore-author
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Author ID"
+// @Success 200 {object} map[string]string "status: author restored"
+// @Failure 404 {object} ErrorResponse "no archived author has this ID"
+// @Router /author/{id}/restore [post]
+func (h *Handler) RestoreAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid author ID")
+		return
+	}
+
+	if err := h.Services.Authors.Restore(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "author restored"})
+}
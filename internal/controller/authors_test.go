@@ -3,12 +3,12 @@ package controller_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"library/internal/controller"
 	"library/internal/service"
 	"library/models"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -37,7 +37,7 @@ func TestHandler_getAuthorByID(t *testing.T) {
 
 	expectedAuthor := models.Author{ID: 1, Name: "Author 1"}
 
-	mockAuthorService.EXPECT().GetByID(1).Return(expectedAuthor, nil)
+	mockAuthorService.EXPECT().GetByID(gomock.Any(), 1).Return(expectedAuthor, nil)
 
 	req, _ := http.NewRequest("GET", "/author/1", nil)
 	w := httptest.NewRecorder()
@@ -78,14 +78,14 @@ func TestHandler_getAuthorByID_NotFound(t *testing.T) {
 	r := setupRouter()
 	r.GET("/author/:id", handler.GetAuthorByID)
 
-	mockAuthorService.EXPECT().GetByID(1).Return(models.Author{}, errors.New("author not found"))
+	mockAuthorService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Author{}, &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "author not found"})
 
 	req, _ := http.NewRequest("GET", "/author/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "author not found")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","error":"author not found"}`, w.Body.String())
 }
 
 func TestHandler_getAllAuthors(t *testing.T) {
@@ -107,17 +107,19 @@ func TestHandler_getAllAuthors(t *testing.T) {
 		{ID: 2, Name: "Author 2"},
 	}
 
-	mockAuthorService.EXPECT().GetAll().Return(expectedAuthors, nil)
+	mockAuthorService.EXPECT().GetAll(gomock.Any(), models.AuthorQuery{}).
+		Return(models.AuthorPage{Authors: expectedAuthors, Total: 2, Page: 1, PageSize: 20}, nil)
 
 	req, _ := http.NewRequest("GET", "/author", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var authors []models.Author
-	err := json.Unmarshal(w.Body.Bytes(), &authors)
+	var page models.AuthorPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedAuthors, authors)
+	assert.Equal(t, expectedAuthors, page.Authors)
+	assert.Empty(t, page.Next)
 }
 
 func TestHandler_createAuthor(t *testing.T) {
@@ -135,7 +137,7 @@ func TestHandler_createAuthor(t *testing.T) {
 	r.POST("/author", handler.CreateAuthor)
 
 	newAuthor := models.Author{Name: "New Author"}
-	mockAuthorService.EXPECT().Create(newAuthor).Return(nil)
+	mockAuthorService.EXPECT().Create(gomock.Any(), newAuthor).Return(nil)
 
 	authorJSON, _ := json.Marshal(newAuthor)
 	req, _ := http.NewRequest("POST", "/author", bytes.NewBuffer(authorJSON))
@@ -162,6 +164,33 @@ func TestHandler_createAuthor_InvalidInput(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "invalid input")
 }
 
+func TestHandler_createAuthor_InvalidFields(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/author", handler.CreateAuthor)
+
+	tests := []struct {
+		body  string
+		field controller.FieldError
+	}{
+		{`{}`, controller.FieldError{Field: "name", Code: "required", Error: "is required"}},
+		{`{"name":" "}`, controller.FieldError{Field: "name", Code: "notblank", Error: "must not be blank"}},
+		{`{"name":"` + strings.Repeat("a", 201) + `"}`, controller.FieldError{Field: "name", Code: "max", Error: "must be at most 200 characters long"}},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("POST", "/author", bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response controller.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []controller.FieldError{tt.field}, response.Fields)
+	}
+}
+
 func TestHandler_updateAuthor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -177,7 +206,7 @@ func TestHandler_updateAuthor(t *testing.T) {
 	r.PUT("/author/:id", handler.UpdateAuthor)
 
 	updatedAuthor := models.Author{ID: 1, Name: "Updated Author"}
-	mockAuthorService.EXPECT().Update(updatedAuthor).Return(nil)
+	mockAuthorService.EXPECT().Update(gomock.Any(), updatedAuthor).Return(nil)
 
 	authorJSON, _ := json.Marshal(updatedAuthor)
 	req, _ := http.NewRequest("PUT", "/author/1", bytes.NewBuffer(authorJSON))
@@ -232,7 +261,7 @@ func TestHandler_deleteAuthor(t *testing.T) {
 	r := setupRouter()
 	r.DELETE("/author/:id", handler.DeleteAuthor)
 
-	mockAuthorService.EXPECT().Delete(1).Return(nil)
+	mockAuthorService.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/author/1", nil)
 	w := httptest.NewRecorder()
@@ -255,3 +284,34 @@ func TestHandler_deleteAuthor_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "invalid author ID")
 }
+
+func TestHandler_getAuthorBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthorService := service.NewMockAuthors(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Authors: mockAuthorService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/author/:id/books", handler.GetAuthorBooks)
+
+	expectedCredits := []models.BookContributor{
+		{BookID: 1, AuthorID: 1, Role: models.RoleAuthor, Position: 1, Book: models.Book{ID: 1, Title: "Book 1"}},
+		{BookID: 2, AuthorID: 1, Role: models.RoleEditor, Position: 3, Book: models.Book{ID: 2, Title: "Book 2"}},
+	}
+	mockAuthorService.EXPECT().GetBooks(gomock.Any(), 1).Return(expectedCredits, nil)
+
+	req, _ := http.NewRequest("GET", "/author/1/books", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"book_id": 1, "title": "Book 1", "role": "author", "position": 1},
+		{"book_id": 2, "title": "Book 2", "role": "editor", "position": 3}
+	]`, w.Body.String())
+}
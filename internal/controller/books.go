@@ -3,73 +3,262 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"library/internal/policy"
 	"library/models"
 )
 
+// BookInput is the body of a book creation or update. Contributors are
+// credited in the order given, subjects are given by ID and tags by name.
+type BookInput struct {
+	Title        string             `json:"title" binding:"required,notblank,max=500"`
+	ISBN         string             `json:"isbn" binding:"required"`
+	PublishedAt  time.Time          `json:"published_at" binding:"required,notfuture"`
+	Contributors []ContributorInput `json:"contributors" binding:"required,min=1,max=50,dive"`
+	SubjectIDs   []int              `json:"subject_ids" binding:"max=50,dive,min=1"`
+	Tags         []string           `json:"tags" binding:"max=50"`
+}
+
+// ContributorInput credits an author on a book. Role defaults to author.
+type ContributorInput struct {
+	AuthorID int    `json:"author_id" binding:"required,min=1"`
+	Role     string `json:"role" binding:"omitempty,oneof=author editor translator illustrator"`
+}
+
+func (input BookInput) book(id int) models.Book {
+	book := models.Book{ID: id, Title: input.Title, ISBN: input.ISBN, PublishedAt: input.PublishedAt}
+	for _, contributor := range input.Contributors {
+		book.Contributors = append(book.Contributors, models.BookContributor{AuthorID: contributor.AuthorID, Role: contributor.Role})
+	}
+	for _, subjectID := range input.SubjectIDs {
+		book.Subjects = append(book.Subjects, models.Subject{ID: subjectID})
+	}
+	for _, tag := range input.Tags {
+		book.Tags = append(book.Tags, models.Tag{Name: tag})
+	}
+	return book
+}
+
+// bookInput is book as the body of a PUT would send it.
+func bookInput(book models.Book) BookInput {
+	input := BookInput{
+		Title:        book.Title,
+		ISBN:         book.ISBN,
+		PublishedAt:  book.PublishedAt,
+		Contributors: make([]ContributorInput, 0, len(book.Contributors)),
+		SubjectIDs:   make([]int, 0, len(book.Subjects)),
+		Tags:         make([]string, 0, len(book.Tags)),
+	}
+	for _, contributor := range book.Contributors {
+		input.Contributors = append(input.Contributors, ContributorInput{AuthorID: contributor.AuthorID, Role: contributor.Role})
+	}
+	for _, subject := range book.Subjects {
+		input.SubjectIDs = append(input.SubjectIDs, subject.ID)
+	}
+	for _, tag := range book.Tags {
+		input.Tags = append(input.Tags, tag.Name)
+	}
+	return input
+}
+
+// BookResponse is a book as the API shows it. Copies are only listed for a
+// single book.
+type BookResponse struct {
+	ID              int                   `json:"id"`
+	Title           string                `json:"title"`
+	ISBN            string                `json:"isbn"`
+	PublishedAt     time.Time             `json:"published_at"`
+	AvailableCopies int                   `json:"available_copies"`
+	Contributors    []ContributorResponse `json:"contributors"`
+	Subjects        []SubjectResponse     `json:"subjects"`
+	Tags            []string              `json:"tags"`
+	Copies          []CopyResponse        `json:"copies,omitempty"`
+	DeletedAt       *time.Time            `json:"deleted_at,omitempty"`
+}
+
+// ContributorResponse is an author credited on a book.
+type ContributorResponse struct {
+	AuthorID int    `json:"author_id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+}
+
+// SubjectResponse is a subject a book is filed under.
+type SubjectResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func bookResponse(book models.Book) BookResponse {
+	response := BookResponse{
+		ID:              book.ID,
+		Title:           book.Title,
+		ISBN:            book.ISBN,
+		PublishedAt:     book.PublishedAt,
+		AvailableCopies: book.AvailableCopies,
+		DeletedAt:       book.DeletedAt,
+		Contributors:    contributorResponses(book.Contributors),
+		Subjects:        make([]SubjectResponse, 0, len(book.Subjects)),
+		Tags:            make([]string, 0, len(book.Tags)),
+	}
+	for _, subject := range book.Subjects {
+		response.Subjects = append(response.Subjects, SubjectResponse{ID: subject.ID, Name: subject.Name})
+	}
+	for _, tag := range book.Tags {
+		response.Tags = append(response.Tags, tag.Name)
+	}
+	for _, bookCopy := range book.Copies {
+		response.Copies = append(response.Copies, copyResponse(bookCopy))
+	}
+	return response
+}
+
+func contributorResponses(contributors []models.BookContributor) []ContributorResponse {
+	responses := make([]ContributorResponse, 0, len(contributors))
+	for _, contributor := range contributors {
+		responses = append(responses, ContributorResponse{
+			AuthorID: contributor.AuthorID,
+			Name:     contributor.Author.Name,
+			Role:     contributor.Role,
+		})
+	}
+	return responses
+}
+
+// BookPageResponse is a page of the book listing. Next links to the
+// following page and is empty on the last one.
+type BookPageResponse struct {
+	Books    []BookResponse `json:"books"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Next     string         `json:"next"`
+}
+
+func bookPageResponse(c *gin.Context, books models.BookPage) BookPageResponse {
+	response := BookPageResponse{
+		Books:    make([]BookResponse, 0, len(books.Books)),
+		Total:    books.Total,
+		Page:     books.Page,
+		PageSize: books.PageSize,
+		Next:     nextPage(c, books.Page, books.PageSize, books.Total),
+	}
+	for _, book := range books.Books {
+		response.Books = append(response.Books, bookResponse(book))
+	}
+	return response
+}
+
 // GetBookByID @Summary Get Book by ID
 // @Tags books
-// @Description Get book details by ID
+// @Description Get book details by ID, with the ISBN hyphenated where the ranges of its publisher are known
 // @ID get-book-by-id
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "Book ID"
-// @Success 200 {object} models.Book
+// @Success 200 {object} BookResponse
 // @Router /book/{id} [get]
 func (h *Handler) GetBookByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		badRequest(c, "invalid_id", "invalid book ID")
 		return
 	}
 
-	book, err := h.Services.Books.GetByID(id)
+	book, err := h.Services.Books.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		errorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, book)
+	c.JSON(http.StatusOK, bookResponse(book))
 }
 
 // GetAllBooks @Summary Get All Books
 // @Tags books
-// @Description Get list of all books
+// @Description Get a page of books, optionally filtered and sorted
 // @ID get-all-books
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} models.Book
+// @Param   title    query    string     false        "Part of the title, case-insensitive"
+// @Param   author_id    query    int     false        "Only books this author contributed to"
+// @Param   subject_id    query    int     false        "Only books filed under this subject or a subject below it"
+// @Param   tag    query    string     false        "Only books with this tag"
+// @Param   published_from    query    string     false        "Published on or after, YYYY-MM-DD"
+// @Param   published_to    query    string     false        "Published on or before, YYYY-MM-DD"
+// @Param   available    query    bool     false        "Only books with (true) or without (false) a free copy"
+// @Param   sort    query    string     false        "id, title or published_at, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Books per page, at most 100"
+// @Success 200 {object} BookPageResponse
 // @Failure 500 {object} map[string]string "internal server error"
 // @Router /book [get]
 func (h *Handler) GetAllBooks(c *gin.Context) {
-	books, err := h.Services.Books.GetAll()
+	query, err := bookQuery(c)
+	if err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+
+	books, err := h.Services.Books.GetAll(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		errorResponse(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, bookPageResponse(c, books))
+}
 
-	c.JSON(http.StatusOK, books)
+// bookQuery reads the filter, sort and paging parameters of the book listing.
+func bookQuery(c *gin.Context) (models.BookQuery, error) {
+	query := models.BookQuery{Title: c.Query("title"), Tag: c.Query("tag"), Sort: c.Query("sort")}
+
+	var err error
+	if query.AuthorID, err = intParam(c, "author_id"); err != nil {
+		return query, err
+	}
+	if query.SubjectID, err = intParam(c, "subject_id"); err != nil {
+		return query, err
+	}
+	if query.PublishedFrom, err = dateParam(c, "published_from"); err != nil {
+		return query, err
+	}
+	if query.PublishedTo, err = dateParam(c, "published_to"); err != nil {
+		return query, err
+	}
+	if query.PublishedTo != nil {
+		// the whole day is included
+		endOfDay := query.PublishedTo.Add(24*time.Hour - time.Nanosecond)
+		query.PublishedTo = &endOfDay
+	}
+	if query.Available, err = boolParam(c, "available"); err != nil {
+		return query, err
+	}
+	query.Page, query.PageSize, err = pageParams(c)
+	return query, err
 }
 
 // CreateBook @Summary Create Book
 // @Tags books
-// @Description Create a new book
+// @Description Create a new book. The ISBN may be an ISBN-10 or ISBN-13, with or without hyphens, and is stored as an ISBN-13. Contributors are credited in the order given; a contributor without a role is an author. Subjects are given by ID, tags by name and are created as needed.
 // @ID create-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
-// @Param   book  body    models.Book     true        "Book Info"
+// @Param   book  body    BookInput     true        "Book Info"
 // @Success 201 {object} map[string]string "status: book created"
+// @Failure 422 {object} ErrorResponse "fields, ISBN, contributors, subjects or tags are not valid"
 // @Router /book [post]
 func (h *Handler) CreateBook(c *gin.Context) {
-	var input models.Book
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	var input BookInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	if err := h.Services.Books.Create(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Books.Create(c.Request.Context(), input.book(0)); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -78,30 +267,72 @@ func (h *Handler) CreateBook(c *gin.Context) {
 
 // UpdateBook @Summary Update Book
 // @Tags books
-// @Description Update book details by ID
+// @Description Replace a book by ID. Every field is required, as on create; use PATCH to change only some. The ISBN is checked and stored as on create. The contributors, subjects and tags given replace the book's current ones.
 // @ID update-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id      path    int     true        "Book ID"
-// @Param   book    body    models.Book     true        "Book Info"
+// @Param   book    body    BookInput     true        "Book Info"
 // @Success 200 {object} map[string]string "status: book updated"
+// @Failure 422 {object} ErrorResponse "fields, ISBN, contributors, subjects or tags are not valid"
 // @Router /book/{id} [put]
 func (h *Handler) UpdateBook(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		badRequest(c, "invalid_id", "invalid book ID")
 		return
 	}
 
-	var input models.Book
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	var input BookInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	input.ID = id
-	if err := h.Services.Books.Update(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Books.Update(c.Request.Context(), input.book(id)); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "book updated"})
+}
+
+// PatchBook @Summary Patch Book
+// @Tags books
+// @Description Change some fields of a book by ID with a JSON merge patch (RFC 7386), or a JSON Patch (RFC 6902) sent as application/json-patch+json. The patched book is checked as on PUT.
+// @ID patch-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json,application/merge-patch+json,application/json-patch+json
+// @Produce  json
+// @Param   id      path    int     true        "Book ID"
+// @Param   patch   body    BookInput     true        "Fields to change"
+// @Success 200 {object} map[string]string "status: book updated"
+// @Failure 409 {object} ErrorResponse "a test operation of the patch failed"
+// @Failure 415 {object} ErrorResponse "body is not a patch"
+// @Failure 422 {object} ErrorResponse "patched fields, ISBN, contributors, subjects or tags are not valid"
+// @Router /book/{id} [patch]
+func (h *Handler) PatchBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	book, err := h.Services.Books.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	var input BookInput
+	if !bindPatch(c, bookInput(book), &input) {
+		return
+	}
+
+	if err := h.Services.Books.Update(c.Request.Context(), input.book(id)); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -110,51 +341,125 @@ func (h *Handler) UpdateBook(c *gin.Context) {
 
 // DeleteBook @Summary Delete Book
 // @Tags books
-// @Description Delete book by ID
+// @Description Archive book by ID. It is no longer listed, found or lent, but its copies, loans and holds are kept and an admin can restore it.
 // @ID delete-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "Book ID"
 // @Success 200 {object} map[string]string "status: book deleted"
+// @Failure 409 {object} ErrorResponse "book is out on loan or on hold"
 // @Router /book/{id} [delete]
 func (h *Handler) DeleteBook(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book ID"})
+		badRequest(c, "invalid_id", "invalid book ID")
 		return
 	}
 
-	if err := h.Services.Books.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Books.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "book deleted"})
 }
 
+// GetDeletedBooks @Summary Get Deleted Books
+// @Tags books
+// @Description Get a page of archived books, filtered and sorted as the book listing
+// @ID get-deleted-books
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   title    query    string     false        "Part of the title, case-insensitive"
+// @Param   sort    query    string     false        "id, title or published_at, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Books per page, at most 100"
+// @Success 200 {object} BookPageResponse
+// @Router /book/deleted [get]
+func (h *Handler) GetDeletedBooks(c *gin.Context) {
+	query, err := bookQuery(c)
+	if err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+	query.Deleted = true
+
+	books, err := h.Services.Books.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, bookPageResponse(c, books))
+}
+
+// RestoreBook @Summary Restore Book
+// @Tags books
+// @Description Bring back an archived book by ID
+// @ID restore-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Book ID"
+// @Success 200 {object} map[string]string "status: book restored"
+// @Failure 404 {object} ErrorResponse "no archived book has this ID"
+// @Failure 409 {object} ErrorResponse "an author of the book is archived"
+// @Router /book/{id}/restore [post]
+func (h *Handler) RestoreBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	if err := h.Services.Books.Restore(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "book restored"})
+}
+
+// Input names a book, or a copy of it, that is rented, returned, renewed or
+// held. It is for the caller unless UserID names someone else, which only
+// staff may do.
 type Input struct {
-	UserID int `json:"user_id"`
-	BookID int `json:"book_id"`
+	UserID int `json:"user_id" binding:"omitempty,min=1"`
+	BookID int `json:"book_id" binding:"required,min=1"`
+	CopyID int `json:"copy_id" binding:"omitempty,min=1"`
 }
 
 // RentBook @Summary Rent Book
 // @Tags books
-// @Description Rent a book
+// @Description Rent a book for the authenticated user, or for user_id if the caller is staff. Callers with an API key must give user_id. A specific copy of the book can be chosen with copy_id, otherwise any free copy is lent
 // @ID rent-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   rent  body    Input    true        "Rent Info"
 // @Success 200 {object} map[string]string "status: book rented"
+// @Failure 401 {object} ErrorResponse "no valid access token"
+// @Failure 403 {object} ErrorResponse "renting for someone else without being staff"
+// @Failure 409 {object} ErrorResponse "user is over the loan limit of their policy or owes too much in fines"
 // @Router /rent [post]
 func (h *Handler) RentBook(c *gin.Context) {
 	var input Input
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, ok := actingFor(c, input.UserID, policy.LoansWrite)
+	if !ok {
 		return
 	}
 
-	if err := h.Services.Books.RentBook(input.UserID, input.BookID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Books.RentBook(c.Request.Context(), userID, input.BookID, input.CopyID); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -163,8 +468,10 @@ func (h *Handler) RentBook(c *gin.Context) {
 
 // ReturnBook @Summary Return Book
 // @Tags books
-// @Description Return a rented book
+// @Description Return a book rented by the authenticated user, or by user_id if the caller is staff. Callers with an API key must give user_id. The loan is identified by book_id, and by copy_id as well if given
 // @ID return-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   return  body    Input     true        "Return Info"
@@ -172,15 +479,76 @@ func (h *Handler) RentBook(c *gin.Context) {
 // @Router /rent/return [post]
 func (h *Handler) ReturnBook(c *gin.Context) {
 	var input Input
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, ok := actingFor(c, input.UserID, policy.LoansWrite)
+	if !ok {
 		return
 	}
 
-	if err := h.Services.Books.ReturnBook(input.UserID, input.BookID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Books.ReturnBook(c.Request.Context(), userID, input.BookID, input.CopyID); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "book returned"})
 }
+
+// RenewBook @Summary Renew Book
+// @Tags books
+// @Description Extend the due date of a book rented by the authenticated user, or by user_id if the caller is staff (required with an API key). The loan is identified by book_id, and by copy_id as well if given. Overdue loans cannot be renewed; they have to be returned
+// @ID renew-book
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   renew  body    Input     true        "Renew Info"
+// @Success 200 {object} map[string]interface{} "status: book renewed, due_at: new due date"
+// @Failure 409 {object} ErrorResponse "the loan is overdue, at its renewal limit or the book is on hold"
+// @Router /rent/renew [post]
+func (h *Handler) RenewBook(c *gin.Context) {
+	var input Input
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, ok := actingFor(c, input.UserID, policy.LoansWrite)
+	if !ok {
+		return
+	}
+
+	rentedBook, err := h.Services.Books.RenewBook(c.Request.Context(), userID, input.BookID, input.CopyID)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "book renewed", "due_at": rentedBook.DueAt})
+}
+
+// GetOverdueRentals @Summary Get Overdue Rentals
+// @Tags books
+// @Description Get list of open rentals past their due date
+// @ID get-overdue-rentals
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} LoanResponse
+// @Failure 500 {object} map[string]string "internal server error"
+// @Router /rent/overdue [get]
+func (h *Handler) GetOverdueRentals(c *gin.Context) {
+	rentedBooks, err := h.Services.Books.GetOverdue(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	response := make([]LoanResponse, 0, len(rentedBooks))
+	for _, rentedBook := range rentedBooks {
+		response = append(response, loanResponse(rentedBook))
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -35,19 +36,37 @@ func TestHandler_getBookByID(t *testing.T) {
 	r := setupRout()
 	r.GET("/book/:id", handler.GetBookByID)
 
-	expectedBook := models.Book{ID: 1, Title: "Book 1", AuthorID: 1}
+	book := models.Book{
+		ID:           1,
+		Title:        "Book 1",
+		ISBN:         "978-0-306-40615-7",
+		PublishedAt:  time.Date(1999, 5, 1, 0, 0, 0, 0, time.UTC),
+		Contributors: []models.BookContributor{{BookID: 1, AuthorID: 1, Role: models.RoleAuthor, Position: 1, Author: models.Author{ID: 1, Name: "Author 1"}}},
+		Subjects:     []models.Subject{{ID: 2, Name: "Fiction"}},
+		Tags:         []models.Tag{{ID: 3, Name: "classic"}},
+		Copies:       []models.BookCopy{{ID: 4, BookID: 1, Barcode: "B-0004", ShelfLocation: "A1", Condition: models.CopyConditionGood}},
+		RentedBooks:  []models.RentedBook{{ID: 5, UserID: 6, BookID: 1}},
+	}
 
-	mockBookService.EXPECT().GetByID(1).Return(expectedBook, nil)
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(book, nil)
 
 	req, _ := http.NewRequest("GET", "/book/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var book models.Book
-	err := json.Unmarshal(w.Body.Bytes(), &book)
-	assert.NoError(t, err)
-	assert.Equal(t, expectedBook, book)
+	// loans of the book are not shown
+	assert.JSONEq(t, `{
+		"id": 1,
+		"title": "Book 1",
+		"isbn": "978-0-306-40615-7",
+		"published_at": "1999-05-01T00:00:00Z",
+		"available_copies": 0,
+		"contributors": [{"author_id": 1, "name": "Author 1", "role": "author"}],
+		"subjects": [{"id": 2, "name": "Fiction"}],
+		"tags": ["classic"],
+		"copies": [{"id": 4, "book_id": 1, "barcode": "B-0004", "shelf_location": "A1", "condition": "good"}]
+	}`, w.Body.String())
 }
 
 func TestHandler_getBookByID_InvalidID(t *testing.T) {
@@ -78,14 +97,14 @@ func TestHandler_getBookByID_NotFound(t *testing.T) {
 	r := setupRout()
 	r.GET("/book/:id", handler.GetBookByID)
 
-	mockBookService.EXPECT().GetByID(1).Return(models.Book{}, errors.New("book not found"))
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Book{}, &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "book not found"})
 
 	req, _ := http.NewRequest("GET", "/book/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "book not found")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","error":"book not found"}`, w.Body.String())
 }
 
 func TestHandler_getAllBooks(t *testing.T) {
@@ -103,21 +122,26 @@ func TestHandler_getAllBooks(t *testing.T) {
 	r.GET("/book", handler.GetAllBooks)
 
 	expectedBooks := []models.Book{
-		{ID: 1, Title: "Book 1", AuthorID: 1},
-		{ID: 2, Title: "Book 2", AuthorID: 2},
+		{ID: 1, Title: "Book 1", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}},
+		{ID: 2, Title: "Book 2", Contributors: []models.BookContributor{{AuthorID: 2, Role: models.RoleAuthor, Position: 1}}},
 	}
 
-	mockBookService.EXPECT().GetAll().Return(expectedBooks, nil)
+	mockBookService.EXPECT().GetAll(gomock.Any(), models.BookQuery{}).
+		Return(models.BookPage{Books: expectedBooks, Total: 2, Page: 1, PageSize: 20}, nil)
 
 	req, _ := http.NewRequest("GET", "/book", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var books []models.Book
-	err := json.Unmarshal(w.Body.Bytes(), &books)
+	var page controller.BookPageResponse
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedBooks, books)
+	assert.Len(t, page.Books, 2)
+	assert.Equal(t, "Book 2", page.Books[1].Title)
+	assert.Equal(t, []controller.ContributorResponse{{AuthorID: 2, Role: models.RoleAuthor}}, page.Books[1].Contributors)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Empty(t, page.Next)
 }
 
 func TestHandler_createBook(t *testing.T) {
@@ -134,11 +158,28 @@ func TestHandler_createBook(t *testing.T) {
 	r := setupRout()
 	r.POST("/book", handler.CreateBook)
 
-	newBook := models.Book{Title: "New Book", AuthorID: 1}
-	mockBookService.EXPECT().Create(newBook).Return(nil)
-
-	bookJSON, _ := json.Marshal(newBook)
-	req, _ := http.NewRequest("POST", "/book", bytes.NewBuffer(bookJSON))
+	newBook := models.Book{
+		Title:        "New Book",
+		ISBN:         "0-306-40615-2",
+		PublishedAt:  time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+		Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor}, {AuthorID: 2}},
+		Subjects:     []models.Subject{{ID: 4}},
+		Tags:         []models.Tag{{Name: "Classic"}},
+	}
+	mockBookService.EXPECT().Create(gomock.Any(), newBook).Return(nil)
+
+	// fields that are not part of the input, like IDs and loans, are ignored
+	body := `{
+		"id": 9,
+		"title": "New Book",
+		"isbn": "0-306-40615-2",
+		"published_at": "2001-02-03T00:00:00Z",
+		"contributors": [{"author_id": 1, "role": "author"}, {"author_id": 2}],
+		"subject_ids": [4],
+		"tags": ["Classic"],
+		"RentedBooks": [{"UserID": 1}]
+	}`
+	req, _ := http.NewRequest("POST", "/book", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -147,6 +188,30 @@ func TestHandler_createBook(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "book created")
 }
 
+func TestHandler_createBook_InvalidFields(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRout()
+	r.POST("/book", handler.CreateBook)
+
+	body := `{"title":"  ","isbn":"9780306406157","published_at":"2999-01-01T00:00:00Z","contributors":[{"author_id":1,"role":"narrator"}]}`
+	req, _ := http.NewRequest("POST", "/book", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"code": "validation_failed",
+		"error": "title: must not be blank",
+		"fields": [
+			{"field": "title", "code": "notblank", "error": "must not be blank"},
+			{"field": "published_at", "code": "notfuture", "error": "must not be in the future"},
+			{"field": "contributors[0].role", "code": "oneof", "error": "must be one of author, editor, translator, illustrator"}
+		]
+	}`, w.Body.String())
+}
+
 func TestHandler_createBook_InvalidInput(t *testing.T) {
 	handler := &controller.Handler{}
 
@@ -176,11 +241,17 @@ func TestHandler_updateBook(t *testing.T) {
 	r := setupRout()
 	r.PUT("/book/:id", handler.UpdateBook)
 
-	updatedBook := models.Book{ID: 1, Title: "Updated Book", AuthorID: 1}
-	mockBookService.EXPECT().Update(updatedBook).Return(nil)
+	updatedBook := models.Book{
+		ID:           1,
+		Title:        "Updated Book",
+		ISBN:         "9780306406157",
+		PublishedAt:  time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+		Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor}},
+	}
+	mockBookService.EXPECT().Update(gomock.Any(), updatedBook).Return(nil)
 
-	bookJSON, _ := json.Marshal(updatedBook)
-	req, _ := http.NewRequest("PUT", "/book/1", bytes.NewBuffer(bookJSON))
+	body := `{"title":"Updated Book","isbn":"9780306406157","published_at":"2001-02-03T00:00:00Z","contributors":[{"author_id":1,"role":"author"}]}`
+	req, _ := http.NewRequest("PUT", "/book/1", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -195,7 +266,7 @@ func TestHandler_updateBook_InvalidID(t *testing.T) {
 	r := setupRouter()
 	r.PUT("/book/:id", handler.UpdateBook)
 
-	updatedBook := models.Book{Title: "Updated Book", AuthorID: 1}
+	updatedBook := models.Book{Title: "Updated Book", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}}
 	bookJSON, _ := json.Marshal(updatedBook)
 	req, _ := http.NewRequest("PUT", "/book/invalid", bytes.NewBuffer(bookJSON))
 	req.Header.Set("Content-Type", "application/json")
@@ -237,7 +308,7 @@ func TestHandler_deleteBook(t *testing.T) {
 	r := setupRouter()
 	r.DELETE("/book/:id", handler.DeleteBook)
 
-	mockBookService.EXPECT().Delete(1).Return(nil)
+	mockBookService.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/book/1", nil)
 	w := httptest.NewRecorder()
@@ -277,17 +348,72 @@ func TestHandler_deleteBook_NotFound(t *testing.T) {
 	r := setupRouter()
 	r.DELETE("/book/:id", handler.DeleteBook)
 
-	mockBookService.EXPECT().Delete(1).Return(errors.New("book not found"))
+	mockBookService.EXPECT().Delete(gomock.Any(), 1).Return(&service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "book not found"})
 
 	req, _ := http.NewRequest("DELETE", "/book/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 	assert.Contains(t, w.Body.String(), "book not found")
 
 }
 
+func TestHandler_getDeletedBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/book/deleted", handler.GetDeletedBooks)
+
+	deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockBookService.EXPECT().GetAll(gomock.Any(), models.BookQuery{Title: "war", Deleted: true}).
+		Return(models.BookPage{Books: []models.Book{{ID: 1, Title: "War and Peace", DeletedAt: &deletedAt}}, Total: 1, Page: 1, PageSize: 20}, nil)
+
+	req, _ := http.NewRequest("GET", "/book/deleted?title=war", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page controller.BookPageResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	if assert.Len(t, page.Books, 1) {
+		assert.Equal(t, &deletedAt, page.Books[0].DeletedAt)
+	}
+}
+
+func TestHandler_restoreBook_AuthorArchived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/book/:id/restore", handler.RestoreBook)
+
+	mockBookService.EXPECT().Restore(gomock.Any(), 1).
+		Return(&service.Error{Kind: service.ErrConflict, Code: "author_archived", Message: "an author of the book is archived"})
+
+	req, _ := http.NewRequest("POST", "/book/1/restore", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "author_archived")
+}
+
 func TestHandler_rentBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -300,14 +426,15 @@ func TestHandler_rentBook(t *testing.T) {
 	}
 
 	r := setupRouter()
-	r.POST("/rent", handler.RentBook)
+	r.POST("/rent", signedIn(ctrl, handler, 1), handler.RentBook)
 
-	rentInfo := controller.Input{UserID: 1, BookID: 1}
-	mockBookService.EXPECT().RentBook(rentInfo.UserID, rentInfo.BookID).Return(nil)
+	rentInfo := controller.Input{BookID: 1}
+	mockBookService.EXPECT().RentBook(gomock.Any(), 1, rentInfo.BookID, 0).Return(nil)
 
 	rentJSON, _ := json.Marshal(rentInfo)
 	req, _ := http.NewRequest("POST", "/rent", bytes.NewBuffer(rentJSON))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -332,6 +459,123 @@ func TestHandler_rentBook_InvalidInput(t *testing.T) {
 
 }
 
+func TestHandler_rentBook_OverLoanLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", signedIn(ctrl, handler, 1), handler.RentBook)
+
+	rentInfo := controller.Input{BookID: 1}
+	mockBookService.EXPECT().RentBook(gomock.Any(), 1, rentInfo.BookID, 0).
+		Return(&service.LoanLimitError{UserID: 1, Policy: "student", Limit: 3, Loans: 3})
+
+	rentJSON, _ := json.Marshal(rentInfo)
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBuffer(rentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{
+		"code": "loan_limit_reached",
+		"error": "user 1 already has 3 of 3 loans allowed by the \"student\" policy",
+		"policy": "student",
+		"limit": 3,
+		"loans": 3
+	}`, w.Body.String())
+}
+
+func TestHandler_rentBook_FinesOwed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", signedIn(ctrl, handler, 1), handler.RentBook)
+
+	rentInfo := controller.Input{BookID: 1}
+	mockBookService.EXPECT().RentBook(gomock.Any(), 1, rentInfo.BookID, 0).
+		Return(&service.FinesOwedError{UserID: 1, Balance: 750, Threshold: 500})
+
+	rentJSON, _ := json.Marshal(rentInfo)
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBuffer(rentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{
+		"code": "fines_owed",
+		"error": "user 1 owes 7.50 in fines, more than the 5.00 allowed to rent",
+		"balance": 750,
+		"threshold": 500
+	}`, w.Body.String())
+}
+
+func TestHandler_rentBook_Copy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent", signedIn(ctrl, handler, 1), handler.RentBook)
+
+	rentInfo := controller.Input{BookID: 1, CopyID: 3}
+	mockBookService.EXPECT().RentBook(gomock.Any(), 1, rentInfo.BookID, rentInfo.CopyID).Return(nil)
+
+	rentJSON, _ := json.Marshal(rentInfo)
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBuffer(rentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "book rented")
+}
+
+func TestHandler_rentBook_MissingBook(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/rent", handler.RentBook)
+
+	rentJSON, _ := json.Marshal(controller.Input{CopyID: 3})
+	req, _ := http.NewRequest("POST", "/rent", bytes.NewBuffer(rentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"code": "validation_failed",
+		"error": "book_id: is required",
+		"fields": [{"field": "book_id", "code": "required", "error": "is required"}]
+	}`, w.Body.String())
+}
+
 func TestHandler_returnBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -344,14 +588,15 @@ func TestHandler_returnBook(t *testing.T) {
 	}
 
 	r := setupRouter()
-	r.POST("/rent/return", handler.ReturnBook)
+	r.POST("/rent/return", signedInAs(ctrl, handler, models.Identity{UserID: 9, Role: models.RoleLibrarian}), handler.ReturnBook)
 
 	returnInfo := controller.Input{UserID: 1, BookID: 1}
-	mockBookService.EXPECT().ReturnBook(returnInfo.UserID, returnInfo.BookID).Return(nil)
+	mockBookService.EXPECT().ReturnBook(gomock.Any(), returnInfo.UserID, returnInfo.BookID, 0).Return(nil)
 
 	returnJSON, _ := json.Marshal(returnInfo)
 	req, _ := http.NewRequest("POST", "/rent/return", bytes.NewBuffer(returnJSON))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -374,3 +619,190 @@ func TestHandler_returnBook_InvalidInput(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "invalid input")
 }
+
+func TestHandler_renewBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent/renew", signedIn(ctrl, handler, 1), handler.RenewBook)
+
+	renewInfo := controller.Input{BookID: 1}
+	dueAt := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+	mockBookService.EXPECT().RenewBook(gomock.Any(), 1, renewInfo.BookID, renewInfo.CopyID).
+		Return(models.RentedBook{ID: 1, UserID: 1, BookID: 1, DueAt: dueAt, RenewalCount: 1}, nil)
+
+	renewJSON, _ := json.Marshal(renewInfo)
+	req, _ := http.NewRequest("POST", "/rent/renew", bytes.NewBuffer(renewJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "book renewed")
+	assert.Contains(t, w.Body.String(), "2024-05-15T00:00:00Z")
+}
+
+func TestHandler_renewBook_LimitReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/rent/renew", signedIn(ctrl, handler, 1), handler.RenewBook)
+
+	renewInfo := controller.Input{BookID: 1}
+	mockBookService.EXPECT().RenewBook(gomock.Any(), 1, renewInfo.BookID, renewInfo.CopyID).
+		Return(models.RentedBook{}, &service.Error{Kind: service.ErrConflict, Code: "renewal_limit_reached", Message: "renewal limit reached: 2 renewals allowed"})
+
+	renewJSON, _ := json.Marshal(renewInfo)
+	req, _ := http.NewRequest("POST", "/rent/renew", bytes.NewBuffer(renewJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "renewal_limit_reached")
+}
+
+func TestHandler_renewBook_InvalidInput(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/rent/renew", handler.RenewBook)
+
+	req, _ := http.NewRequest("POST", "/rent/renew", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid input")
+}
+
+func TestHandler_getOverdueRentals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/rent/overdue", handler.GetOverdueRentals)
+
+	dueAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	expectedRentals := []models.RentedBook{
+		{ID: 1, UserID: 1, BookID: 2, DueAt: dueAt, User: models.User{ID: 1, Name: "User 1"}, Book: models.Book{ID: 2, Title: "Book 2"}},
+	}
+
+	mockBookService.EXPECT().GetOverdue(gomock.Any()).Return(expectedRentals, nil)
+
+	req, _ := http.NewRequest("GET", "/rent/overdue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var rentals []controller.LoanResponse
+	err := json.Unmarshal(w.Body.Bytes(), &rentals)
+	assert.NoError(t, err)
+	assert.Equal(t, []controller.LoanResponse{{ID: 1, UserID: 1, UserName: "User 1", BookID: 2, Title: "Book 2", DueAt: dueAt}}, rentals)
+}
+
+func TestHandler_getOverdueRentals_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/rent/overdue", handler.GetOverdueRentals)
+
+	mockBookService.EXPECT().GetOverdue(gomock.Any()).Return(nil, errors.New("db error"))
+
+	req, _ := http.NewRequest("GET", "/rent/overdue", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code":"internal_error","error":"internal server error"}`, w.Body.String())
+}
+
+func TestHandler_getAllBooks_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/book", handler.GetAllBooks)
+
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2010, 12, 31, 0, 0, 0, 0, time.UTC).Add(24*time.Hour - time.Nanosecond)
+	available := true
+	query := models.BookQuery{
+		Title:         "war",
+		AuthorID:      3,
+		PublishedFrom: &from,
+		PublishedTo:   &to,
+		Available:     &available,
+		Sort:          "-title",
+		Page:          2,
+		PageSize:      10,
+	}
+	mockBookService.EXPECT().GetAll(gomock.Any(), query).
+		Return(models.BookPage{Books: []models.Book{{ID: 11, Title: "War"}}, Total: 35, Page: 2, PageSize: 10}, nil)
+
+	req, _ := http.NewRequest("GET", "/book?title=war&author_id=3&published_from=2000-01-01&published_to=2010-12-31&available=true&sort=-title&page=2&page_size=10", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page models.BookPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(35), page.Total)
+	assert.Equal(t, "/book?author_id=3&available=true&page=3&page_size=10&published_from=2000-01-01&published_to=2010-12-31&sort=-title&title=war", page.Next)
+}
+
+func TestHandler_getAllBooks_InvalidQuery(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.GET("/book", handler.GetAllBooks)
+
+	req, _ := http.NewRequest("GET", "/book?published_from=01.01.2000", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"invalid_query","error":"invalid published_from, expected YYYY-MM-DD"}`, w.Body.String())
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// CopyResponse is a physical copy of a book.
+type CopyResponse struct {
+	ID            int    `json:"id"`
+	BookID        int    `json:"book_id"`
+	Barcode       string `json:"barcode"`
+	ShelfLocation string `json:"shelf_location"`
+	Condition     string `json:"condition"`
+}
+
+func copyResponse(bookCopy models.BookCopy) CopyResponse {
+	return CopyResponse{
+		ID:            bookCopy.ID,
+		BookID:        bookCopy.BookID,
+		Barcode:       bookCopy.Barcode,
+		ShelfLocation: bookCopy.ShelfLocation,
+		Condition:     bookCopy.Condition,
+	}
+}
+
+// GetBookCopies @Summary Get Book Copies
+// @Tags copies
+// @Description Get physical copies of a book
+// @ID get-book-copies
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Book ID"
+// @Success 200 {array} CopyResponse
+// @Router /book/{id}/copies [get]
+func (h *Handler) GetBookCopies(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	copies, err := h.Services.Copies.GetByBook(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	response := make([]CopyResponse, 0, len(copies))
+	for _, bookCopy := range copies {
+		response = append(response, copyResponse(bookCopy))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateBookCopy @Summary Create Book Copy
+// @Tags copies
+// @Description Add a physical copy to a book
+// @ID create-book-copy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "Book ID"
+// @Param   copy    body    models.BookCopy     true        "Copy Info"
+// @Success 201 {object} map[string]string "status: copy created"
+// @Router /book/{id}/copies [post]
+func (h *Handler) CreateBookCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	var input models.BookCopy
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	input.BookID = id
+	if err := h.Services.Copies.Create(c.Request.Context(), input); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "copy created"})
+}
+
+// GetCopyByID @Summary Get Copy by ID
+// @Tags copies
+// @Description Get copy details by ID
+// @ID get-copy-by-id
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Copy ID"
+// @Success 200 {object} CopyResponse
+// @Router /copy/{id} [get]
+func (h *Handler) GetCopyByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid copy ID")
+		return
+	}
+
+	bookCopy, err := h.Services.Copies.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, copyResponse(bookCopy))
+}
+
+// UpdateCopy @Summary Update Copy
+// @Tags copies
+// @Description Update copy barcode, shelf location or condition by ID
+// @ID update-copy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "Copy ID"
+// @Param   copy    body    models.BookCopy     true        "Copy Info"
+// @Success 200 {object} map[string]string "status: copy updated"
+// @Router /copy/{id} [put]
+func (h *Handler) UpdateCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid copy ID")
+		return
+	}
+
+	var input models.BookCopy
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	input.ID = id
+	if err := h.Services.Copies.Update(c.Request.Context(), input); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "copy updated"})
+}
+
+// DeleteCopy @Summary Delete Copy
+// @Tags copies
+// @Description Delete copy by ID
+// @ID delete-copy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Copy ID"
+// @Success 200 {object} map[string]string "status: copy deleted"
+// @Router /copy/{id} [delete]
+func (h *Handler) DeleteCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid copy ID")
+		return
+	}
+
+	if err := h.Services.Copies.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "copy deleted"})
+}
@@ -0,0 +1,139 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getBookCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopyService := service.NewMockCopies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Copies: mockCopyService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/book/:id/copies", handler.GetBookCopies)
+
+	expectedCopies := []models.BookCopy{
+		{ID: 1, BookID: 1, Barcode: "000000101", ShelfLocation: "A-1", Condition: models.CopyConditionGood},
+		{ID: 2, BookID: 1, Barcode: "000000102", ShelfLocation: "A-1", Condition: models.CopyConditionPoor},
+	}
+	mockCopyService.EXPECT().GetByBook(gomock.Any(), 1).Return(expectedCopies, nil)
+
+	req, _ := http.NewRequest("GET", "/book/1/copies", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"id": 1, "book_id": 1, "barcode": "000000101", "shelf_location": "A-1", "condition": "good"},
+		{"id": 2, "book_id": 1, "barcode": "000000102", "shelf_location": "A-1", "condition": "poor"}
+	]`, w.Body.String())
+}
+
+func TestHandler_createBookCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopyService := service.NewMockCopies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Copies: mockCopyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/book/:id/copies", handler.CreateBookCopy)
+
+	newCopy := models.BookCopy{Barcode: "000000103", ShelfLocation: "A-1"}
+	mockCopyService.EXPECT().Create(gomock.Any(), models.BookCopy{BookID: 1, Barcode: "000000103", ShelfLocation: "A-1"}).Return(nil)
+
+	copyJSON, _ := json.Marshal(newCopy)
+	req, _ := http.NewRequest("POST", "/book/1/copies", bytes.NewBuffer(copyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "copy created")
+}
+
+func TestHandler_createBookCopy_InvalidInput(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/book/:id/copies", handler.CreateBookCopy)
+
+	req, _ := http.NewRequest("POST", "/book/1/copies", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid input")
+}
+
+func TestHandler_updateCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopyService := service.NewMockCopies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Copies: mockCopyService,
+		},
+	}
+
+	r := setupRouter()
+	r.PUT("/copy/:id", handler.UpdateCopy)
+
+	updatedCopy := models.BookCopy{ID: 1, Barcode: "000000101", ShelfLocation: "C-4", Condition: models.CopyConditionFair}
+	mockCopyService.EXPECT().Update(gomock.Any(), updatedCopy).Return(nil)
+
+	copyJSON, _ := json.Marshal(updatedCopy)
+	req, _ := http.NewRequest("PUT", "/copy/1", bytes.NewBuffer(copyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "copy updated")
+}
+
+func TestHandler_deleteCopy_Rented(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopyService := service.NewMockCopies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Copies: mockCopyService,
+		},
+	}
+
+	r := setupRouter()
+	r.DELETE("/copy/:id", handler.DeleteCopy)
+
+	mockCopyService.EXPECT().Delete(gomock.Any(), 1).Return(&service.Error{Kind: service.ErrConflict, Code: "copy_rented", Message: "copy is rented"})
+
+	req, _ := http.NewRequest("DELETE", "/copy/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "copy is rented")
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"library/internal/service"
+)
+
+// ErrorResponse is the body of every error response. Code is a stable
+// machine-readable identifier, Error a message for humans. Fields lists what
+// is wrong with a request body that failed validation.
+type ErrorResponse struct {
+	Code   string       `json:"code"`
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// statusOf maps the kinds of service errors to HTTP statuses.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorResponse writes err returned by the service layer. Domain errors keep
+// their message and code, and queries cut short by the deadline of the
+// request give a 503; anything else is a fault, which is logged and
+// reported without details.
+func errorResponse(c *gin.Context, err error) {
+	var (
+		domainErr *service.Error
+		limitErr  *service.LoanLimitError
+		finesErr  *service.FinesOwedError
+	)
+
+	switch {
+	case errors.As(err, &limitErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"code":   "loan_limit_reached",
+			"error":  err.Error(),
+			"policy": limitErr.Policy,
+			"limit":  limitErr.Limit,
+			"loans":  limitErr.Loans,
+		})
+	case errors.As(err, &finesErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"code":      "fines_owed",
+			"error":     err.Error(),
+			"balance":   finesErr.Balance,
+			"threshold": finesErr.Threshold,
+		})
+	case errors.As(err, &domainErr):
+		c.JSON(statusOf(domainErr), ErrorResponse{Code: domainErr.Code, Error: domainErr.Message})
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Code: "timeout", Error: "request timed out"})
+	default:
+		logrus.Errorf("%s %s: %s", c.Request.Method, c.Request.URL.Path, err.Error())
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Code: "internal_error", Error: "internal server error"})
+	}
+}
+
+// badRequest writes a 400 for a request that could not be read.
+func badRequest(c *gin.Context, code, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{Code: code, Error: message})
+}
+
+// unprocessable writes a 422 for a request that was read but is not valid.
+func unprocessable(c *gin.Context, code, message string) {
+	c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Code: code, Error: message})
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// PaymentInput is the body of a fine payment. Amount is in cents.
+type PaymentInput struct {
+	Amount int64  `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// WaiveInput is the body of a fine waiver.
+type WaiveInput struct {
+	Reason string `json:"reason"`
+}
+
+// FineBalanceResponse is the fines ledger of a user. Amounts are in cents;
+// Balance is what the user still owes.
+type FineBalanceResponse struct {
+	UserID   int               `json:"user_id"`
+	Balance  int64             `json:"balance"`
+	Fines    []FineResponse    `json:"fines"`
+	Payments []PaymentResponse `json:"payments"`
+}
+
+// FineResponse is a fine for the loan LoanID, returned DaysLate days late.
+type FineResponse struct {
+	ID          int        `json:"id"`
+	LoanID      int        `json:"loan_id"`
+	BookID      int        `json:"book_id"`
+	Title       string     `json:"title"`
+	DaysLate    int        `json:"days_late"`
+	Amount      int64      `json:"amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+	WaivedAt    *time.Time `json:"waived_at"`
+	WaiveReason string     `json:"waive_reason,omitempty"`
+}
+
+// PaymentResponse is money received from a user towards their fines.
+type PaymentResponse struct {
+	ID        int       `json:"id"`
+	Amount    int64     `json:"amount"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func fineBalanceResponse(balance models.FineBalance) FineBalanceResponse {
+	response := FineBalanceResponse{
+		UserID:   balance.UserID,
+		Balance:  balance.Balance,
+		Fines:    make([]FineResponse, 0, len(balance.Fines)),
+		Payments: make([]PaymentResponse, 0, len(balance.Payments)),
+	}
+	for _, fine := range balance.Fines {
+		response.Fines = append(response.Fines, FineResponse{
+			ID:          fine.ID,
+			LoanID:      fine.RentedBookID,
+			BookID:      fine.RentedBook.BookID,
+			Title:       fine.RentedBook.Book.Title,
+			DaysLate:    fine.DaysLate,
+			Amount:      fine.Amount,
+			CreatedAt:   fine.CreatedAt,
+			WaivedAt:    fine.WaivedAt,
+			WaiveReason: fine.WaiveReason,
+		})
+	}
+	for _, payment := range balance.Payments {
+		response.Payments = append(response.Payments, PaymentResponse{
+			ID:        payment.ID,
+			Amount:    payment.Amount,
+			Note:      payment.Note,
+			CreatedAt: payment.CreatedAt,
+		})
+	}
+	return response
+}
+
+// GetUserFines @Summary Get User Fines
+// @Tags fines
+// @Description Get the outstanding fine balance of a user with their fines and payments
+// @ID get-user-fines
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "User ID"
+// @Success 200 {object} FineBalanceResponse
+// @Router /user/{id}/fines [get]
+func (h *Handler) GetUserFines(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	balance, err := h.Services.Fines.GetBalance(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fineBalanceResponse(balance))
+}
+
+// CreatePayment @Summary Record Payment
+// @Tags fines
+// @Description Record a payment against the outstanding fine balance of a user
+// @ID create-payment
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "User ID"
+// @Param   payment  body    PaymentInput     true        "Payment Info"
+// @Success 201 {object} map[string]string "status: payment recorded"
+// @Router /user/{id}/payments [post]
+func (h *Handler) CreatePayment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	var input PaymentInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Fines.Pay(c.Request.Context(), id, input.Amount, input.Note); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "payment recorded"})
+}
+
+// WaiveFine @Summary Waive Fine
+// @Tags fines
+// @Description Waive a fine so it no longer counts towards the balance of the user. A fine that payments have gone towards, so that the balance no longer covers it, cannot be waived
+// @ID waive-fine
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Fine ID"
+// @Param   waive  body    WaiveInput     true        "Waiver Info"
+// @Success 200 {object} map[string]string "status: fine waived"
+// @Failure 409 {object} ErrorResponse "the fine is already waived or paid in part"
+// @Router /fine/{id}/waive [post]
+func (h *Handler) WaiveFine(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid fine ID")
+		return
+	}
+
+	var input WaiveInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Fines.Waive(c.Request.Context(), id, input.Reason); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "fine waived"})
+}
@@ -0,0 +1,132 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getUserFines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := service.NewMockFines(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Fines: mockFineService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/user/:id/fines", handler.GetUserFines)
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	expectedBalance := models.FineBalance{
+		UserID:  1,
+		Balance: 50,
+		Fines: []models.Fine{{
+			ID: 1, UserID: 1, RentedBookID: 2, DaysLate: 2, Amount: 50, CreatedAt: createdAt,
+			RentedBook: models.RentedBook{ID: 2, UserID: 1, BookID: 3, User: models.User{ID: 1, Email: "user@example.com"}, Book: models.Book{ID: 3, Title: "Book 3"}},
+		}},
+		Payments: []models.Payment{{ID: 4, UserID: 1, Amount: 25, Note: "cash", CreatedAt: createdAt}},
+	}
+	mockFineService.EXPECT().GetBalance(gomock.Any(), 1).Return(expectedBalance, nil)
+
+	req, _ := http.NewRequest("GET", "/user/1/fines", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the loan is shown by its book only, not its borrower
+	assert.JSONEq(t, `{
+		"user_id": 1,
+		"balance": 50,
+		"fines": [{
+			"id": 1,
+			"loan_id": 2,
+			"book_id": 3,
+			"title": "Book 3",
+			"days_late": 2,
+			"amount": 50,
+			"created_at": "2024-05-01T00:00:00Z",
+			"waived_at": null
+		}],
+		"payments": [{"id": 4, "amount": 25, "note": "cash", "created_at": "2024-05-01T00:00:00Z"}]
+	}`, w.Body.String())
+}
+
+func TestHandler_createPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := service.NewMockFines(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Fines: mockFineService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/user/:id/payments", handler.CreatePayment)
+
+	payment := controller.PaymentInput{Amount: 50, Note: "card"}
+	mockFineService.EXPECT().Pay(gomock.Any(), 1, payment.Amount, payment.Note).Return(nil)
+
+	paymentJSON, _ := json.Marshal(payment)
+	req, _ := http.NewRequest("POST", "/user/1/payments", bytes.NewBuffer(paymentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"status":"payment recorded"}`, w.Body.String())
+}
+
+func TestHandler_waiveFine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := service.NewMockFines(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Fines: mockFineService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/fine/:id/waive", handler.WaiveFine)
+
+	mockFineService.EXPECT().Waive(gomock.Any(), 3, "first offence").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/fine/3/waive", bytes.NewBufferString(`{"reason":"first offence"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"fine waived"}`, w.Body.String())
+}
+
+func TestHandler_waiveFine_InvalidID(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/fine/:id/waive", handler.WaiveFine)
+
+	req, _ := http.NewRequest("POST", "/fine/abc/waive", bytes.NewBufferString(`{"reason":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"invalid_id","error":"invalid fine ID"}`, w.Body.String())
+}
@@ -1,20 +1,26 @@
 package controller
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
+	"library/internal/policy"
 	"library/internal/service"
 	"library/swagger"
 	"net/http"
+	"time"
 
 	_ "library/docs"
 )
 
 type Handler struct {
 	Services *service.Service
+	// QueryTimeout is how long the queries of an API request may take in
+	// all; zero leaves requests without a deadline.
+	QueryTimeout time.Duration
 }
 
-func NewHandler(services *service.Service) *Handler {
-	return &Handler{Services: services}
+func NewHandler(services *service.Service, queryTimeout time.Duration) *Handler {
+	return &Handler{Services: services, QueryTimeout: queryTimeout}
 }
 
 func (h *Handler) InitRoutes() *gin.Engine {
@@ -24,41 +30,158 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	router.Static("/docs", "./docs")
 	router.GET("/swagger/*any", gin.WrapH(http.HandlerFunc(swagger.SwaggerUI)))
 
+	// Reading the catalogue is open to everyone. Every other route needs an
+	// access token whose role has the permission named, or an API key scoped
+	// for it, see package policy.
+	var (
+		catalogWrite   = h.allow(policy.CatalogWrite)
+		loansOwnRead   = h.allow(policy.LoansOwn, policy.LoansRead)
+		loansOwnWrite  = h.allow(policy.LoansOwn, policy.LoansWrite)
+		loansRead      = h.allow(policy.LoansRead)
+		ownLoans       = h.allowSelf(policy.LoansOwn, policy.LoansRead)
+		ownAccount     = h.allowSelf(policy.LoansOwn, policy.UsersRead)
+		finesManage    = h.allow(policy.FinesManage)
+		usersRead      = h.allow(policy.UsersRead)
+		usersManage    = h.allow(policy.UsersManage)
+		policiesManage = h.allow(policy.PoliciesManage)
+		apiKeysManage  = h.allow(policy.APIKeysManage)
+		archiveManage  = h.allow(policy.ArchiveManage)
+	)
+
 	api := router.Group("/api")
+	if h.QueryTimeout > 0 {
+		api.Use(withTimeout(h.QueryTimeout))
+	}
 	{
+		api.GET("/search", h.SearchBooks)
+
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", h.Register)
+			auth.POST("/login", h.Login)
+			auth.POST("/refresh", h.RefreshToken)
+		}
+
 		authors := api.Group("/author")
 		{
+			authors.GET("/deleted", archiveManage, h.GetDeletedAuthors)
 			authors.GET("/:id", h.GetAuthorByID)
 			authors.GET("/", h.GetAllAuthors)
-			authors.POST("/", h.CreateAuthor)
-			authors.PUT("/:id", h.UpdateAuthor)
-			authors.DELETE("/:id", h.DeleteAuthor)
+			authors.POST("/", catalogWrite, h.CreateAuthor)
+			authors.PUT("/:id", catalogWrite, h.UpdateAuthor)
+			authors.PATCH("/:id", catalogWrite, h.PatchAuthor)
+			authors.DELETE("/:id", catalogWrite, h.DeleteAuthor)
+			authors.POST("/:id/restore", archiveManage, h.RestoreAuthor)
+			authors.GET("/:id/books", h.GetAuthorBooks)
 		}
 
 		books := api.Group("/book")
 		{
+			books.GET("/deleted", archiveManage, h.GetDeletedBooks)
 			books.GET("/:id", h.GetBookByID)
 			books.GET("/", h.GetAllBooks)
-			books.POST("/", h.CreateBook)
-			books.PUT("/:id", h.UpdateBook)
-			books.DELETE("/:id", h.DeleteBook)
+			books.POST("/", catalogWrite, h.CreateBook)
+			books.PUT("/:id", catalogWrite, h.UpdateBook)
+			books.PATCH("/:id", catalogWrite, h.PatchBook)
+			books.DELETE("/:id", catalogWrite, h.DeleteBook)
+			books.POST("/:id/restore", archiveManage, h.RestoreBook)
+			books.GET("/:id/holds", loansRead, h.GetBookHolds)
+			books.GET("/:id/loans", loansRead, h.GetBookLoans)
+			books.GET("/:id/copies", h.GetBookCopies)
+			books.POST("/:id/copies", catalogWrite, h.CreateBookCopy)
+		}
+
+		copies := api.Group("/copy")
+		{
+			copies.GET("/:id", h.GetCopyByID)
+			copies.PUT("/:id", catalogWrite, h.UpdateCopy)
+			copies.DELETE("/:id", catalogWrite, h.DeleteCopy)
 		}
 
 		users := api.Group("/user")
 		{
-			users.GET("/:id", h.GetUserByID)
-			users.GET("/", h.GetAllUsers)
-			users.POST("/", h.CreateUser)
-			users.PUT("/:id", h.UpdateUser)
-			users.DELETE("/:id", h.DeleteUser)
+			users.GET("/deleted", archiveManage, h.GetDeletedUsers)
+			users.GET("/:id", ownAccount, h.GetUserByID)
+			users.GET("/", usersRead, h.GetAllUsers)
+			users.POST("/", usersManage, h.CreateUser)
+			users.PUT("/:id", usersManage, h.UpdateUser)
+			users.PATCH("/:id", usersManage, h.PatchUser)
+			users.PUT("/:id/role", usersManage, h.SetUserRole)
+			users.DELETE("/:id", usersManage, h.DeleteUser)
+			users.POST("/:id/restore", archiveManage, h.RestoreUser)
+			users.GET("/:id/holds", ownLoans, h.GetUserHolds)
+			users.GET("/:id/loans", ownLoans, h.GetUserLoans)
+			users.GET("/:id/fines", ownLoans, h.GetUserFines)
+			users.POST("/:id/payments", finesManage, h.CreatePayment)
 		}
 
+		// patrons rent for themselves, staff for anyone; see actingFor
 		rent := api.Group("/rent")
 		{
-			rent.POST("/", h.RentBook)
-			rent.POST("/return", h.ReturnBook)
+			rent.POST("/", loansOwnWrite, h.RentBook)
+			rent.POST("/return", loansOwnWrite, h.ReturnBook)
+			rent.POST("/renew", loansOwnWrite, h.RenewBook)
+			rent.GET("/overdue", loansRead, h.GetOverdueRentals)
+		}
+
+		fines := api.Group("/fine")
+		{
+			fines.POST("/:id/waive", finesManage, h.WaiveFine)
+		}
+
+		policies := api.Group("/policy")
+		{
+			policies.GET("/:id", h.GetLoanPolicyByID)
+			policies.GET("/", h.GetAllLoanPolicies)
+			policies.POST("/", policiesManage, h.CreateLoanPolicy)
+			policies.PUT("/:id", policiesManage, h.UpdateLoanPolicy)
+			policies.DELETE("/:id", policiesManage, h.DeleteLoanPolicy)
+		}
+
+		subjects := api.Group("/subject")
+		{
+			subjects.GET("/", h.GetAllSubjects)
+			subjects.GET("/:id", h.GetSubjectByID)
+			subjects.GET("/:id/books", h.GetSubjectBooks)
+			subjects.POST("/", catalogWrite, h.CreateSubject)
+			subjects.PUT("/:id", catalogWrite, h.UpdateSubject)
+			subjects.DELETE("/:id", catalogWrite, h.DeleteSubject)
+		}
+
+		tags := api.Group("/tag")
+		{
+			tags.GET("/", h.GetAllTags)
+			tags.PUT("/:id", catalogWrite, h.UpdateTag)
+			tags.DELETE("/:id", catalogWrite, h.DeleteTag)
+		}
+
+		holds := api.Group("/hold")
+		{
+			holds.POST("/", loansOwnWrite, h.PlaceHold)
+			holds.GET("/:id", loansOwnRead, h.GetHoldByID)
+			holds.DELETE("/:id", loansOwnWrite, h.CancelHold)
+		}
+
+		apiKeys := api.Group("/api-key")
+		{
+			apiKeys.GET("/", apiKeysManage, h.GetAllAPIKeys)
+			apiKeys.POST("/", apiKeysManage, h.CreateAPIKey)
+			apiKeys.DELETE("/:id", apiKeysManage, h.RevokeAPIKey)
 		}
 	}
 
 	return router
 }
+
+// withTimeout puts a deadline on the context of the request, which the
+// services pass on to every query they make. A query still running when it
+// passes is cancelled.
+func withTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
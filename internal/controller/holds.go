@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"library/internal/policy"
+	"library/models"
+)
+
+// HoldResponse is a hold as the API shows it. The holder is shown by ID and
+// name only; the name and title are left out where the listing did not load
+// them. CopyID is the copy set aside once the hold is ready.
+type HoldResponse struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	UserName  string     `json:"user_name,omitempty"`
+	BookID    int        `json:"book_id"`
+	Title     string     `json:"title,omitempty"`
+	CopyID    *int       `json:"copy_id"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadyAt   *time.Time `json:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+func holdResponse(hold models.Hold) HoldResponse {
+	return HoldResponse{
+		ID:        hold.ID,
+		UserID:    hold.UserID,
+		UserName:  hold.User.Name,
+		BookID:    hold.BookID,
+		Title:     hold.Book.Title,
+		CopyID:    hold.CopyID,
+		Status:    hold.Status,
+		CreatedAt: hold.CreatedAt,
+		ReadyAt:   hold.ReadyAt,
+		ExpiresAt: hold.ExpiresAt,
+		ClosedAt:  hold.ClosedAt,
+	}
+}
+
+func holdResponses(holds []models.Hold) []HoldResponse {
+	responses := make([]HoldResponse, 0, len(holds))
+	for _, hold := range holds {
+		responses = append(responses, holdResponse(hold))
+	}
+	return responses
+}
+
+// PlaceHold @Summary Place Hold
+// @Tags holds
+// @Description Join the hold queue for a rented book as the authenticated user, or for user_id if the caller is staff (required with an API key)
+// @ID place-hold
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   hold  body    Input     true        "Hold Info"
+// @Success 201 {object} HoldResponse
+// @Router /hold [post]
+func (h *Handler) PlaceHold(c *gin.Context) {
+	var input Input
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, ok := actingFor(c, input.UserID, policy.LoansWrite)
+	if !ok {
+		return
+	}
+
+	hold, err := h.Services.Holds.Place(c.Request.Context(), userID, input.BookID)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holdResponse(hold))
+}
+
+// GetHoldByID @Summary Get Hold by ID
+// @Tags holds
+// @Description Get hold details by ID. Patrons may only see their own holds
+// @ID get-hold-by-id
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Hold ID"
+// @Success 200 {object} HoldResponse
+// @Router /hold/{id} [get]
+func (h *Handler) GetHoldByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid hold ID")
+		return
+	}
+
+	hold, err := h.Services.Holds.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if _, ok := actingFor(c, hold.UserID, policy.LoansRead); !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, holdResponse(hold))
+}
+
+// CancelHold @Summary Cancel Hold
+// @Tags holds
+// @Description Cancel a waiting or ready hold by ID. Patrons may only cancel their own holds
+// @ID cancel-hold
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Hold ID"
+// @Success 200 {object} map[string]string "status: hold cancelled"
+// @Router /hold/{id} [delete]
+func (h *Handler) CancelHold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid hold ID")
+		return
+	}
+
+	hold, err := h.Services.Holds.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	if _, ok := actingFor(c, hold.UserID, policy.LoansWrite); !ok {
+		return
+	}
+
+	if err := h.Services.Holds.Cancel(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "hold cancelled"})
+}
+
+// GetBookHolds @Summary Get Book Holds
+// @Tags holds
+// @Description Get the hold queue of a book in FIFO order
+// @ID get-book-holds
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Book ID"
+// @Success 200 {array} HoldResponse
+// @Router /book/{id}/holds [get]
+func (h *Handler) GetBookHolds(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	holds, err := h.Services.Holds.GetByBook(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holdResponses(holds))
+}
+
+// GetUserHolds @Summary Get User Holds
+// @Tags holds
+// @Description Get active holds placed by a user
+// @ID get-user-holds
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "User ID"
+// @Success 200 {array} HoldResponse
+// @Router /user/{id}/holds [get]
+func (h *Handler) GetUserHolds(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	holds, err := h.Services.Holds.GetByUser(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holdResponses(holds))
+}
@@ -0,0 +1,264 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_placeHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/hold", signedIn(ctrl, handler, 2), handler.PlaceHold)
+
+	holdInfo := controller.Input{BookID: 1}
+	expectedHold := models.Hold{ID: 1, UserID: 2, BookID: 1, Status: models.HoldWaiting}
+	mockHoldService.EXPECT().Place(gomock.Any(), 2, holdInfo.BookID).Return(expectedHold, nil)
+
+	holdJSON, _ := json.Marshal(holdInfo)
+	req, _ := http.NewRequest("POST", "/hold", bytes.NewBuffer(holdJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var hold controller.HoldResponse
+	err := json.Unmarshal(w.Body.Bytes(), &hold)
+	assert.NoError(t, err)
+	assert.Equal(t, controller.HoldResponse{ID: 1, UserID: 2, BookID: 1, Status: models.HoldWaiting}, hold)
+}
+
+func TestHandler_placeHold_InvalidInput(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/hold", handler.PlaceHold)
+
+	req, _ := http.NewRequest("POST", "/hold", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid input")
+}
+
+func TestHandler_placeHold_BookAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/hold", signedIn(ctrl, handler, 2), handler.PlaceHold)
+
+	holdInfo := controller.Input{BookID: 1}
+	mockHoldService.EXPECT().Place(gomock.Any(), 2, holdInfo.BookID).Return(models.Hold{}, &service.Error{Kind: service.ErrConflict, Code: "book_available", Message: "book is available for rent"})
+
+	holdJSON, _ := json.Marshal(holdInfo)
+	req, _ := http.NewRequest("POST", "/hold", bytes.NewBuffer(holdJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "book is available for rent")
+}
+
+func TestHandler_getHoldByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/hold/:id", signedIn(ctrl, handler, 2), handler.GetHoldByID)
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	expectedHold := models.Hold{
+		ID: 1, UserID: 2, BookID: 1, Status: models.HoldWaiting, CreatedAt: createdAt,
+		User: models.User{ID: 2, Name: "User 2", Email: "user2@example.com", Role: models.RolePatron},
+		Book: models.Book{ID: 1, Title: "Book 1"},
+	}
+	mockHoldService.EXPECT().GetByID(gomock.Any(), 1).Return(expectedHold, nil)
+
+	req, _ := http.NewRequest("GET", "/hold/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the holder is shown by ID and name only
+	assert.JSONEq(t, `{
+		"id": 1,
+		"user_id": 2,
+		"user_name": "User 2",
+		"book_id": 1,
+		"title": "Book 1",
+		"copy_id": null,
+		"status": "waiting",
+		"created_at": "2024-05-01T00:00:00Z",
+		"ready_at": null,
+		"expires_at": null,
+		"closed_at": null
+	}`, w.Body.String())
+}
+
+func TestHandler_cancelHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.DELETE("/hold/:id", signedIn(ctrl, handler, 2), handler.CancelHold)
+
+	mockHoldService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{ID: 1, UserID: 2, BookID: 1, Status: models.HoldWaiting}, nil)
+	mockHoldService.EXPECT().Cancel(gomock.Any(), 1).Return(nil)
+
+	req, _ := http.NewRequest("DELETE", "/hold/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "hold cancelled")
+}
+
+func TestHandler_cancelHold_SomeoneElses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.DELETE("/hold/:id", signedIn(ctrl, handler, 3), handler.CancelHold)
+
+	mockHoldService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{ID: 1, UserID: 2, BookID: 1, Status: models.HoldWaiting}, nil)
+
+	req, _ := http.NewRequest("DELETE", "/hold/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"code":"forbidden","error":"patron role does not allow loans:write"}`, w.Body.String())
+}
+
+func TestHandler_cancelHold_InvalidID(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.DELETE("/hold/:id", handler.CancelHold)
+
+	req, _ := http.NewRequest("DELETE", "/hold/invalid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid hold ID")
+}
+
+func TestHandler_getBookHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/book/:id/holds", handler.GetBookHolds)
+
+	expectedHolds := []models.Hold{
+		{ID: 1, UserID: 2, BookID: 1, Status: models.HoldReady},
+		{ID: 2, UserID: 3, BookID: 1, Status: models.HoldWaiting},
+	}
+	mockHoldService.EXPECT().GetByBook(gomock.Any(), 1).Return(expectedHolds, nil)
+
+	req, _ := http.NewRequest("GET", "/book/1/holds", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var holds []controller.HoldResponse
+	err := json.Unmarshal(w.Body.Bytes(), &holds)
+	assert.NoError(t, err)
+	assert.Equal(t, []controller.HoldResponse{
+		{ID: 1, UserID: 2, BookID: 1, Status: models.HoldReady},
+		{ID: 2, UserID: 3, BookID: 1, Status: models.HoldWaiting},
+	}, holds)
+}
+
+func TestHandler_getUserHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := service.NewMockHolds(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Holds: mockHoldService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/user/:id/holds", handler.GetUserHolds)
+
+	expectedHolds := []models.Hold{
+		{ID: 2, UserID: 3, BookID: 1, Status: models.HoldWaiting},
+	}
+	mockHoldService.EXPECT().GetByUser(gomock.Any(), 3).Return(expectedHolds, nil)
+
+	req, _ := http.NewRequest("GET", "/user/3/holds", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var holds []controller.HoldResponse
+	err := json.Unmarshal(w.Body.Bytes(), &holds)
+	assert.NoError(t, err)
+	assert.Equal(t, []controller.HoldResponse{{ID: 2, UserID: 3, BookID: 1, Status: models.HoldWaiting}}, holds)
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// LoanResponse is a loan as the API shows it. The borrower is shown by ID
+// and name only; the name, title and barcode are left out where the listing
+// did not load them.
+type LoanResponse struct {
+	ID           int                   `json:"id"`
+	UserID       int                   `json:"user_id"`
+	UserName     string                `json:"user_name,omitempty"`
+	BookID       int                   `json:"book_id"`
+	Title        string                `json:"title,omitempty"`
+	Contributors []ContributorResponse `json:"contributors,omitempty"`
+	CopyID       int                   `json:"copy_id"`
+	Barcode      string                `json:"barcode,omitempty"`
+	RentedAt     time.Time             `json:"rented_at"`
+	DueAt        time.Time             `json:"due_at"`
+	RenewalCount int                   `json:"renewal_count"`
+	ReturnedAt   *time.Time            `json:"returned_at"`
+}
+
+func loanResponse(rentedBook models.RentedBook) LoanResponse {
+	response := LoanResponse{
+		ID:           rentedBook.ID,
+		UserID:       rentedBook.UserID,
+		UserName:     rentedBook.User.Name,
+		BookID:       rentedBook.BookID,
+		Title:        rentedBook.Book.Title,
+		CopyID:       rentedBook.CopyID,
+		Barcode:      rentedBook.Copy.Barcode,
+		RentedAt:     rentedBook.RentedAt,
+		DueAt:        rentedBook.DueAt,
+		RenewalCount: rentedBook.RenewalCount,
+		ReturnedAt:   rentedBook.ReturnedAt,
+	}
+	if len(rentedBook.Book.Contributors) > 0 {
+		response.Contributors = contributorResponses(rentedBook.Book.Contributors)
+	}
+	return response
+}
+
+// LoanPageResponse is a page of a loan listing. Next links to the following
+// page and is empty on the last one.
+type LoanPageResponse struct {
+	Loans    []LoanResponse `json:"loans"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Next     string         `json:"next"`
+}
+
+func loanPageResponse(c *gin.Context, loans models.LoanPage) LoanPageResponse {
+	response := LoanPageResponse{
+		Loans:    make([]LoanResponse, 0, len(loans.Loans)),
+		Total:    loans.Total,
+		Page:     loans.Page,
+		PageSize: loans.PageSize,
+		Next:     nextPage(c, loans.Page, loans.PageSize, loans.Total),
+	}
+	for _, loan := range loans.Loans {
+		response.Loans = append(response.Loans, loanResponse(loan))
+	}
+	return response
+}
+
+// GetUserLoans @Summary Get User Loans
+// @Tags loans
+// @Description Get a page of loans of a user with the book, author and copy, sorted by rent date
+// @ID get-user-loans
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "User ID"
+// @Param   status    query    string     false        "open or returned, all loans if omitted"
+// @Param   order    query    string     false        "asc or desc by rent date, desc if omitted"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Loans per page, at most 100"
+// @Success 200 {object} LoanPageResponse
+// @Router /user/{id}/loans [get]
+func (h *Handler) GetUserLoans(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	query, err := loanQuery(c)
+	if err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+	query.UserID = id
+
+	loans, err := h.Services.Books.GetLoans(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, loanPageResponse(c, loans))
+}
+
+// GetBookLoans @Summary Get Book Loans
+// @Tags loans
+// @Description Get a page of loans of a book with the user, author and copy, sorted by rent date
+// @ID get-book-loans
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Book ID"
+// @Param   status    query    string     false        "open or returned, all loans if omitted"
+// @Param   order    query    string     false        "asc or desc by rent date, desc if omitted"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Loans per page, at most 100"
+// @Success 200 {object} LoanPageResponse
+// @Router /book/{id}/loans [get]
+func (h *Handler) GetBookLoans(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid book ID")
+		return
+	}
+
+	query, err := loanQuery(c)
+	if err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+	query.BookID = id
+
+	loans, err := h.Services.Books.GetLoans(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, loanPageResponse(c, loans))
+}
+
+// loanQuery reads the status, order and paging parameters of a loan listing.
+func loanQuery(c *gin.Context) (models.LoanQuery, error) {
+	var query models.LoanQuery
+
+	switch status := c.Query("status"); status {
+	case "", models.LoanOpen, models.LoanReturned:
+		query.Status = status
+	default:
+		return query, fmt.Errorf("invalid status, expected open or returned")
+	}
+
+	switch c.Query("order") {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("invalid order, expected asc or desc")
+	}
+
+	var err error
+	query.Page, query.PageSize, err = pageParams(c)
+	return query, err
+}
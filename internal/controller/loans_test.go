@@ -0,0 +1,109 @@
+package controller_test
+
+import (
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getUserLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/user/:id/loans", handler.GetUserLoans)
+
+	rentedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	expectedPage := models.LoanPage{
+		Loans: []models.RentedBook{{
+			ID: 1, UserID: 1, BookID: 2, CopyID: 3, RentedAt: rentedAt, DueAt: rentedAt.Add(14 * 24 * time.Hour),
+			User: models.User{ID: 1, Name: "User 1", Email: "user1@example.com", Role: models.RolePatron},
+			Book: models.Book{ID: 2, Title: "Book 2", Contributors: []models.BookContributor{{AuthorID: 4, Role: models.RoleAuthor, Author: models.Author{ID: 4, Name: "Author 4"}}}},
+			Copy: models.BookCopy{ID: 3, BookID: 2, Barcode: "B-0003"},
+		}},
+		Total:    11,
+		Page:     1,
+		PageSize: 10,
+	}
+	mockBookService.EXPECT().GetLoans(gomock.Any(), models.LoanQuery{UserID: 1, Status: models.LoanOpen, Page: 1, PageSize: 10}).
+		Return(expectedPage, nil)
+
+	req, _ := http.NewRequest("GET", "/user/1/loans?status=open&page=1&page_size=10", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the borrower is shown by ID and name only
+	assert.JSONEq(t, `{
+		"loans": [{
+			"id": 1,
+			"user_id": 1,
+			"user_name": "User 1",
+			"book_id": 2,
+			"title": "Book 2",
+			"contributors": [{"author_id": 4, "name": "Author 4", "role": "author"}],
+			"copy_id": 3,
+			"barcode": "B-0003",
+			"rented_at": "2024-05-01T00:00:00Z",
+			"due_at": "2024-05-15T00:00:00Z",
+			"renewal_count": 0,
+			"returned_at": null
+		}],
+		"total": 11,
+		"page": 1,
+		"page_size": 10,
+		"next": "/user/1/loans?page=2&page_size=10&status=open"
+	}`, w.Body.String())
+}
+
+func TestHandler_getUserLoans_InvalidStatus(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.GET("/user/:id/loans", handler.GetUserLoans)
+
+	req, _ := http.NewRequest("GET", "/user/1/loans?status=lost", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"invalid_query","error":"invalid status, expected open or returned"}`, w.Body.String())
+}
+
+func TestHandler_getBookLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/book/:id/loans", handler.GetBookLoans)
+
+	mockBookService.EXPECT().GetLoans(gomock.Any(), models.LoanQuery{BookID: 2, Status: models.LoanReturned, Ascending: true}).
+		Return(models.LoanPage{Page: 1, PageSize: 20}, nil)
+
+	req, _ := http.NewRequest("GET", "/book/2/loans?status=returned&order=asc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Media types of the bodies of PATCH requests. Plain JSON is taken for a
+// merge patch.
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+// bindPatch applies the patch in the body of a PATCH request to current,
+// the resource as it would be sent in a PUT, and binds the result to input
+// as bindJSON does, so a patched resource is checked like a replaced one.
+// input must be empty: fields the patch removes are left as they are in it.
+// The patch is an RFC 7386 merge patch, or an RFC 6902 JSON Patch when sent
+// as application/json-patch+json. When it does not apply, bindPatch writes
+// the error and returns false.
+func bindPatch(c *gin.Context, current, input interface{}) bool {
+	patch, err := c.GetRawData()
+	if err != nil {
+		badRequest(c, "invalid_input", "invalid input")
+		return false
+	}
+	document, err := json.Marshal(current)
+	if err != nil {
+		errorResponse(c, err)
+		return false
+	}
+
+	switch c.ContentType() {
+	case jsonPatchType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			badRequest(c, "invalid_patch", "body is not a JSON Patch")
+			return false
+		}
+		document, err = operations.Apply(document)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			c.JSON(http.StatusConflict, ErrorResponse{Code: "patch_test_failed", Error: "a test operation of the patch failed"})
+			return false
+		}
+		if err != nil {
+			unprocessable(c, "invalid_patch", "patch does not apply: "+err.Error())
+			return false
+		}
+	case mergePatchType, binding.MIMEJSON:
+		document, err = jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			badRequest(c, "invalid_patch", "body is not a JSON merge patch")
+			return false
+		}
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+			Code:  "unsupported_media_type",
+			Error: "patches are sent as " + mergePatchType + " or " + jsonPatchType,
+		})
+		return false
+	}
+
+	return bound(c, binding.JSON.BindBody(document, input))
+}
@@ -0,0 +1,241 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var patchedBook = models.Book{
+	ID:           1,
+	Title:        "Old Title",
+	ISBN:         "978-0-306-40615-7",
+	PublishedAt:  time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+	Contributors: []models.BookContributor{{BookID: 1, AuthorID: 1, Role: models.RoleAuthor, Position: 1}},
+	Subjects:     []models.Subject{{ID: 2, Name: "History"}},
+	Tags:         []models.Tag{{ID: 3, Name: "classic"}},
+}
+
+func setupPatchBook(t *testing.T) (*gin.Engine, *service.MockBooks) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := gin.Default()
+	r.PATCH("/book/:id", handler.PatchBook)
+	return r, mockBookService
+}
+
+func patch(r *gin.Engine, path, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("PATCH", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_patchBook_MergePatch(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+	mockBookService.EXPECT().Update(gomock.Any(), models.Book{
+		ID:           1,
+		Title:        "New Title",
+		ISBN:         "978-0-306-40615-7",
+		PublishedAt:  time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+		Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor}},
+		Subjects:     []models.Subject{{ID: 2}},
+		Tags:         []models.Tag{{Name: "classic"}},
+	}).Return(nil)
+
+	w := patch(r, "/book/1", "application/merge-patch+json", `{"title":"New Title"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "book updated")
+}
+
+func TestHandler_patchBook_MergePatchRemovesTags(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+	mockBookService.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, book models.Book) error {
+		assert.Empty(t, book.Tags)
+		assert.Equal(t, "Old Title", book.Title)
+		return nil
+	})
+
+	w := patch(r, "/book/1", "application/json", `{"tags":null}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_patchBook_JSONPatch(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+	mockBookService.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, book models.Book) error {
+		assert.Equal(t, "New Title", book.Title)
+		assert.Equal(t, []models.Tag{{Name: "classic"}, {Name: "new"}}, book.Tags)
+		return nil
+	})
+
+	body := `[
+		{"op":"test","path":"/title","value":"Old Title"},
+		{"op":"replace","path":"/title","value":"New Title"},
+		{"op":"add","path":"/tags/-","value":"new"}
+	]`
+	w := patch(r, "/book/1", "application/json-patch+json", body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "book updated")
+}
+
+func TestHandler_patchBook_TestFailed(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+
+	body := `[{"op":"test","path":"/title","value":"Another Title"},{"op":"replace","path":"/title","value":"New Title"}]`
+	w := patch(r, "/book/1", "application/json-patch+json", body)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "patch_test_failed")
+}
+
+func TestHandler_patchBook_InvalidPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"merge patch is not JSON", "application/merge-patch+json", `{"title":`, http.StatusBadRequest},
+		{"JSON Patch is not a list of operations", "application/json-patch+json", `{"title":"New Title"}`, http.StatusBadRequest},
+		{"JSON Patch path is missing", "application/json-patch+json", `[{"op":"remove","path":"/subtitle"}]`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockBookService := setupPatchBook(t)
+			mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+
+			w := patch(r, "/book/1", tt.contentType, tt.body)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid_patch")
+		})
+	}
+}
+
+func TestHandler_patchBook_InvalidFields(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+
+	w := patch(r, "/book/1", "application/merge-patch+json", `{"title":null,"contributors":[]}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"code": "validation_failed",
+		"error": "title: is required",
+		"fields": [
+			{"field": "title", "code": "required", "error": "is required"},
+			{"field": "contributors", "code": "min", "error": "must have at least 1 items"}
+		]
+	}`, w.Body.String())
+}
+
+func TestHandler_patchBook_UnsupportedMediaType(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(patchedBook, nil)
+
+	w := patch(r, "/book/1", "text/plain", `title=New Title`)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "unsupported_media_type")
+}
+
+func TestHandler_patchBook_NotFound(t *testing.T) {
+	r, mockBookService := setupPatchBook(t)
+
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Book{}, &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "book not found"})
+
+	w := patch(r, "/book/1", "application/merge-patch+json", `{"title":"New Title"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "book not found")
+}
+
+func TestHandler_patchBook_InvalidID(t *testing.T) {
+	r, _ := setupPatchBook(t)
+
+	w := patch(r, "/book/invalid", "application/merge-patch+json", `{"title":"New Title"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid book ID")
+}
+
+func TestHandler_patchAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthorService := service.NewMockAuthors(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Authors: mockAuthorService,
+		},
+	}
+
+	r := gin.Default()
+	r.PATCH("/author/:id", handler.PatchAuthor)
+
+	mockAuthorService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Author{ID: 1, Name: "Old Name"}, nil)
+	mockAuthorService.EXPECT().Update(gomock.Any(), models.Author{ID: 1, Name: "New Name"}).Return(nil)
+
+	w := patch(r, "/author/1", "application/json-patch+json", `[{"op":"replace","path":"/name","value":"New Name"}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "author updated")
+}
+
+func TestHandler_patchUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := service.NewMockUsers(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Users: mockUserService,
+		},
+	}
+
+	r := gin.Default()
+	r.PATCH("/user/:id", handler.PatchUser)
+
+	policyID := 2
+	mockUserService.EXPECT().GetByID(gomock.Any(), 1).
+		Return(models.User{ID: 1, Name: "Ann", Email: "ann@example.com", Role: models.RoleLibrarian, LoanPolicyID: &policyID}, nil)
+	mockUserService.EXPECT().Update(gomock.Any(), models.User{ID: 1, Name: "Ann", Email: "ann@example.org"}).Return(nil)
+
+	w := patch(r, "/user/1", "application/merge-patch+json", `{"email":"ann@example.org","loan_policy_id":null,"role":"admin"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "user updated")
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// LoanPolicyInput is the body of a loan policy creation or update.
+type LoanPolicyInput struct {
+	Name        string `json:"name" binding:"required,notblank,max=100"`
+	MaxLoans    int    `json:"max_loans" binding:"min=0"`
+	LoanDays    int    `json:"loan_days" binding:"min=1"`
+	MaxRenewals int    `json:"max_renewals" binding:"min=0"`
+}
+
+func (input LoanPolicyInput) policy(id int) models.LoanPolicy {
+	return models.LoanPolicy{
+		ID:          id,
+		Name:        input.Name,
+		MaxLoans:    input.MaxLoans,
+		LoanDays:    input.LoanDays,
+		MaxRenewals: input.MaxRenewals,
+	}
+}
+
+// LoanPolicyResponse is a loan policy as the API shows it.
+type LoanPolicyResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	MaxLoans    int    `json:"max_loans"`
+	LoanDays    int    `json:"loan_days"`
+	MaxRenewals int    `json:"max_renewals"`
+}
+
+func loanPolicyResponse(policy models.LoanPolicy) LoanPolicyResponse {
+	return LoanPolicyResponse{
+		ID:          policy.ID,
+		Name:        policy.Name,
+		MaxLoans:    policy.MaxLoans,
+		LoanDays:    policy.LoanDays,
+		MaxRenewals: policy.MaxRenewals,
+	}
+}
+
+// GetLoanPolicyByID @Summary Get Loan Policy by ID
+// @Tags policies
+// @Description Get loan policy details by ID
+// @ID get-loan-policy-by-id
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Loan Policy ID"
+// @Success 200 {object} LoanPolicyResponse
+// @Router /policy/{id} [get]
+func (h *Handler) GetLoanPolicyByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid policy ID")
+		return
+	}
+
+	policy, err := h.Services.LoanPolicies.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loanPolicyResponse(policy))
+}
+
+// GetAllLoanPolicies @Summary Get All Loan Policies
+// @Tags policies
+// @Description Get list of all loan policies
+// @ID get-all-loan-policies
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} LoanPolicyResponse
+// @Router /policy [get]
+func (h *Handler) GetAllLoanPolicies(c *gin.Context) {
+	policies, err := h.Services.LoanPolicies.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	response := make([]LoanPolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		response = append(response, loanPolicyResponse(policy))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateLoanPolicy @Summary Create Loan Policy
+// @Tags policies
+// @Description Create a new loan policy
+// @ID create-loan-policy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   policy  body    LoanPolicyInput     true        "Loan Policy Info"
+// @Success 201 {object} map[string]string "status: policy created"
+// @Router /policy [post]
+func (h *Handler) CreateLoanPolicy(c *gin.Context) {
+	var input LoanPolicyInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.LoanPolicies.Create(c.Request.Context(), input.policy(0)); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "policy created"})
+}
+
+// UpdateLoanPolicy @Summary Update Loan Policy
+// @Tags policies
+// @Description Update loan policy limits by ID
+// @ID update-loan-policy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "Loan Policy ID"
+// @Param   policy  body    LoanPolicyInput     true        "Loan Policy Info"
+// @Success 200 {object} map[string]string "status: policy updated"
+// @Router /policy/{id} [put]
+func (h *Handler) UpdateLoanPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid policy ID")
+		return
+	}
+
+	var input LoanPolicyInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.LoanPolicies.Update(c.Request.Context(), input.policy(id)); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "policy updated"})
+}
+
+// DeleteLoanPolicy @Summary Delete Loan Policy
+// @Tags policies
+// @Description Delete loan policy by ID
+// @ID delete-loan-policy
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Loan Policy ID"
+// @Success 200 {object} map[string]string "status: policy deleted"
+// @Router /policy/{id} [delete]
+func (h *Handler) DeleteLoanPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid policy ID")
+		return
+	}
+
+	if err := h.Services.LoanPolicies.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "policy deleted"})
+}
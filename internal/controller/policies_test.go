@@ -0,0 +1,145 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getAllLoanPolicies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPolicyService := service.NewMockLoanPolicies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			LoanPolicies: mockPolicyService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/policy", handler.GetAllLoanPolicies)
+
+	expectedPolicies := []models.LoanPolicy{
+		{ID: 1, Name: "student", MaxLoans: 3, LoanDays: 7, MaxRenewals: 1},
+		{ID: 2, Name: "staff", MaxLoans: 20, LoanDays: 28, MaxRenewals: 5},
+	}
+	mockPolicyService.EXPECT().GetAll(gomock.Any()).Return(expectedPolicies, nil)
+
+	req, _ := http.NewRequest("GET", "/policy", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"id": 1, "name": "student", "max_loans": 3, "loan_days": 7, "max_renewals": 1},
+		{"id": 2, "name": "staff", "max_loans": 20, "loan_days": 28, "max_renewals": 5}
+	]`, w.Body.String())
+}
+
+func TestHandler_createLoanPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPolicyService := service.NewMockLoanPolicies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			LoanPolicies: mockPolicyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/policy", handler.CreateLoanPolicy)
+
+	newPolicy := models.LoanPolicy{Name: "student", MaxLoans: 3, LoanDays: 7, MaxRenewals: 1}
+	mockPolicyService.EXPECT().Create(gomock.Any(), newPolicy).Return(nil)
+
+	policyJSON, _ := json.Marshal(controller.LoanPolicyInput{Name: "student", MaxLoans: 3, LoanDays: 7, MaxRenewals: 1})
+	req, _ := http.NewRequest("POST", "/policy", bytes.NewBuffer(policyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "policy created")
+}
+
+func TestHandler_updateLoanPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPolicyService := service.NewMockLoanPolicies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			LoanPolicies: mockPolicyService,
+		},
+	}
+
+	r := setupRouter()
+	r.PUT("/policy/:id", handler.UpdateLoanPolicy)
+
+	updatedPolicy := models.LoanPolicy{ID: 1, Name: "student", MaxLoans: 4, LoanDays: 7, MaxRenewals: 1}
+	mockPolicyService.EXPECT().Update(gomock.Any(), updatedPolicy).Return(nil)
+
+	policyJSON, _ := json.Marshal(controller.LoanPolicyInput{Name: "student", MaxLoans: 4, LoanDays: 7, MaxRenewals: 1})
+	req, _ := http.NewRequest("PUT", "/policy/1", bytes.NewBuffer(policyJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "policy updated")
+}
+
+func TestHandler_deleteLoanPolicy_InvalidID(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.DELETE("/policy/:id", handler.DeleteLoanPolicy)
+
+	req, _ := http.NewRequest("DELETE", "/policy/invalid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid policy ID")
+}
+
+func TestHandler_createLoanPolicy_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPolicyService := service.NewMockLoanPolicies(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			LoanPolicies: mockPolicyService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/policy", handler.CreateLoanPolicy)
+
+	req, _ := http.NewRequest("POST", "/policy", bytes.NewBufferString(`{"name":" ","max_loans":-1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"code": "validation_failed",
+		"error": "name: must not be blank",
+		"fields": [
+			{"field": "name", "code": "notblank", "error": "must not be blank"},
+			{"field": "max_loans", "code": "min", "error": "must be at least 0"},
+			{"field": "loan_days", "code": "min", "error": "must be at least 1"}
+		]
+	}`, w.Body.String())
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"library/internal/policy"
+)
+
+// allow is middleware that authenticates a request and lets it through only
+// if the caller has one of permissions.
+func (h *Handler) allow(permissions ...policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.authenticate(c) {
+			return
+		}
+		for _, permission := range permissions {
+			if can(c, permission) {
+				c.Next()
+				return
+			}
+		}
+		forbidden(c, permissions[len(permissions)-1])
+		c.Abort()
+	}
+}
+
+// allowSelf is allow for routes about the user in their :id parameter. Callers
+// with own may use them for themselves, callers with others for everyone.
+func (h *Handler) allowSelf(own, others policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.authenticate(c) {
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		self := err == nil && id == identityOf(c).UserID
+		if !can(c, others) && !(self && can(c, own)) {
+			forbidden(c, others)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// can tells whether the caller of an authenticated request has permission.
+func can(c *gin.Context, permission policy.Permission) bool {
+	return policy.Allows(identityOf(c), permission)
+}
+
+// actingFor returns the user a loan request is made for: userID if given,
+// otherwise the caller. Acting for someone else needs others; when the
+// caller lacks it, a 403 is written and ok is false. API keys belong to no
+// user, so their requests must name one.
+func actingFor(c *gin.Context, userID int, others policy.Permission) (int, bool) {
+	identity := identityOf(c)
+	if identity.APIKeyID != 0 && userID == 0 {
+		unprocessable(c, "user_id_required", "user_id is required when calling with an API key")
+		return 0, false
+	}
+	if userID == 0 || userID == identity.UserID {
+		return identity.UserID, true
+	}
+	if !can(c, others) {
+		forbidden(c, others)
+		return 0, false
+	}
+	return userID, true
+}
+
+// forbidden writes a 403 for a caller who lacks permission.
+func forbidden(c *gin.Context, permission policy.Permission) {
+	identity := identityOf(c)
+	message := fmt.Sprintf("%s role does not allow %s", identity.Role, permission)
+	if identity.APIKeyID != 0 {
+		message = fmt.Sprintf("API key is not scoped for %s", permission)
+	}
+	c.JSON(http.StatusForbidden, ErrorResponse{Code: "forbidden", Error: message})
+}
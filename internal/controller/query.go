@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// intParam reads an optional non-negative integer query parameter, 0 when absent.
+func intParam(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return n, nil
+}
+
+// dateParam reads an optional YYYY-MM-DD query parameter.
+func dateParam(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected YYYY-MM-DD", name)
+	}
+	return &date, nil
+}
+
+// boolParam reads an optional true/false query parameter.
+func boolParam(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected true or false", name)
+	}
+	return &b, nil
+}
+
+// pageParams reads the page and page_size query parameters.
+func pageParams(c *gin.Context) (page, pageSize int, err error) {
+	if page, err = intParam(c, "page"); err != nil {
+		return 0, 0, err
+	}
+	if pageSize, err = intParam(c, "page_size"); err != nil {
+		return 0, 0, err
+	}
+	return page, pageSize, nil
+}
+
+// nextPage links to the page after page of the current listing, keeping
+// its other query parameters, or is empty on the last page.
+func nextPage(c *gin.Context, page, pageSize int, total int64) string {
+	if int64(page)*int64(pageSize) >= total {
+		return ""
+	}
+	values := c.Request.URL.Query()
+	values.Set("page", strconv.Itoa(page+1))
+	values.Set("page_size", strconv.Itoa(pageSize))
+	return c.Request.URL.Path + "?" + values.Encode()
+}
@@ -0,0 +1,229 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInitRoutes_Permissions sends a request to every route that needs a
+// token as each role. The requests are made to fail validation, so one that
+// gets past the policy layer ends in a 400 (or a 200 for routes that have
+// nothing to validate) without touching the services.
+func TestInitRoutes_Permissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	roles := []string{models.RolePatron, models.RoleLibrarian, models.RoleAdmin}
+
+	mockAuthService := service.NewMockAuth(ctrl)
+	for _, role := range roles {
+		mockAuthService.EXPECT().Authenticate(gomock.Any(), role).Return(models.Identity{UserID: 1, Role: role}, nil).AnyTimes()
+	}
+	mockBookService := service.NewMockBooks(ctrl)
+	mockBookService.EXPECT().GetOverdue(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockHoldService := service.NewMockHolds(ctrl)
+	mockHoldService.EXPECT().GetByUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFineService := service.NewMockFines(ctrl)
+	mockFineService.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Return(models.FineBalance{}, nil).AnyTimes()
+	mockUserService := service.NewMockUsers(ctrl)
+	mockUserService.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(models.User{}, nil).AnyTimes()
+
+	handler := controller.NewHandler(&service.Service{
+		Auth:  mockAuthService,
+		Books: mockBookService,
+		Holds: mockHoldService,
+		Fines: mockFineService,
+		Users: mockUserService,
+	}, 0)
+	router := handler.InitRoutes()
+
+	var (
+		everyone = roles
+		staff    = []string{models.RoleLibrarian, models.RoleAdmin}
+		admin    = []string{models.RoleAdmin}
+	)
+
+	routes := []struct {
+		method  string
+		path    string
+		allowed []string
+	}{
+		// the catalogue
+		{"POST", "/api/author/", staff},
+		{"PUT", "/api/author/x", staff},
+		{"PATCH", "/api/author/x", staff},
+		{"DELETE", "/api/author/x", staff},
+		{"POST", "/api/book/", staff},
+		{"PUT", "/api/book/x", staff},
+		{"PATCH", "/api/book/x", staff},
+		{"DELETE", "/api/book/x", staff},
+		{"POST", "/api/book/x/copies", staff},
+		{"PUT", "/api/copy/x", staff},
+		{"DELETE", "/api/copy/x", staff},
+		{"POST", "/api/subject/", staff},
+		{"PUT", "/api/subject/x", staff},
+		{"DELETE", "/api/subject/x", staff},
+		{"PUT", "/api/tag/x", staff},
+		{"DELETE", "/api/tag/x", staff},
+
+		// loans of the caller
+		{"POST", "/api/rent/", everyone},
+		{"POST", "/api/rent/return", everyone},
+		{"POST", "/api/rent/renew", everyone},
+		{"POST", "/api/hold/", everyone},
+		{"GET", "/api/hold/x", everyone},
+		{"DELETE", "/api/hold/x", everyone},
+		{"GET", "/api/user/1", everyone},
+		{"GET", "/api/user/1/loans?status=x", everyone},
+		{"GET", "/api/user/1/holds", everyone},
+		{"GET", "/api/user/1/fines", everyone},
+
+		// loans of everyone
+		{"GET", "/api/user/2/loans?status=x", staff},
+		{"GET", "/api/user/2/holds", staff},
+		{"GET", "/api/user/2/fines", staff},
+		{"GET", "/api/book/x/holds", staff},
+		{"GET", "/api/book/x/loans", staff},
+		{"GET", "/api/rent/overdue", staff},
+
+		// fines
+		{"POST", "/api/user/x/payments", staff},
+		{"POST", "/api/fine/x/waive", staff},
+
+		// users and policies
+		{"GET", "/api/user/2", staff},
+		{"GET", "/api/user/?page=x", staff},
+		{"POST", "/api/user/", admin},
+		{"PUT", "/api/user/x", admin},
+		{"PATCH", "/api/user/x", admin},
+		{"PUT", "/api/user/x/role", admin},
+		{"DELETE", "/api/user/x", admin},
+		{"POST", "/api/policy/", admin},
+		{"PUT", "/api/policy/x", admin},
+		{"DELETE", "/api/policy/x", admin},
+		{"POST", "/api/api-key/", admin},
+		{"DELETE", "/api/api-key/x", admin},
+
+		// the archive
+		{"GET", "/api/author/deleted?page=x", admin},
+		{"POST", "/api/author/x/restore", admin},
+		{"GET", "/api/book/deleted?page=x", admin},
+		{"POST", "/api/book/x/restore", admin},
+		{"GET", "/api/user/deleted?page=x", admin},
+		{"POST", "/api/user/x/restore", admin},
+	}
+
+	for _, route := range routes {
+		for _, role := range roles {
+			req, _ := http.NewRequest(route.method, route.path, bytes.NewBufferString("invalid json"))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+role)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if contains(route.allowed, role) {
+				assert.Contains(t, []int{http.StatusOK, http.StatusBadRequest}, w.Code, "%s %s as %s", route.method, route.path, role)
+			} else {
+				assert.Equal(t, http.StatusForbidden, w.Code, "%s %s as %s", route.method, route.path, role)
+			}
+		}
+
+		req, _ := http.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s without a token", route.method, route.path)
+	}
+}
+
+// TestInitRoutes_APIKeys checks that API keys reach the routes their scopes
+// allow, and only those.
+func TestInitRoutes_APIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyService := service.NewMockAPIKeys(ctrl)
+	mockAPIKeyService.EXPECT().Authenticate(gomock.Any(), "kiosk").
+		Return(models.Identity{APIKeyID: 1, Scopes: []string{"catalog:read", "loans:write"}}, nil).AnyTimes()
+	mockAPIKeyService.EXPECT().Authenticate(gomock.Any(), "revoked").
+		Return(models.Identity{}, &service.Error{Kind: service.ErrUnauthenticated, Code: "invalid_api_key", Message: "API key is not valid"}).AnyTimes()
+
+	router := controller.NewHandler(&service.Service{APIKeys: mockAPIKeyService}, 0).InitRoutes()
+
+	routes := []struct {
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"POST", "/api/rent/", "kiosk", http.StatusBadRequest},
+		{"POST", "/api/rent/return", "kiosk", http.StatusBadRequest},
+		{"POST", "/api/hold/", "kiosk", http.StatusBadRequest},
+		{"GET", "/api/rent/overdue", "kiosk", http.StatusForbidden},
+		{"GET", "/api/user/1/loans", "kiosk", http.StatusForbidden},
+		{"POST", "/api/book/", "kiosk", http.StatusForbidden},
+		{"GET", "/api/api-key/", "kiosk", http.StatusForbidden},
+		{"POST", "/api/rent/", "revoked", http.StatusUnauthorized},
+	}
+
+	for _, route := range routes {
+		req, _ := http.NewRequest(route.method, route.path, bytes.NewBufferString("invalid json"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "ApiKey "+route.key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, route.status, w.Code, "%s %s with %s", route.method, route.path, route.key)
+	}
+}
+
+func TestInitRoutes_CatalogueIsOpen(t *testing.T) {
+	router := controller.NewHandler(&service.Service{}, 0).InitRoutes()
+
+	for _, path := range []string{"/api/author/x", "/api/book/x", "/api/copy/x", "/api/subject/x", "/api/policy/x", "/api/search?page=x"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestInitRoutes_QueryTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	mockBookService.EXPECT().GetByID(gomock.Any(), 1).DoAndReturn(func(ctx context.Context, id int) (models.Book, error) {
+		<-ctx.Done()
+		return models.Book{}, fmt.Errorf("get book: %w", ctx.Err())
+	})
+
+	router := controller.NewHandler(&service.Service{Books: mockBookService}, 10*time.Millisecond).InitRoutes()
+
+	req, _ := http.NewRequest("GET", "/api/book/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"code":"timeout","error":"request timed out"}`, w.Body.String())
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
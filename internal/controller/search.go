@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// SearchHitResponse is a book found by a search. Contributors are the
+// names of its contributors in order; the snippets wrap the matched words in
+// <mark> tags.
+type SearchHitResponse struct {
+	BookID              int     `json:"book_id"`
+	Title               string  `json:"title"`
+	ISBN                string  `json:"isbn"`
+	Contributors        string  `json:"contributors"`
+	Rank                float64 `json:"rank"`
+	TitleSnippet        string  `json:"title_snippet"`
+	ContributorsSnippet string  `json:"contributors_snippet"`
+}
+
+// SearchPageResponse is a page of search hits, best first. Next links to
+// the following page and is empty on the last one.
+type SearchPageResponse struct {
+	Hits     []SearchHitResponse `json:"hits"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+	Next     string              `json:"next"`
+}
+
+func searchPageResponse(c *gin.Context, results models.SearchPage) SearchPageResponse {
+	response := SearchPageResponse{
+		Hits:     make([]SearchHitResponse, 0, len(results.Hits)),
+		Total:    results.Total,
+		Page:     results.Page,
+		PageSize: results.PageSize,
+		Next:     nextPage(c, results.Page, results.PageSize, results.Total),
+	}
+	for _, hit := range results.Hits {
+		response.Hits = append(response.Hits, SearchHitResponse{
+			BookID:              hit.BookID,
+			Title:               hit.Title,
+			ISBN:                hit.ISBN,
+			Contributors:        hit.Contributors,
+			Rank:                hit.Rank,
+			TitleSnippet:        hit.TitleSnippet,
+			ContributorsSnippet: hit.ContributorsSnippet,
+		})
+	}
+	return response
+}
+
+// SearchBooks @Summary Search the catalogue
+// @Tags search
+// @Description Find books by words or word prefixes of their title or author name, or by ISBN. Close misspellings match too. Snippets wrap the matched words in <mark> tags.
+// @ID search-books
+// @Accept  json
+// @Produce  json
+// @Param   q    query    string     true        "Search text"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Results per page, at most 100"
+// @Success 200 {object} SearchPageResponse
+// @Failure 422 {object} map[string]string "query is empty or too long"
+// @Router /search [get]
+func (h *Handler) SearchBooks(c *gin.Context) {
+	query := models.SearchQuery{Text: c.Query("q")}
+
+	var err error
+	if query.Page, query.PageSize, err = pageParams(c); err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+
+	results, err := h.Services.Books.Search(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, searchPageResponse(c, results))
+}
@@ -0,0 +1,80 @@
+package controller_test
+
+import (
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_searchBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/search", handler.SearchBooks)
+
+	hits := []models.SearchHit{
+		{BookID: 1, Title: "War and Peace", Contributors: "Leo Tolstoy, Louise Maude", TitleSnippet: "<mark>War</mark> and Peace", ContributorsSnippet: "Leo Tolstoy, Louise Maude"},
+	}
+	mockBookService.EXPECT().Search(gomock.Any(), models.SearchQuery{Text: "war", PageSize: 1}).
+		Return(models.SearchPage{Hits: hits, Total: 3, Page: 1, PageSize: 1}, nil)
+
+	req, _ := http.NewRequest("GET", "/search?q=war&page_size=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"hits": [{
+			"book_id": 1,
+			"title": "War and Peace",
+			"isbn": "",
+			"contributors": "Leo Tolstoy, Louise Maude",
+			"rank": 0,
+			"title_snippet": "<mark>War</mark> and Peace",
+			"contributors_snippet": "Leo Tolstoy, Louise Maude"
+		}],
+		"total": 3,
+		"page": 1,
+		"page_size": 1,
+		"next": "/search?page=2&page_size=1&q=war"
+	}`, w.Body.String())
+}
+
+func TestHandler_searchBooks_EmptyQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Books: mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/search", handler.SearchBooks)
+
+	mockBookService.EXPECT().Search(gomock.Any(), models.SearchQuery{}).
+		Return(models.SearchPage{}, &service.Error{Kind: service.ErrValidation, Code: "query_required", Message: "search query is required"})
+
+	req, _ := http.NewRequest("GET", "/search", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"code":"query_required","error":"search query is required"}`, w.Body.String())
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// SubjectInput is the body of a subject creation or update. A subject
+// without ParentID is at the top level.
+type SubjectInput struct {
+	Name     string `json:"name" binding:"required,notblank,max=200"`
+	ParentID *int   `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+func (input SubjectInput) subject(id int) models.Subject {
+	return models.Subject{ID: id, Name: input.Name, ParentID: input.ParentID}
+}
+
+// GetAllSubjects @Summary Get Subject Tree
+// @Tags subjects
+// @Description Get the whole subject and genre taxonomy as a tree of top-level subjects with their children
+// @ID get-all-subjects
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Subject
+// @Router /subject [get]
+func (h *Handler) GetAllSubjects(c *gin.Context) {
+	subjects, err := h.Services.Subjects.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subjects)
+}
+
+// GetSubjectByID @Summary Get Subject by ID
+// @Tags subjects
+// @Description Get a subject with its direct children
+// @ID get-subject-by-id
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Subject ID"
+// @Success 200 {object} models.Subject
+// @Router /subject/{id} [get]
+func (h *Handler) GetSubjectByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid subject ID")
+		return
+	}
+
+	subject, err := h.Services.Subjects.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subject)
+}
+
+// GetSubjectBooks @Summary Browse Subject
+// @Tags subjects
+// @Description Get a page of the books filed under a subject or any subject below it. Takes the filters of the book listing.
+// @ID get-subject-books
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Subject ID"
+// @Param   title    query    string     false        "Part of the title, case-insensitive"
+// @Param   tag    query    string     false        "Tag name"
+// @Param   sort    query    string     false        "id, title or published_at, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Books per page, at most 100"
+// @Success 200 {object} BookPageResponse
+// @Router /subject/{id}/books [get]
+func (h *Handler) GetSubjectBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid subject ID")
+		return
+	}
+
+	query, err := bookQuery(c)
+	if err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+	query.SubjectID = id
+
+	if _, err := h.Services.Subjects.GetByID(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	books, err := h.Services.Books.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, bookPageResponse(c, books))
+}
+
+// CreateSubject @Summary Create Subject
+// @Tags subjects
+// @Description Create a subject, at the top level or under parent_id
+// @ID create-subject
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   subject  body    SubjectInput     true        "Subject Info"
+// @Success 201 {object} map[string]string "status: subject created"
+// @Router /subject [post]
+func (h *Handler) CreateSubject(c *gin.Context) {
+	var input SubjectInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Subjects.Create(c.Request.Context(), input.subject(0)); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "subject created"})
+}
+
+// UpdateSubject @Summary Update Subject
+// @Tags subjects
+// @Description Rename a subject or move it, with the subjects below it, under another parent
+// @ID update-subject
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "Subject ID"
+// @Param   subject    body    SubjectInput     true        "Subject Info"
+// @Success 200 {object} map[string]string "status: subject updated"
+// @Router /subject/{id} [put]
+func (h *Handler) UpdateSubject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid subject ID")
+		return
+	}
+
+	var input SubjectInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Subjects.Update(c.Request.Context(), input.subject(id)); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "subject updated"})
+}
+
+// DeleteSubject @Summary Delete Subject
+// @Tags subjects
+// @Description Delete a subject that has no subjects below it and no books filed under it
+// @ID delete-subject
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Subject ID"
+// @Success 200 {object} map[string]string "status: subject deleted"
+// @Router /subject/{id} [delete]
+func (h *Handler) DeleteSubject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid subject ID")
+		return
+	}
+
+	if err := h.Services.Subjects.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "subject deleted"})
+}
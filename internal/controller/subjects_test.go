@@ -0,0 +1,155 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getAllSubjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjectService := service.NewMockSubjects(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Subjects: mockSubjectService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/subject", handler.GetAllSubjects)
+
+	fiction := 1
+	expectedSubjects := []models.Subject{
+		{ID: 1, Name: "Fiction", Children: []models.Subject{{ID: 2, Name: "Fantasy", ParentID: &fiction}}},
+	}
+	mockSubjectService.EXPECT().GetAll(gomock.Any()).Return(expectedSubjects, nil)
+
+	req, _ := http.NewRequest("GET", "/subject", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var subjects []models.Subject
+	err := json.Unmarshal(w.Body.Bytes(), &subjects)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSubjects, subjects)
+}
+
+func TestHandler_getSubjectBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjectService := service.NewMockSubjects(ctrl)
+	mockBookService := service.NewMockBooks(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Subjects: mockSubjectService,
+			Books:    mockBookService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/subject/:id/books", handler.GetSubjectBooks)
+
+	expectedBooks := []models.Book{{ID: 1, Title: "Book 1"}}
+	mockSubjectService.EXPECT().GetByID(gomock.Any(), 1).Return(models.Subject{ID: 1, Name: "Fiction"}, nil)
+	mockBookService.EXPECT().GetAll(gomock.Any(), models.BookQuery{SubjectID: 1, Tag: "classic"}).
+		Return(models.BookPage{Books: expectedBooks, Total: 1, Page: 1, PageSize: 20}, nil)
+
+	req, _ := http.NewRequest("GET", "/subject/1/books?tag=classic", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page controller.BookPageResponse
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	assert.NoError(t, err)
+	assert.Len(t, page.Books, 1)
+	assert.Equal(t, "Book 1", page.Books[0].Title)
+}
+
+func TestHandler_getSubjectBooks_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjectService := service.NewMockSubjects(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Subjects: mockSubjectService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/subject/:id/books", handler.GetSubjectBooks)
+
+	mockSubjectService.EXPECT().GetByID(gomock.Any(), 9).Return(models.Subject{}, &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "subject not found"})
+
+	req, _ := http.NewRequest("GET", "/subject/9/books", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","error":"subject not found"}`, w.Body.String())
+}
+
+func TestHandler_createSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjectService := service.NewMockSubjects(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Subjects: mockSubjectService,
+		},
+	}
+
+	r := setupRouter()
+	r.POST("/subject", handler.CreateSubject)
+
+	parent := 1
+	newSubject := models.Subject{Name: "Fantasy", ParentID: &parent}
+	mockSubjectService.EXPECT().Create(gomock.Any(), newSubject).Return(nil)
+
+	subjectJSON, _ := json.Marshal(controller.SubjectInput{Name: "Fantasy", ParentID: &parent})
+	req, _ := http.NewRequest("POST", "/subject", bytes.NewBuffer(subjectJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "subject created")
+}
+
+func TestHandler_deleteSubject_InUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjectService := service.NewMockSubjects(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Subjects: mockSubjectService,
+		},
+	}
+
+	r := setupRouter()
+	r.DELETE("/subject/:id", handler.DeleteSubject)
+
+	mockSubjectService.EXPECT().Delete(gomock.Any(), 1).Return(&service.Error{Kind: service.ErrConflict, Code: "in_use", Message: "subject is still in use"})
+
+	req, _ := http.NewRequest("DELETE", "/subject/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"code":"in_use","error":"subject is still in use"}`, w.Body.String())
+}
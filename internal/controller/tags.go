@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"library/models"
+)
+
+// TagInput is the body of a tag rename.
+type TagInput struct {
+	Name string `json:"name" binding:"required,notblank,max=50"`
+}
+
+// GetAllTags @Summary Get All Tags
+// @Tags tags
+// @Description Get every tag in use, ordered by name. Tags are created by adding them to a book.
+// @ID get-all-tags
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.Tag
+// @Router /tag [get]
+func (h *Handler) GetAllTags(c *gin.Context) {
+	tags, err := h.Services.Tags.GetAll(c.Request.Context())
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// UpdateTag @Summary Rename Tag
+// @Tags tags
+// @Description Rename a tag on every book it is on
+// @ID update-tag
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "Tag ID"
+// @Param   tag    body    TagInput     true        "Tag Info"
+// @Success 200 {object} map[string]string "status: tag updated"
+// @Router /tag/{id} [put]
+func (h *Handler) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid tag ID")
+		return
+	}
+
+	var input TagInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Tags.Update(c.Request.Context(), models.Tag{ID: id, Name: input.Name}); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "tag updated"})
+}
+
+// DeleteTag @Summary Delete Tag
+// @Tags tags
+// @Description Delete a tag and remove it from every book
+// @ID delete-tag
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "Tag ID"
+// @Success 200 {object} map[string]string "status: tag deleted"
+// @Router /tag/{id} [delete]
+func (h *Handler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid tag ID")
+		return
+	}
+
+	if err := h.Services.Tags.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "tag deleted"})
+}
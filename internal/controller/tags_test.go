@@ -0,0 +1,82 @@
+package controller_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"library/internal/controller"
+	"library/internal/service"
+	"library/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getAllTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagService := service.NewMockTags(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Tags: mockTagService,
+		},
+	}
+
+	r := setupRouter()
+	r.GET("/tag", handler.GetAllTags)
+
+	expectedTags := []models.Tag{{ID: 2, Name: "classic"}, {ID: 1, Name: "russian"}}
+	mockTagService.EXPECT().GetAll(gomock.Any()).Return(expectedTags, nil)
+
+	req, _ := http.NewRequest("GET", "/tag", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tags []models.Tag
+	err := json.Unmarshal(w.Body.Bytes(), &tags)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTags, tags)
+}
+
+func TestHandler_updateTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTagService := service.NewMockTags(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Tags: mockTagService,
+		},
+	}
+
+	r := setupRouter()
+	r.PUT("/tag/:id", handler.UpdateTag)
+
+	mockTagService.EXPECT().Update(gomock.Any(), models.Tag{ID: 1, Name: "Classics"}).Return(nil)
+
+	req, _ := http.NewRequest("PUT", "/tag/1", bytes.NewBufferString(`{"name":"Classics"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "tag updated")
+}
+
+func TestHandler_deleteTag_InvalidID(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.DELETE("/tag/:id", handler.DeleteTag)
+
+	req, _ := http.NewRequest("DELETE", "/tag/invalid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid tag ID")
+}
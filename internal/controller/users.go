@@ -3,72 +3,158 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"library/models"
 )
 
+// CreateUserInput is the body of a user creation. Without a role the user
+// is a patron, without a loan policy the default policy applies.
+type CreateUserInput struct {
+	Name         string `json:"name" binding:"required,notblank,max=200"`
+	Email        string `json:"email" binding:"required,email,max=254"`
+	Role         string `json:"role"`
+	LoanPolicyID *int   `json:"loan_policy_id" binding:"omitempty,min=1"`
+}
+
+// UpdateUserInput is the body of a user update.
+type UpdateUserInput struct {
+	Name         string `json:"name" binding:"required,notblank,max=200"`
+	Email        string `json:"email" binding:"required,email,max=254"`
+	LoanPolicyID *int   `json:"loan_policy_id" binding:"omitempty,min=1"`
+}
+
+// UserResponse is a user as the API shows it. LoanPolicy is the name of
+// their loan policy, empty when the default applies. Loans are listed by
+// /user/{id}/loans.
+type UserResponse struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	LoanPolicyID *int       `json:"loan_policy_id"`
+	LoanPolicy   string     `json:"loan_policy,omitempty"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+func userResponse(user models.User) UserResponse {
+	return UserResponse{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Role:         user.Role,
+		LoanPolicyID: user.LoanPolicyID,
+		LoanPolicy:   user.LoanPolicy.Name,
+		DeletedAt:    user.DeletedAt,
+	}
+}
+
+// UserPageResponse is a page of the user listing. Next links to the
+// following page and is empty on the last one.
+type UserPageResponse struct {
+	Users    []UserResponse `json:"users"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Next     string         `json:"next"`
+}
+
+func userPageResponse(c *gin.Context, users models.UserPage) UserPageResponse {
+	response := UserPageResponse{
+		Users:    make([]UserResponse, 0, len(users.Users)),
+		Total:    users.Total,
+		Page:     users.Page,
+		PageSize: users.PageSize,
+		Next:     nextPage(c, users.Page, users.PageSize, users.Total),
+	}
+	for _, user := range users.Users {
+		response.Users = append(response.Users, userResponse(user))
+	}
+	return response
+}
+
 // GetUserByID @Summary Get User by ID
 // @Tags users
 // @Description Get user details by ID
 // @ID get-user-by-id
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "User ID"
-// @Success 200 {object} models.User
+// @Success 200 {object} UserResponse
 // @Router /user/{id} [get]
 func (h *Handler) GetUserByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		badRequest(c, "invalid_id", "invalid user ID")
 		return
 	}
 
-	user, err := h.Services.Users.GetByID(id)
+	user, err := h.Services.Users.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		errorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, userResponse(user))
 }
 
 // GetAllUsers @Summary Get All Users
 // @Tags users
-// @Description Get list of all users
+// @Description Get a page of users, optionally filtered and sorted. Loans are listed by /user/{id}/loans.
 // @ID get-all-users
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} models.User
+// @Param   name    query    string     false        "Part of the name, case-insensitive"
+// @Param   email    query    string     false        "Part of the email, case-insensitive"
+// @Param   sort    query    string     false        "id, name or email, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Users per page, at most 100"
+// @Success 200 {object} UserPageResponse
 // @Router /user [get]
 func (h *Handler) GetAllUsers(c *gin.Context) {
-	users, err := h.Services.Users.GetAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	query := models.UserQuery{Name: c.Query("name"), Email: c.Query("email"), Sort: c.Query("sort")}
+
+	var err error
+	if query.Page, query.PageSize, err = pageParams(c); err != nil {
+		badRequest(c, "invalid_query", err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	users, err := h.Services.Users.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, userPageResponse(c, users))
 }
 
 // CreateUser @Summary Create User
 // @Tags users
 // @Description Create a new user
 // @ID create-user
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
-// @Param   user  body    models.User     true        "User Info"
+// @Param   user  body    CreateUserInput     true        "User Info"
 // @Success 201 {object} map[string]string "status: user created"
+// @Failure 409 {object} ErrorResponse "email is taken"
+// @Failure 422 {object} ErrorResponse "fields, role or loan policy are not valid"
 // @Router /user [post]
 func (h *Handler) CreateUser(c *gin.Context) {
-	var input models.User
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	var input CreateUserInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	if err := h.Services.Users.Create(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	user := models.User{Name: input.Name, Email: input.Email, Role: input.Role, LoanPolicyID: input.LoanPolicyID}
+	if err := h.Services.Users.Create(c.Request.Context(), user); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
@@ -77,56 +163,202 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 // UpdateUser @Summary Update User
 // @Tags users
-// @Description Update user details by ID
+// @Description Replace the details of a user by ID. Use PATCH to change only some fields. The role is changed with /user/{id}/role.
 // @ID update-user
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id      path    int     true        "User ID"
-// @Param   user    body    models.User     true        "User Info"
+// @Param   user    body    UpdateUserInput     true        "User Info"
 // @Success 200 {object} map[string]string "status: user updated"
+// @Failure 409 {object} ErrorResponse "email is taken"
+// @Failure 422 {object} ErrorResponse "fields or loan policy are not valid"
 // @Router /user/{id} [put]
 func (h *Handler) UpdateUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	var input UpdateUserInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	user := models.User{ID: id, Name: input.Name, Email: input.Email, LoanPolicyID: input.LoanPolicyID}
+	if err := h.Services.Users.Update(c.Request.Context(), user); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "user updated"})
+}
+
+// PatchUser @Summary Patch User
+// @Tags users
+// @Description Change some details of a user by ID with a JSON merge patch (RFC 7386), or a JSON Patch (RFC 6902) sent as application/json-patch+json. The patched user is checked as on PUT. The role is changed with /user/{id}/role.
+// @ID patch-user
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json,application/merge-patch+json,application/json-patch+json
+// @Produce  json
+// @Param   id      path    int     true        "User ID"
+// @Param   patch   body    UpdateUserInput     true        "Fields to change"
+// @Success 200 {object} map[string]string "status: user updated"
+// @Failure 409 {object} ErrorResponse "email is taken, or a test operation of the patch failed"
+// @Failure 415 {object} ErrorResponse "body is not a patch"
+// @Failure 422 {object} ErrorResponse "patched fields or loan policy are not valid"
+// @Router /user/{id} [patch]
+func (h *Handler) PatchUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
 		return
 	}
 
-	var input models.User
-	if err := c.BindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	user, err := h.Services.Users.GetByID(c.Request.Context(), id)
+	if err != nil {
+		errorResponse(c, err)
 		return
 	}
 
-	input.ID = id
-	if err := h.Services.Users.Update(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	current := UpdateUserInput{Name: user.Name, Email: user.Email, LoanPolicyID: user.LoanPolicyID}
+	var input UpdateUserInput
+	if !bindPatch(c, current, &input) {
+		return
+	}
+
+	user = models.User{ID: id, Name: input.Name, Email: input.Email, LoanPolicyID: input.LoanPolicyID}
+	if err := h.Services.Users.Update(c.Request.Context(), user); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "user updated"})
 }
 
+// RoleInput is the body of a role change.
+type RoleInput struct {
+	Role string `json:"role"`
+}
+
+// SetUserRole @Summary Set User Role
+// @Tags users
+// @Description Make a user a patron, librarian or admin. New tokens of the user carry the role; tokens already issued keep the old one until they expire.
+// @ID set-user-role
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id      path    int     true        "User ID"
+// @Param   role    body    RoleInput     true        "Role"
+// @Success 200 {object} map[string]string "status: role changed"
+// @Failure 422 {object} ErrorResponse "unknown role"
+// @Router /user/{id}/role [put]
+func (h *Handler) SetUserRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	var input RoleInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.Services.Users.SetRole(c.Request.Context(), id, input.Role); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "role changed"})
+}
+
 // DeleteUser @Summary Delete User
 // @Tags users
-// @Description Delete user by ID
+// @Description Archive user by ID. They can no longer log in, but their loans, holds and fines are kept and an admin can restore them.
 // @ID delete-user
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept  json
 // @Produce  json
 // @Param   id    path    int     true        "User ID"
 // @Success 200 {object} map[string]string "status: user deleted"
+// @Failure 409 {object} ErrorResponse "user has books out on loan or on hold"
 // @Router /user/{id} [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		badRequest(c, "invalid_id", "invalid user ID")
 		return
 	}
 
-	if err := h.Services.Users.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Services.Users.Delete(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "user deleted"})
 }
+
+// GetDeletedUsers @Summary Get Deleted Users
+// @Tags users
+// @Description Get a page of archived users, filtered and sorted as the user listing
+// @ID get-deleted-users
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   name    query    string     false        "Part of the name, case-insensitive"
+// @Param   email    query    string     false        "Part of the email, case-insensitive"
+// @Param   sort    query    string     false        "id, name or email, prefixed with - for descending"
+// @Param   page    query    int     false        "Page number, starting at 1"
+// @Param   page_size    query    int     false        "Users per page, at most 100"
+// @Success 200 {object} UserPageResponse
+// @Router /user/deleted [get]
+func (h *Handler) GetDeletedUsers(c *gin.Context) {
+	query := models.UserQuery{Name: c.Query("name"), Email: c.Query("email"), Deleted: true, Sort: c.Query("sort")}
+
+	var err error
+	if query.Page, query.PageSize, err = pageParams(c); err != nil {
+		badRequest(c, "invalid_query", err.Error())
+		return
+	}
+
+	users, err := h.Services.Users.GetAll(c.Request.Context(), query)
+	if err != nil {
+		errorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, userPageResponse(c, users))
+}
+
+// RestoreUser @Summary Restore User
+// @Tags users
+// @Description Bring back an archived user by ID
+// @ID restore-user
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path    int     true        "User ID"
+// @Success 200 {object} map[string]string "status: user restored"
+// @Failure 404 {object} ErrorResponse "no archived user has this ID"
+// @Router /user/{id}/restore [post]
+func (h *Handler) RestoreUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequest(c, "invalid_id", "invalid user ID")
+		return
+	}
+
+	if err := h.Services.Users.Restore(c.Request.Context(), id); err != nil {
+		errorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "user restored"})
+}
@@ -3,7 +3,6 @@ package controller_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"library/internal/controller"
 	"library/internal/service"
 	"library/models"
@@ -35,19 +34,33 @@ func TestHandler_getUserByID(t *testing.T) {
 	r := setupRouter()
 	r.GET("/user/:id", handler.GetUserByID)
 
-	expectedUser := models.User{ID: 1, Name: "User 1"}
+	policyID := 2
+	user := models.User{
+		ID:           1,
+		Name:         "User 1",
+		Email:        "user1@example.com",
+		Role:         models.RolePatron,
+		LoanPolicyID: &policyID,
+		LoanPolicy:   models.LoanPolicy{ID: 2, Name: "student"},
+		RentedBooks:  []models.RentedBook{{ID: 3, UserID: 1, BookID: 4}},
+		PasswordHash: "$2a$10$hash",
+	}
 
-	mockUserService.EXPECT().GetByID(1).Return(expectedUser, nil)
+	mockUserService.EXPECT().GetByID(gomock.Any(), 1).Return(user, nil)
 
 	req, _ := http.NewRequest("GET", "/user/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var user models.User
-	err := json.Unmarshal(w.Body.Bytes(), &user)
-	assert.NoError(t, err)
-	assert.Equal(t, expectedUser, user)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"name": "User 1",
+		"email": "user1@example.com",
+		"role": "patron",
+		"loan_policy_id": 2,
+		"loan_policy": "student"
+	}`, w.Body.String())
 }
 
 func TestHandler_getUserByID_InvalidID(t *testing.T) {
@@ -78,14 +91,14 @@ func TestHandler_getUserByID_NotFound(t *testing.T) {
 	r := setupRouter()
 	r.GET("/user/:id", handler.GetUserByID)
 
-	mockUserService.EXPECT().GetByID(1).Return(models.User{}, errors.New("user not found"))
+	mockUserService.EXPECT().GetByID(gomock.Any(), 1).Return(models.User{}, &service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "user not found"})
 
 	req, _ := http.NewRequest("GET", "/user/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "user not found")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","error":"user not found"}`, w.Body.String())
 }
 
 func TestHandler_getAllUsers(t *testing.T) {
@@ -107,17 +120,19 @@ func TestHandler_getAllUsers(t *testing.T) {
 		{ID: 2, Name: "User 2"},
 	}
 
-	mockUserService.EXPECT().GetAll().Return(expectedUsers, nil)
+	mockUserService.EXPECT().GetAll(gomock.Any(), models.UserQuery{}).
+		Return(models.UserPage{Users: expectedUsers, Total: 2, Page: 1, PageSize: 20}, nil)
 
 	req, _ := http.NewRequest("GET", "/user", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var users []models.User
-	err := json.Unmarshal(w.Body.Bytes(), &users)
+	var page controller.UserPageResponse
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedUsers, users)
+	assert.Equal(t, []controller.UserResponse{{ID: 1, Name: "User 1"}, {ID: 2, Name: "User 2"}}, page.Users)
+	assert.Empty(t, page.Next)
 }
 
 func TestHandler_createUser(t *testing.T) {
@@ -134,11 +149,12 @@ func TestHandler_createUser(t *testing.T) {
 	r := setupRouter()
 	r.POST("/user", handler.CreateUser)
 
-	newUser := models.User{Name: "New User"}
-	mockUserService.EXPECT().Create(newUser).Return(nil)
+	policyID := 2
+	newUser := models.User{Name: "New User", Email: "new@example.com", Role: models.RoleLibrarian, LoanPolicyID: &policyID}
+	mockUserService.EXPECT().Create(gomock.Any(), newUser).Return(nil)
 
-	userJSON, _ := json.Marshal(newUser)
-	req, _ := http.NewRequest("POST", "/user", bytes.NewBuffer(userJSON))
+	body := `{"id":7,"name":"New User","email":"new@example.com","role":"librarian","loan_policy_id":2,"RentedBooks":[{"BookID":1}]}`
+	req, _ := http.NewRequest("POST", "/user", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -147,6 +163,28 @@ func TestHandler_createUser(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "user created")
 }
 
+func TestHandler_createUser_InvalidFields(t *testing.T) {
+	handler := &controller.Handler{}
+
+	r := setupRouter()
+	r.POST("/user", handler.CreateUser)
+
+	req, _ := http.NewRequest("POST", "/user", bytes.NewBufferString(`{"email":"not an email","loan_policy_id":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var response controller.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "validation_failed", response.Code)
+	assert.Equal(t, []controller.FieldError{
+		{Field: "name", Code: "required", Error: "is required"},
+		{Field: "email", Code: "email", Error: "must be a valid email address"},
+		{Field: "loan_policy_id", Code: "min", Error: "must be at least 1"},
+	}, response.Fields)
+}
+
 func TestHandler_createUser_InvalidInput(t *testing.T) {
 	handler := &controller.Handler{}
 
@@ -176,11 +214,12 @@ func TestHandler_updateUser(t *testing.T) {
 	r := setupRouter()
 	r.PUT("/user/:id", handler.UpdateUser)
 
-	updatedUser := models.User{ID: 1, Name: "Updated User"}
-	mockUserService.EXPECT().Update(updatedUser).Return(nil)
+	updatedUser := models.User{ID: 1, Name: "Updated User", Email: "updated@example.com"}
+	mockUserService.EXPECT().Update(gomock.Any(), updatedUser).Return(nil)
 
-	userJSON, _ := json.Marshal(updatedUser)
-	req, _ := http.NewRequest("PUT", "/user/1", bytes.NewBuffer(userJSON))
+	// the role is not changed here, see setUserRole
+	body := `{"name":"Updated User","email":"updated@example.com","role":"admin"}`
+	req, _ := http.NewRequest("PUT", "/user/1", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -235,7 +274,7 @@ func TestHandler_deleteUser(t *testing.T) {
 	r := setupRouter()
 	r.DELETE("/user/:id", handler.DeleteUser)
 
-	mockUserService.EXPECT().Delete(1).Return(nil)
+	mockUserService.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 
 	req, _ := http.NewRequest("DELETE", "/user/1", nil)
 	w := httptest.NewRecorder()
@@ -273,12 +312,37 @@ func TestHandler_deleteUser_NotFound(t *testing.T) {
 	r := setupRouter()
 	r.DELETE("/user/:id", handler.DeleteUser)
 
-	mockUserService.EXPECT().Delete(1).Return(errors.New("user not found"))
+	mockUserService.EXPECT().Delete(gomock.Any(), 1).Return(&service.Error{Kind: service.ErrNotFound, Code: "not_found", Message: "user not found"})
 
 	req, _ := http.NewRequest("DELETE", "/user/1", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 	assert.Contains(t, w.Body.String(), "user not found")
 }
+
+func TestHandler_setUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := service.NewMockUsers(ctrl)
+	handler := &controller.Handler{
+		Services: &service.Service{
+			Users: mockUserService,
+		},
+	}
+
+	r := setupRouter()
+	r.PUT("/user/:id/role", handler.SetUserRole)
+
+	mockUserService.EXPECT().SetRole(gomock.Any(), 3, models.RoleLibrarian).Return(nil)
+
+	req, _ := http.NewRequest("PUT", "/user/3/role", bytes.NewBufferString(`{"role":"librarian"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "role changed")
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError tells what is wrong with one field of a request body. Field is
+// its path in the JSON, such as contributors[0].author_id, and Code the
+// rule it breaks.
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+func init() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// name fields in errors as clients know them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	_ = validate.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+	_ = validate.RegisterValidation("notfuture", func(fl validator.FieldLevel) bool {
+		date, ok := fl.Field().Interface().(time.Time)
+		return ok && !date.After(time.Now())
+	})
+}
+
+// bindJSON reads the JSON body of a request into input and checks it against
+// the binding tags of input. A body that cannot be read gets a 400, one that
+// breaks the rules a 422 listing every field at fault; either way bindJSON
+// returns false.
+func bindJSON(c *gin.Context, input interface{}) bool {
+	return bound(c, c.ShouldBindJSON(input))
+}
+
+// bound reports the error of binding a request body as bindJSON does, and
+// tells whether there was none.
+func bound(c *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var invalidFields validator.ValidationErrors
+	if !errors.As(err, &invalidFields) {
+		badRequest(c, "invalid_input", "invalid input")
+		return false
+	}
+
+	fields := make([]FieldError, 0, len(invalidFields))
+	for _, invalidField := range invalidFields {
+		fields = append(fields, fieldError(invalidField))
+	}
+	c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+		Code:   "validation_failed",
+		Error:  fmt.Sprintf("%s: %s", fields[0].Field, fields[0].Error),
+		Fields: fields,
+	})
+	return false
+}
+
+func fieldError(err validator.FieldError) FieldError {
+	// the namespace starts with the name of the input type
+	_, field, _ := strings.Cut(err.Namespace(), ".")
+
+	var message string
+	switch err.Tag() {
+	case "required":
+		message = "is required"
+	case "notblank":
+		message = "must not be blank"
+	case "email":
+		message = "must be a valid email address"
+	case "notfuture":
+		message = "must not be in the future"
+	case "oneof":
+		message = "must be one of " + strings.ReplaceAll(err.Param(), " ", ", ")
+	case "min", "max":
+		bound := "at least"
+		if err.Tag() == "max" {
+			bound = "at most"
+		}
+		switch err.Kind() {
+		case reflect.String:
+			message = fmt.Sprintf("must be %s %s characters long", bound, err.Param())
+		case reflect.Slice:
+			message = fmt.Sprintf("must have %s %s items", bound, err.Param())
+		default:
+			message = fmt.Sprintf("must be %s %s", bound, err.Param())
+		}
+	default:
+		message = "is not valid"
+	}
+
+	return FieldError{Field: field, Code: err.Tag(), Error: message}
+}
@@ -0,0 +1,123 @@
+package isbn
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A span maps a range of the seven digits that follow a prefix to the length
+// of the element that starts there, as in the ranges published by the
+// International ISBN Agency. A length of 0 marks a range not yet in use.
+type span struct {
+	from, to int
+	length   int
+}
+
+// groups splits the registration group off an ISBN-13 after its prefix.
+var groups = map[string][]span{
+	"978": {
+		{0, 5999999, 1},
+		{6000000, 6499999, 3},
+		{6500000, 6599999, 2},
+		{6600000, 6999999, 0},
+		{7000000, 7999999, 1},
+		{8000000, 9499999, 2},
+		{9500000, 9899999, 3},
+		{9900000, 9989999, 4},
+		{9990000, 9999999, 5},
+	},
+	"979": {
+		{0, 999999, 0},
+		{1000000, 1299999, 2},
+		{1300000, 7999999, 0},
+		{8000000, 8999999, 1},
+		{9000000, 9999999, 0},
+	},
+}
+
+// registrants splits the registrant, that is the publisher, off the digits
+// after a registration group. Only the English-language groups, which hold
+// most of the catalogue, are listed, as published in the International ISBN
+// Agency's RangeMessage. ISBNs of other groups, and of ranges left out here,
+// cannot be hyphenated correctly and are formatted without hyphens.
+var registrants = map[string][]span{
+	"978-0": {
+		{0, 1999999, 2},
+		{2000000, 2279999, 3},
+		{2280000, 2289999, 4},
+		{2290000, 3689999, 3},
+		{3690000, 3699999, 4},
+		{3700000, 6389999, 3},
+		{6390000, 6397999, 4},
+		{6398000, 6399999, 7},
+		{6400000, 6449999, 3},
+		{6450000, 6459999, 7},
+		{6460000, 6479999, 3},
+		{6480000, 6489999, 7},
+		{6490000, 6549999, 3},
+		{6550000, 6559999, 4},
+		{6560000, 6999999, 3},
+		{7000000, 8499999, 4},
+		{8500000, 8999999, 5},
+		{9000000, 9499999, 6},
+		{9500000, 9999999, 7},
+	},
+	// the ranges of group 1 from 7320000 up are split into many small
+	// blocks that change with every edition of the RangeMessage
+	"978-1": {
+		{0, 999999, 2},
+		{1000000, 3999999, 3},
+		{4000000, 5499999, 4},
+		{5500000, 7319999, 5},
+	},
+}
+
+// Format hyphenates a valid ISBN between its prefix, registration group,
+// registrant, publication and check digit, e.g. 978-0-306-40615-7. An
+// ISBN-10 stays an ISBN-10. Hyphens are only placed where the registrant
+// ranges are known, which is the English-language groups 0 and 1; any other
+// ISBN is given as its bare digits, e.g. 9783161484100, rather than split
+// where its publisher's block does not end.
+func Format(s string) (string, error) {
+	isbn13, err := Normalize(s)
+	if err != nil {
+		return "", err
+	}
+
+	prefix, rest := isbn13[:3], isbn13[3:12]
+	groupLen := lookup(groups[prefix], rest)
+	if groupLen == 0 {
+		return "", ErrGroup
+	}
+	group, rest := rest[:groupLen], rest[groupLen:]
+
+	clean := Clean(s)
+	registrantLen := lookup(registrants[prefix+"-"+group], rest)
+	if registrantLen == 0 || registrantLen == len(rest) {
+		if len(clean) == 10 {
+			return clean, nil
+		}
+		return isbn13, nil
+	}
+
+	parts := []string{group, rest[:registrantLen], rest[registrantLen:]}
+	if len(clean) == 10 {
+		return strings.Join(append(parts, clean[9:]), "-"), nil
+	}
+	return strings.Join(append([]string{prefix}, append(parts, isbn13[12:])...), "-"), nil
+}
+
+// lookup finds the length of the element at the start of digits, or 0 if
+// the spans do not cover it.
+func lookup(spans []span, digits string) int {
+	for len(digits) < 7 {
+		digits += "0"
+	}
+	value, _ := strconv.Atoi(digits[:7])
+	for _, span := range spans {
+		if value >= span.from && value <= span.to {
+			return span.length
+		}
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+// Package isbn validates, normalizes, converts and hyphenates International
+// Standard Book Numbers.
+//
+// The catalogue stores ISBNs as ISBN-13 digits without hyphens; Normalize
+// turns any valid ISBN-10 or ISBN-13, hyphenated or not, into that form, and
+// Format hyphenates a stored ISBN for display.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCharacters = errors.New("ISBN may only contain digits, hyphens and spaces, and end in X if it is an ISBN-10")
+	ErrLength     = errors.New("ISBN must have 10 or 13 digits")
+	ErrPrefix     = errors.New("ISBN-13 must start with 978 or 979")
+	ErrCheckDigit = errors.New("ISBN check digit does not match")
+	ErrGroup      = errors.New("ISBN registration group is not assigned")
+)
+
+// Clean strips hyphens and spaces from s and upper-cases a final x. It does
+// not validate s.
+func Clean(s string) string {
+	s = strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s))
+	return strings.ToUpper(s)
+}
+
+// Validate checks that s is an ISBN-10 or ISBN-13 with a correct check
+// digit. Hyphens and spaces are ignored.
+func Validate(s string) error {
+	s = Clean(s)
+	for i, r := range s {
+		if (r < '0' || r > '9') && !(r == 'X' && i == 9 && len(s) == 10) {
+			return ErrCharacters
+		}
+	}
+
+	switch len(s) {
+	case 10:
+		if CheckDigit10(s[:9]) != s[9] {
+			return ErrCheckDigit
+		}
+	case 13:
+		if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+			return ErrPrefix
+		}
+		if CheckDigit13(s[:12]) != s[12] {
+			return ErrCheckDigit
+		}
+	default:
+		return ErrLength
+	}
+	return nil
+}
+
+// Valid reports whether s is a valid ISBN-10 or ISBN-13.
+func Valid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Normalize returns s as ISBN-13 digits without hyphens, converting an
+// ISBN-10 if need be.
+func Normalize(s string) (string, error) {
+	if err := Validate(s); err != nil {
+		return "", err
+	}
+	s = Clean(s)
+	if len(s) == 10 {
+		return to13(s), nil
+	}
+	return s, nil
+}
+
+// To13 converts an ISBN-10 to the equivalent ISBN-13, without hyphens.
+func To13(isbn10 string) (string, error) {
+	if err := Validate(isbn10); err != nil {
+		return "", err
+	}
+	isbn10 = Clean(isbn10)
+	if len(isbn10) != 10 {
+		return "", ErrLength
+	}
+	return to13(isbn10), nil
+}
+
+func to13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + string(CheckDigit13(body))
+}
+
+// To10 converts an ISBN-13 starting with 978 to the equivalent ISBN-10,
+// without hyphens. ISBN-13s starting with 979 have no ISBN-10.
+func To10(isbn13 string) (string, error) {
+	if err := Validate(isbn13); err != nil {
+		return "", err
+	}
+	isbn13 = Clean(isbn13)
+	if len(isbn13) != 13 {
+		return "", ErrLength
+	}
+	if !strings.HasPrefix(isbn13, "978") {
+		return "", ErrPrefix
+	}
+	body := isbn13[3:12]
+	return body + string(CheckDigit10(body)), nil
+}
+
+// CheckDigit10 computes the check digit of an ISBN-10 from its first nine
+// digits: '0' to '9', or 'X' for ten.
+func CheckDigit10(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += (10 - i) * int(digits[i]-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// CheckDigit13 computes the check digit of an ISBN-13 from its first twelve
+// digits.
+func CheckDigit13(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(digits[i]-'0')
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,131 @@
+package isbn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		isbn string
+		err  error
+	}{
+		{isbn: "0-306-40615-2"},
+		{isbn: "0306406152"},
+		{isbn: "0-8044-2957-x"},
+		{isbn: "978-0-306-40615-7"},
+		{isbn: "978 3 16 148410 0"},
+		{isbn: "979-10-90636-07-1"},
+		{isbn: "0-306-40615-3", err: ErrCheckDigit},
+		{isbn: "978-0-306-40615-8", err: ErrCheckDigit},
+		{isbn: "977-0-306-40615-7", err: ErrPrefix},
+		{isbn: "978-0-306-40615", err: ErrLength},
+		{isbn: "", err: ErrLength},
+		{isbn: "X-306-40615-2", err: ErrCharacters},
+		{isbn: "978-0-306-4061X-7", err: ErrCharacters},
+		{isbn: "978/0/306/40615/7", err: ErrCharacters},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.err, Validate(tt.isbn), tt.isbn)
+		assert.Equal(t, tt.err == nil, Valid(tt.isbn), tt.isbn)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{isbn: "0-306-40615-2", want: "9780306406157"},
+		{isbn: "080442957X", want: "9780804429573"},
+		{isbn: "978-3-16-148410-0", want: "9783161484100"},
+		{isbn: " 979-10-90636-07-1 ", want: "9791090636071"},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.isbn)
+		assert.NoError(t, err, tt.isbn)
+		assert.Equal(t, tt.want, got, tt.isbn)
+	}
+
+	_, err := Normalize("978-3-16-148410-1")
+	assert.ErrorIs(t, err, ErrCheckDigit)
+}
+
+func TestConvert(t *testing.T) {
+	isbn13, err := To13("0-19-852663-6")
+	assert.NoError(t, err)
+	assert.Equal(t, "9780198526636", isbn13)
+
+	isbn10, err := To10(isbn13)
+	assert.NoError(t, err)
+	assert.Equal(t, "0198526636", isbn10)
+
+	isbn10, err = To10("978-0-8044-2957-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "080442957X", isbn10)
+
+	_, err = To10("979-10-90636-07-1")
+	assert.ErrorIs(t, err, ErrPrefix)
+
+	_, err = To13("978-0-306-40615-7")
+	assert.ErrorIs(t, err, ErrLength)
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{isbn: "9780306406157", want: "978-0-306-40615-7"},
+		{isbn: "0306406152", want: "0-306-40615-2"},
+		{isbn: "9780198526636", want: "978-0-19-852663-6"},
+		{isbn: "080442957X", want: "0-8044-2957-X"},
+		{isbn: "9781402894626", want: "978-1-4028-9462-6"},
+		{isbn: "1402894627", want: "1-4028-9462-7"},
+		// group 0 switches registrant length inside its blocks
+		{isbn: "9780228123453", want: "978-0-2281-2345-3"},
+		{isbn: "9780229123452", want: "978-0-229-12345-2"},
+		{isbn: "9780369012340", want: "978-0-3690-1234-0"},
+		{isbn: "9780639800011", want: "978-0-6398000-1-1"},
+		{isbn: "9780645000122", want: "978-0-6450001-2-2"},
+		{isbn: "9780655012344", want: "978-0-6550-1234-4"},
+		{isbn: "9780656123452", want: "978-0-656-12345-2"},
+		// groups and ranges without known registrants are left unhyphenated
+		{isbn: "9781781234563", want: "9781781234563"},
+		{isbn: "9786021234563", want: "9786021234563"},
+		{isbn: "9789992112342", want: "9789992112342"},
+		{isbn: "9791090636071", want: "9791090636071"},
+		{isbn: "9798123456781", want: "9798123456781"},
+	}
+
+	for _, tt := range tests {
+		got, err := Format(tt.isbn)
+		assert.NoError(t, err, tt.isbn)
+		assert.Equal(t, tt.want, got, tt.isbn)
+	}
+
+	// German, group 3, whose publisher blocks are not listed
+	for isbn, want := range map[string]string{
+		"978-3-16-148410-0": "9783161484100",
+		"3-16-148410-X":     "316148410X",
+	} {
+		got, err := Format(isbn)
+		assert.NoError(t, err, isbn)
+		assert.Equal(t, want, got, isbn)
+	}
+
+	_, err := Format("9790123456785")
+	assert.ErrorIs(t, err, ErrGroup)
+	_, err = Format("invalid")
+	assert.ErrorIs(t, err, ErrCharacters)
+}
+
+func TestCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('2'), CheckDigit10("030640615"))
+	assert.Equal(t, byte('X'), CheckDigit10("080442957"))
+	assert.Equal(t, byte('7'), CheckDigit13("978030640615"))
+	assert.Equal(t, byte('0'), CheckDigit13("978316148410"))
+}
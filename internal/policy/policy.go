@@ -0,0 +1,115 @@
+// Package policy decides what the users of each role, and the holders of
+// each API key, may do.
+//
+// Reading the catalogue needs no permission at all. Everything else is one
+// of the permissions below, which the roles are granted in grants. API keys
+// are granted the permissions named by their scopes instead.
+package policy
+
+import "library/models"
+
+// Permission is something a role or API key may be allowed to do. It is
+// also the name of the API key scope that grants it.
+type Permission string
+
+const (
+	// CatalogRead is reading the catalogue. The catalogue is open to anyone,
+	// so it only lets an API key be scoped to nothing more.
+	CatalogRead Permission = "catalog:read"
+	// CatalogWrite is creating, updating and deleting books, authors,
+	// copies, subjects and tags.
+	CatalogWrite Permission = "catalog:write"
+	// LoansOwn is renting, renewing, returning and holding books for
+	// oneself, and seeing one's own account, loans, holds and fines.
+	LoansOwn Permission = "loans:own"
+	// LoansRead is seeing the loans, holds and fines of any user and book.
+	LoansRead Permission = "loans:read"
+	// LoansWrite is renting, renewing, returning and holding books for any
+	// user.
+	LoansWrite Permission = "loans:write"
+	// FinesManage is recording payments and waiving fines.
+	FinesManage Permission = "fines:manage"
+	// UsersRead is seeing the details of any user.
+	UsersRead Permission = "users:read"
+	// UsersManage is creating, updating and deleting users and giving them
+	// roles.
+	UsersManage Permission = "users:manage"
+	// PoliciesManage is creating, updating and deleting loan policies.
+	PoliciesManage Permission = "policies:manage"
+	// APIKeysManage is creating, listing and revoking API keys.
+	APIKeysManage Permission = "apikeys:manage"
+	// ArchiveManage is listing and restoring archived books, authors and
+	// users. Archiving them is deleting, which CatalogWrite and UsersManage
+	// allow.
+	ArchiveManage Permission = "archive:manage"
+)
+
+// permissions lists every permission, in the order of the constants.
+var permissions = []Permission{
+	CatalogRead, CatalogWrite, LoansOwn, LoansRead, LoansWrite, FinesManage,
+	UsersRead, UsersManage, PoliciesManage, APIKeysManage, ArchiveManage,
+}
+
+// grants is the permission matrix. Every role has an entry.
+var grants = map[string][]Permission{
+	models.RolePatron: {CatalogRead, LoansOwn},
+	models.RoleLibrarian: {
+		CatalogRead, LoansOwn, LoansRead, LoansWrite, CatalogWrite,
+		FinesManage, UsersRead,
+	},
+	models.RoleAdmin: {
+		CatalogRead, LoansOwn, LoansRead, LoansWrite, CatalogWrite,
+		FinesManage, UsersRead, UsersManage, PoliciesManage, APIKeysManage,
+		ArchiveManage,
+	},
+}
+
+// Roles lists the roles a user can have, from least to most privileged.
+func Roles() []string {
+	return []string{models.RolePatron, models.RoleLibrarian, models.RoleAdmin}
+}
+
+// ValidRole tells whether role is one of Roles.
+func ValidRole(role string) bool {
+	_, ok := grants[role]
+	return ok
+}
+
+// Permissions lists every permission, which are also the API key scopes.
+func Permissions() []Permission {
+	return append([]Permission(nil), permissions...)
+}
+
+// ValidPermission tells whether permission is one of Permissions.
+func ValidPermission(permission Permission) bool {
+	return contains(permissions, permission)
+}
+
+// Can tells whether users with role have permission. Unknown roles have no
+// permissions.
+func Can(role string, permission Permission) bool {
+	return contains(grants[role], permission)
+}
+
+// Allows tells whether the caller with identity has permission: through the
+// scopes of their API key if they used one, otherwise through their role.
+func Allows(identity models.Identity, permission Permission) bool {
+	if identity.APIKeyID != 0 {
+		for _, scope := range identity.Scopes {
+			if Permission(scope) == permission {
+				return true
+			}
+		}
+		return false
+	}
+	return Can(identity.Role, permission)
+}
+
+func contains(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
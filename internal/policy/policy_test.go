@@ -0,0 +1,69 @@
+package policy_test
+
+import (
+	"library/internal/policy"
+	"library/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCan(t *testing.T) {
+	// the whole matrix: which of patron, librarian and admin may do what
+	matrix := map[policy.Permission][3]bool{
+		policy.CatalogRead:    {true, true, true},
+		policy.LoansOwn:       {true, true, true},
+		policy.LoansRead:      {false, true, true},
+		policy.LoansWrite:     {false, true, true},
+		policy.CatalogWrite:   {false, true, true},
+		policy.FinesManage:    {false, true, true},
+		policy.UsersRead:      {false, true, true},
+		policy.UsersManage:    {false, false, true},
+		policy.PoliciesManage: {false, false, true},
+		policy.APIKeysManage:  {false, false, true},
+		policy.ArchiveManage:  {false, false, true},
+	}
+	assert.Len(t, matrix, len(policy.Permissions()))
+
+	for permission, allowed := range matrix {
+		for i, role := range policy.Roles() {
+			assert.Equal(t, allowed[i], policy.Can(role, permission), "%s %s", role, permission)
+		}
+	}
+}
+
+func TestCan_UnknownRole(t *testing.T) {
+	assert.False(t, policy.Can("", policy.LoansOwn))
+	assert.False(t, policy.Can("root", policy.UsersManage))
+}
+
+func TestValidRole(t *testing.T) {
+	for _, role := range policy.Roles() {
+		assert.True(t, policy.ValidRole(role), role)
+	}
+	assert.False(t, policy.ValidRole(""))
+	assert.False(t, policy.ValidRole(models.RoleAuthor))
+}
+
+func TestValidPermission(t *testing.T) {
+	for _, permission := range policy.Permissions() {
+		assert.True(t, policy.ValidPermission(permission), permission)
+	}
+	assert.False(t, policy.ValidPermission(""))
+	assert.False(t, policy.ValidPermission("loans:any"))
+}
+
+func TestAllows(t *testing.T) {
+	librarian := models.Identity{UserID: 1, Role: models.RoleLibrarian}
+	assert.True(t, policy.Allows(librarian, policy.LoansWrite))
+	assert.False(t, policy.Allows(librarian, policy.UsersManage))
+
+	// API keys have the permissions of their scopes, and no role
+	kiosk := models.Identity{APIKeyID: 1, Scopes: []string{"catalog:read", "loans:write"}}
+	assert.True(t, policy.Allows(kiosk, policy.LoansWrite))
+	assert.False(t, policy.Allows(kiosk, policy.LoansRead))
+	assert.False(t, policy.Allows(kiosk, policy.LoansOwn))
+
+	scopedAsAdmin := models.Identity{APIKeyID: 2, Role: models.RoleAdmin}
+	assert.False(t, policy.Allows(scopedAsAdmin, policy.UsersManage))
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"library/models"
+	"slices"
+	"time"
+)
+
+type APIKeyMemory struct {
+	store *memoryStore
+}
+
+func (r *APIKeyMemory) Create(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	err := r.store.do(ctx, func(d *memoryData) error {
+		if hasRow(d.apiKeys, func(other models.APIKey) bool { return other.KeyHash == key.KeyHash }) {
+			return ErrDuplicate
+		}
+		if key.CreatedByID != nil {
+			if _, ok := d.users[*key.CreatedByID]; !ok {
+				return ErrForeignKey
+			}
+		}
+
+		key.ID = d.nextID("api_keys")
+		if key.CreatedAt.IsZero() {
+			key.CreatedAt = time.Now()
+		}
+		key.Scopes = slices.Clone(key.Scopes)
+		d.apiKeys[key.ID] = key
+		return nil
+	})
+	return key, err
+}
+
+func (r *APIKeyMemory) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.store.do(ctx, func(d *memoryData) error {
+		keys = sortedRows(d.apiKeys, nil)
+		return nil
+	})
+	return keys, err
+}
+
+func (r *APIKeyMemory) GetByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	var key models.APIKey
+	err := r.store.do(ctx, func(d *memoryData) error {
+		keys := sortedRows(d.apiKeys, func(key models.APIKey) bool { return key.KeyHash == hash })
+		if len(keys) == 0 {
+			return ErrNotFound
+		}
+		key = keys[0]
+		return nil
+	})
+	return key, err
+}
+
+func (r *APIKeyMemory) Revoke(ctx context.Context, id int, at time.Time) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		key, ok := d.apiKeys[id]
+		if !ok {
+			return ErrNotFound
+		}
+		if key.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+		key.RevokedAt = &at
+		d.apiKeys[id] = key
+		return nil
+	})
+}
+
+// Touch records that the key was used at, unless its last use was recorded
+// less than touchInterval before.
+func (r *APIKeyMemory) Touch(ctx context.Context, id int, at time.Time) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		key, ok := d.apiKeys[id]
+		if !ok || key.LastUsedAt != nil && !key.LastUsedAt.Before(at.Add(-touchInterval)) {
+			return nil
+		}
+		key.LastUsedAt = &at
+		d.apiKeys[id] = key
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"library/models"
+	"time"
+)
+
+// touchInterval is how stale the last use of an API key may get before
+// Touch records a new one, so that busy keys do not write on every request.
+const touchInterval = time.Minute
+
+type APIKeyPostgres struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyPostgres(db *gorm.DB) *APIKeyPostgres {
+	return &APIKeyPostgres{db: db}
+}
+
+func (r *APIKeyPostgres) Create(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	err := conn(ctx, r.db).Create(&key).Error
+	return key, err
+}
+
+func (r *APIKeyPostgres) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := conn(ctx, r.db).Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *APIKeyPostgres) GetByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	var key models.APIKey
+	err := conn(ctx, r.db).Where("key_hash = ?", hash).First(&key).Error
+	return key, err
+}
+
+func (r *APIKeyPostgres) Revoke(ctx context.Context, id int, at time.Time) error {
+	db := conn(ctx, r.db)
+	res := db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if err := db.First(&models.APIKey{}, id).Error; err != nil {
+			return err
+		}
+		return ErrAPIKeyRevoked
+	}
+	return nil
+}
+
+// Touch records that the key was used at, unless its last use was recorded
+// less than touchInterval before.
+func (r *APIKeyPostgres) Touch(ctx context.Context, id int, at time.Time) error {
+	return conn(ctx, r.db).Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-touchInterval)).
+		Update("last_used_at", at).Error
+}
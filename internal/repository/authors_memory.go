@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"library/models"
+	"slices"
+	"time"
+)
+
+type AuthorMemory struct {
+	store *memoryStore
+}
+
+func (r *AuthorMemory) GetAll(ctx context.Context, query models.AuthorQuery) ([]models.Author, int64, error) {
+	var (
+		authors []models.Author
+		total   int64
+	)
+	err := r.store.do(ctx, func(d *memoryData) error {
+		matches := sortedRows(d.authors, func(author models.Author) bool {
+			return (author.DeletedAt != nil) == query.Deleted &&
+				(query.Name == "" || containsFold(author.Name, query.Name))
+		})
+		sortBy(matches, query.Sort, map[string]func(a, b models.Author) int{
+			"name": func(a, b models.Author) int { return cmp.Compare(a.Name, b.Name) },
+		})
+		total = int64(len(matches))
+		authors = paged(matches, query.Page, query.PageSize)
+		return nil
+	})
+	return authors, total, err
+}
+
+func (r *AuthorMemory) Create(ctx context.Context, author models.Author) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		author.ID = d.nextID("authors")
+		d.authors[author.ID] = author
+		return nil
+	})
+}
+
+func (r *AuthorMemory) GetByID(ctx context.Context, id int) (models.Author, error) {
+	var author models.Author
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if author, ok = d.authors[id]; !ok || author.DeletedAt != nil {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return author, err
+}
+
+// GetBooks returns the credits of an author, one per book and role, with
+// the book. Archived books are left out. Books keep their contributors in
+// credit order, so walking them in ID order lists the credits as Postgres
+// does.
+func (r *AuthorMemory) GetBooks(ctx context.Context, id int) ([]models.BookContributor, error) {
+	var credits []models.BookContributor
+	err := r.store.do(ctx, func(d *memoryData) error {
+		for _, book := range sortedRows(d.books, func(book models.Book) bool { return book.DeletedAt == nil }) {
+			for _, contributor := range book.Contributors {
+				if contributor.AuthorID == id {
+					contributor.Book = models.Book{ID: book.ID, Title: book.Title, PublishedAt: book.PublishedAt, ISBN: book.ISBN, DeletedAt: book.DeletedAt}
+					credits = append(credits, contributor)
+				}
+			}
+		}
+		return nil
+	})
+	return credits, err
+}
+
+// Delete archives an author together with the books they contributed to,
+// unless one of those books is out on loan or on hold.
+func (r *AuthorMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		author, ok := d.authors[id]
+		if !ok || author.DeletedAt != nil {
+			return ErrNotFound
+		}
+
+		books := sortedRows(d.books, func(book models.Book) bool {
+			return book.DeletedAt == nil && d.credits(book, id)
+		})
+		err := d.checkIdle(func(_, bookID int) bool {
+			return slices.ContainsFunc(books, func(book models.Book) bool { return book.ID == bookID })
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, book := range books {
+			book.DeletedAt = &now
+			d.books[book.ID] = book
+		}
+		author.DeletedAt = &now
+		d.authors[id] = author
+		return nil
+	})
+}
+
+// Restore brings back an archived author and the books archived with them,
+// which are the ones archived at the same time. A book that another
+// archived author contributed to stays archived.
+func (r *AuthorMemory) Restore(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		author, ok := d.authors[id]
+		if !ok || author.DeletedAt == nil {
+			return ErrNotFound
+		}
+
+		for _, book := range d.books {
+			if book.DeletedAt == nil || !book.DeletedAt.Equal(*author.DeletedAt) || !d.credits(book, id) ||
+				slices.ContainsFunc(book.Contributors, func(contributor models.BookContributor) bool {
+					return contributor.AuthorID != id && d.authors[contributor.AuthorID].DeletedAt != nil
+				}) {
+				continue
+			}
+			book.DeletedAt = nil
+			d.books[book.ID] = book
+		}
+		author.DeletedAt = nil
+		d.authors[id] = author
+		return nil
+	})
+}
+
+// Update saves a live author.
+func (r *AuthorMemory) Update(ctx context.Context, author models.Author) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if stored, ok := d.authors[author.ID]; !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		author.DeletedAt = nil
+		d.authors[author.ID] = author
+		return nil
+	})
+}
+
+// credits tells whether the author is credited on the book.
+func (d *memoryData) credits(book models.Book, authorID int) bool {
+	return slices.ContainsFunc(book.Contributors, func(contributor models.BookContributor) bool {
+		return contributor.AuthorID == authorID
+	})
+}
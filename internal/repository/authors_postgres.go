@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"gorm.io/gorm"
 	"library/models"
+	"time"
 )
 
 type AuthorPostgres struct {
@@ -13,26 +15,90 @@ func NewAuthorPostgres(db *gorm.DB) *AuthorPostgres {
 	return &AuthorPostgres{db: db}
 }
 
-func (r *AuthorPostgres) GetAll() ([]models.Author, error) {
+func (r *AuthorPostgres) GetAll(ctx context.Context, query models.AuthorQuery) ([]models.Author, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where(archived("authors", query.Deleted))
+		if query.Name != "" {
+			db = db.Where(containsMatch(db, "authors.name"), containsPattern(query.Name))
+		}
+		return db
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).Model(&models.Author{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var authors []models.Author
-	err := r.db.Find(&authors).Error
-	return authors, err
+	err := conn(ctx, r.db).Scopes(filter, paginate(query.Page, query.PageSize)).
+		Clauses(sortOrder("authors", query.Sort)).
+		Find(&authors).Error
+	return authors, total, err
 }
 
-func (r *AuthorPostgres) Create(author models.Author) error {
-	return r.db.Create(&author).Error
+func (r *AuthorPostgres) Create(ctx context.Context, author models.Author) error {
+	return conn(ctx, r.db).Create(&author).Error
 }
 
-func (r *AuthorPostgres) GetByID(id int) (models.Author, error) {
+func (r *AuthorPostgres) GetByID(ctx context.Context, id int) (models.Author, error) {
 	var author models.Author
-	err := r.db.First(&author, id).Error
+	err := conn(ctx, r.db).Where("deleted_at IS NULL").First(&author, id).Error
 	return author, err
 }
 
-func (r *AuthorPostgres) Delete(id int) error {
-	return r.db.Delete(&models.Author{}, id).Error
+// GetBooks returns the credits of an author, one per book and role, with
+// the book. Archived books are left out.
+func (r *AuthorPostgres) GetBooks(ctx context.Context, id int) ([]models.BookContributor, error) {
+	var credits []models.BookContributor
+	err := conn(ctx, r.db).Preload("Book").
+		Where("author_id = ? AND book_id IN (SELECT id FROM books WHERE deleted_at IS NULL)", id).
+		Order("book_id, position").
+		Find(&credits).Error
+	return credits, err
+}
+
+// Delete archives an author together with the books they contributed to,
+// unless one of those books is out on loan or on hold.
+func (r *AuthorPostgres) Delete(ctx context.Context, id int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := archiveByID(tx, &models.Author{}, id, &now); err != nil {
+			return err
+		}
+
+		var bookIDs []int
+		err := tx.Model(&models.Book{}).
+			Where("deleted_at IS NULL AND id IN (SELECT book_id FROM book_contributors WHERE author_id = ?)", id).
+			Pluck("id", &bookIDs).Error
+		if err != nil || len(bookIDs) == 0 {
+			return err
+		}
+		if err := checkIdle(tx, "book_id", bookIDs); err != nil {
+			return err
+		}
+		return tx.Model(&models.Book{}).Where("id IN ?", bookIDs).Update("deleted_at", now).Error
+	})
+}
+
+// Restore brings back an archived author and the books archived with them,
+// which are the ones archived at the same time. A book that another
+// archived author contributed to stays archived.
+func (r *AuthorPostgres) Restore(ctx context.Context, id int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Book{}).
+			Where("deleted_at = (SELECT deleted_at FROM authors WHERE id = ?)", id).
+			Where("id IN (SELECT book_id FROM book_contributors WHERE author_id = ?)", id).
+			Where(`NOT EXISTS (SELECT 1 FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id
+				WHERE book_contributors.book_id = books.id AND authors.id <> ? AND authors.deleted_at IS NOT NULL)`, id).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return archiveByID(tx, &models.Author{}, id, nil)
+	})
 }
 
-func (r *AuthorPostgres) Update(author models.Author) error {
-	return r.db.Save(&author).Error
+// Update saves a live author.
+func (r *AuthorPostgres) Update(ctx context.Context, author models.Author) error {
+	return updateByID(conn(ctx, r.db).Where("deleted_at IS NULL"), &author, "deleted_at")
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"library/models"
 	"testing"
@@ -19,11 +20,14 @@ func TestAuthorPostgres_GetAll(t *testing.T) {
 		{ID: 2, Name: "Author 2"},
 	}
 
-	mockDB.EXPECT().GetAll().Return(expectedAuthors, nil)
+	query := models.AuthorQuery{Page: 1, PageSize: 20}
 
-	authors, err := mockDB.GetAll()
+	mockDB.EXPECT().GetAll(gomock.Any(), query).Return(expectedAuthors, int64(2), nil)
+
+	authors, total, err := mockDB.GetAll(context.Background(), query)
 	assert.Nil(t, err)
 	assert.NotNil(t, authors)
+	assert.Equal(t, int64(2), total)
 
 }
 
@@ -35,9 +39,9 @@ func TestAuthorPostgres_Create(t *testing.T) {
 
 	newAuthor := models.Author{Name: "New Author"}
 
-	mockDB.EXPECT().Create(newAuthor).Return(nil)
+	mockDB.EXPECT().Create(gomock.Any(), newAuthor).Return(nil)
 
-	err := mockDB.Create(newAuthor)
+	err := mockDB.Create(context.Background(), newAuthor)
 	assert.Nil(t, err)
 
 }
@@ -50,9 +54,9 @@ func TestAuthorPostgres_GetByID(t *testing.T) {
 
 	expectedAuthor := models.Author{ID: 1, Name: "Author 1"}
 
-	mockDB.EXPECT().GetByID(1).Return(expectedAuthor, nil)
+	mockDB.EXPECT().GetByID(gomock.Any(), 1).Return(expectedAuthor, nil)
 
-	author, err := mockDB.GetByID(1)
+	author, err := mockDB.GetByID(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedAuthor, author)
 
@@ -66,9 +70,9 @@ func TestAuthorPostgres_Delete(t *testing.T) {
 
 	authorID := 1
 
-	mockDB.EXPECT().Delete(authorID).Return(nil)
+	mockDB.EXPECT().Delete(gomock.Any(), authorID).Return(nil)
 
-	err := mockDB.Delete(authorID)
+	err := mockDB.Delete(context.Background(), authorID)
 	assert.Nil(t, err)
 
 }
@@ -81,9 +85,9 @@ func TestAuthorPostgres_Update(t *testing.T) {
 
 	updatedAuthor := models.Author{ID: 1, Name: "Updated Author"}
 
-	mockDB.EXPECT().Update(updatedAuthor).Return(nil)
+	mockDB.EXPECT().Update(gomock.Any(), updatedAuthor).Return(nil)
 
-	err := mockDB.Update(updatedAuthor)
+	err := mockDB.Update(context.Background(), updatedAuthor)
 	assert.NoError(t, err)
 
 }
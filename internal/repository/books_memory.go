@@ -0,0 +1,509 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"library/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+type BookMemory struct {
+	store *memoryStore
+}
+
+func (r *BookMemory) GetAll(ctx context.Context, query models.BookQuery) ([]models.Book, int64, error) {
+	var (
+		books []models.Book
+		total int64
+	)
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var subjects map[int]bool
+		if query.SubjectID != 0 {
+			subjects = d.subjectTree(query.SubjectID)
+		}
+
+		matches := sortedRows(d.books, func(book models.Book) bool {
+			switch {
+			case (book.DeletedAt != nil) != query.Deleted:
+				return false
+			case query.Title != "" && !containsFold(book.Title, query.Title):
+				return false
+			case query.AuthorID != 0 && !slices.ContainsFunc(book.Contributors, func(contributor models.BookContributor) bool {
+				return contributor.AuthorID == query.AuthorID
+			}):
+				return false
+			case query.SubjectID != 0 && !slices.ContainsFunc(book.Subjects, func(subject models.Subject) bool {
+				return subjects[subject.ID]
+			}):
+				return false
+			case query.Tag != "" && !slices.ContainsFunc(book.Tags, func(tag models.Tag) bool {
+				return d.tags[tag.ID].Name == query.Tag
+			}):
+				return false
+			case query.PublishedFrom != nil && book.PublishedAt.Before(*query.PublishedFrom):
+				return false
+			case query.PublishedTo != nil && book.PublishedAt.After(*query.PublishedTo):
+				return false
+			case query.Available != nil && (d.availableCopies(book.ID) > 0) != *query.Available:
+				return false
+			}
+			return true
+		})
+		sortBy(matches, query.Sort, map[string]func(a, b models.Book) int{
+			"title":        func(a, b models.Book) int { return cmp.Compare(a.Title, b.Title) },
+			"published_at": func(a, b models.Book) int { return a.PublishedAt.Compare(b.PublishedAt) },
+		})
+
+		total = int64(len(matches))
+		for _, book := range paged(matches, query.Page, query.PageSize) {
+			books = append(books, d.book(book))
+		}
+		return nil
+	})
+	return books, total, err
+}
+
+// Search matches books as rankHit does.
+func (r *BookMemory) Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, int64, error) {
+	text := strings.TrimSpace(query.Text)
+	words := queryWords(text)
+	isbn := exactISBN(text)
+
+	var (
+		hits  []models.SearchHit
+		total int64
+	)
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var matches []models.SearchHit
+		for _, book := range sortedRows(d.books, func(book models.Book) bool { return book.DeletedAt == nil }) {
+			hit := models.SearchHit{
+				BookID:       book.ID,
+				Title:        book.Title,
+				ISBN:         book.ISBN,
+				Contributors: d.creditNames(book),
+			}
+			if rankHit(&hit, words, isbn) {
+				matches = append(matches, hit)
+			}
+		}
+		sortHits(matches)
+
+		total = int64(len(matches))
+		hits = paged(matches, query.Page, query.PageSize)
+		return nil
+	})
+	return hits, total, err
+}
+
+// Create adds a book together with its contributors, subjects and tags.
+// Tags are created as needed.
+func (r *BookMemory) Create(ctx context.Context, book models.Book) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if err := d.checkBook(book); err != nil {
+			return err
+		}
+		book.ID = d.nextID("books")
+		d.saveBook(book)
+		return nil
+	})
+}
+
+func (r *BookMemory) GetByID(ctx context.Context, id int) (models.Book, error) {
+	var book models.Book
+	err := r.store.do(ctx, func(d *memoryData) error {
+		stored, ok := d.books[id]
+		if !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		book = d.book(stored)
+		book.Copies = sortedRows(d.copies, func(bookCopy models.BookCopy) bool {
+			return bookCopy.BookID == id
+		})
+		return nil
+	})
+	return book, err
+}
+
+// Delete archives a book, unless it is out on loan or on hold. Its copies,
+// and the loans and holds of the past, are kept.
+func (r *BookMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		book, ok := d.books[id]
+		if !ok || book.DeletedAt != nil {
+			return ErrNotFound
+		}
+		if err := d.checkIdle(func(_, bookID int) bool { return bookID == id }); err != nil {
+			return err
+		}
+		now := time.Now()
+		book.DeletedAt = &now
+		d.books[id] = book
+		return nil
+	})
+}
+
+// Restore brings back an archived book, unless an author of it is still
+// archived.
+func (r *BookMemory) Restore(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		book, ok := d.books[id]
+		if !ok || book.DeletedAt == nil {
+			return ErrNotFound
+		}
+		if slices.ContainsFunc(book.Contributors, func(contributor models.BookContributor) bool {
+			return d.authors[contributor.AuthorID].DeletedAt != nil
+		}) {
+			return ErrAuthorArchived
+		}
+		book.DeletedAt = nil
+		d.books[id] = book
+		return nil
+	})
+}
+
+// Update saves a live book and replaces its contributors, subjects and tags
+// with the ones given.
+func (r *BookMemory) Update(ctx context.Context, book models.Book) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if stored, ok := d.books[book.ID]; !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		book.DeletedAt = nil
+		if err := d.checkBook(book); err != nil {
+			return err
+		}
+		d.saveBook(book)
+		return nil
+	})
+}
+
+// checkBook reports what the constraints of the tables would make of
+// saving book: a taken ISBN, a credit of an unknown author or the same
+// credit twice, an unknown subject, or the same subject or tag twice. An
+// archived author counts as unknown.
+func (d *memoryData) checkBook(book models.Book) error {
+	if hasRow(d.books, func(other models.Book) bool { return other.ID != book.ID && other.ISBN == book.ISBN }) {
+		return ErrDuplicate
+	}
+
+	type credit struct {
+		authorID int
+		role     string
+	}
+	credits := make(map[credit]bool)
+	for _, contributor := range book.Contributors {
+		if author, ok := d.authors[contributor.AuthorID]; !ok || author.DeletedAt != nil {
+			return ErrForeignKey
+		}
+		key := credit{contributor.AuthorID, contributor.Role}
+		if credits[key] {
+			return ErrDuplicate
+		}
+		credits[key] = true
+	}
+
+	subjects := make(map[int]bool)
+	for _, subject := range book.Subjects {
+		if _, ok := d.subjects[subject.ID]; !ok {
+			return ErrUnknownSubject
+		}
+		if subjects[subject.ID] {
+			return ErrDuplicate
+		}
+		subjects[subject.ID] = true
+	}
+
+	tags := make(map[string]bool)
+	for _, tag := range book.Tags {
+		if tags[tag.Name] {
+			return ErrDuplicate
+		}
+		tags[tag.Name] = true
+	}
+	return nil
+}
+
+// saveBook stores a book checked by checkBook, in the form memoryData keeps
+// books in, creating the tags it does not find by name.
+func (d *memoryData) saveBook(book models.Book) {
+	contributors := make([]models.BookContributor, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		contributors[i] = models.BookContributor{
+			BookID:   book.ID,
+			AuthorID: contributor.AuthorID,
+			Role:     contributor.Role,
+			Position: contributor.Position,
+		}
+	}
+	slices.SortStableFunc(contributors, func(a, b models.BookContributor) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	subjects := make([]models.Subject, len(book.Subjects))
+	for i, subject := range book.Subjects {
+		subjects[i] = models.Subject{ID: subject.ID}
+	}
+
+	tags := make([]models.Tag, len(book.Tags))
+	for i, tag := range book.Tags {
+		tags[i] = models.Tag{ID: d.tagID(tag.Name)}
+	}
+
+	d.books[book.ID] = models.Book{
+		ID:           book.ID,
+		Title:        book.Title,
+		PublishedAt:  book.PublishedAt,
+		ISBN:         book.ISBN,
+		Contributors: contributors,
+		Subjects:     subjects,
+		Tags:         tags,
+	}
+}
+
+// book returns a stored book as GetAll lists it: with its contributors and
+// their authors, its subjects, its tags and the number of copies available.
+func (d *memoryData) book(stored models.Book) models.Book {
+	book := d.creditedBook(stored)
+	book.Subjects = make([]models.Subject, len(stored.Subjects))
+	for i, subject := range stored.Subjects {
+		book.Subjects[i] = d.subjects[subject.ID]
+	}
+	book.Tags = make([]models.Tag, len(stored.Tags))
+	for i, tag := range stored.Tags {
+		book.Tags[i] = d.tags[tag.ID]
+	}
+	book.AvailableCopies = d.availableCopies(stored.ID)
+	return book
+}
+
+// creditedBook returns a stored book with only its contributors and their
+// authors, as loans carry it.
+func (d *memoryData) creditedBook(stored models.Book) models.Book {
+	book := models.Book{
+		ID:           stored.ID,
+		Title:        stored.Title,
+		PublishedAt:  stored.PublishedAt,
+		ISBN:         stored.ISBN,
+		Contributors: make([]models.BookContributor, len(stored.Contributors)),
+		DeletedAt:    stored.DeletedAt,
+	}
+	for i, contributor := range stored.Contributors {
+		contributor.Author = d.authors[contributor.AuthorID]
+		book.Contributors[i] = contributor
+	}
+	return book
+}
+
+// creditNames joins the names of the contributors of a book in credit
+// order, as searchCredits does.
+func (d *memoryData) creditNames(book models.Book) string {
+	names := make([]string, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		names[i] = d.authors[contributor.AuthorID].Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// RentBook lends a copy of a book to the user. A specific copy can be asked
+// for with copyID; otherwise the copy set aside by the user's ready hold or
+// the first free copy is used.
+func (r *BookMemory) RentBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if copyID != 0 {
+			bookCopy, ok := d.copies[copyID]
+			if !ok {
+				return ErrNotFound
+			}
+			if bookID != 0 && bookCopy.BookID != bookID {
+				return ErrCopyMismatch
+			}
+			bookID = bookCopy.BookID
+		}
+
+		holds := sortedRows(d.holds, func(hold models.Hold) bool {
+			return hold.UserID == userID && hold.BookID == bookID && hold.Status == models.HoldReady
+		})
+		if len(holds) > 0 && copyID == 0 && holds[0].CopyID != nil {
+			copyID = *holds[0].CopyID
+		}
+
+		free := d.freeCopies(bookID, userID)
+		i := slices.IndexFunc(free, func(bookCopy models.BookCopy) bool {
+			return copyID == 0 || bookCopy.ID == copyID
+		})
+		if i < 0 {
+			if copyID != 0 {
+				return ErrCopyUnavailable
+			}
+			return ErrNoCopyAvailable
+		}
+		if _, ok := d.users[userID]; !ok {
+			return ErrForeignKey
+		}
+
+		now := time.Now()
+		rentedBook := models.RentedBook{
+			ID:       d.nextID("rented_books"),
+			UserID:   userID,
+			BookID:   bookID,
+			CopyID:   free[i].ID,
+			RentedAt: now,
+			DueAt:    now.Add(loanPeriod),
+		}
+		d.loans[rentedBook.ID] = rentedBook
+
+		if len(holds) > 0 {
+			hold := holds[0]
+			hold.Status = models.HoldFulfilled
+			hold.ClosedAt = &now
+			d.holds[hold.ID] = hold
+		}
+		return nil
+	})
+}
+
+// ReturnBook closes the user's oldest matching open loan.
+func (r *BookMemory) ReturnBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error) {
+	var rentedBook models.RentedBook
+	err := r.store.do(ctx, func(d *memoryData) error {
+		loans := d.openLoans(userID, bookID, copyID)
+		if len(loans) == 0 {
+			return ErrNotRented
+		}
+
+		now := time.Now()
+		rentedBook = loans[0]
+		rentedBook.ReturnedAt = &now
+		d.loans[rentedBook.ID] = rentedBook
+		return nil
+	})
+	return rentedBook, err
+}
+
+// RenewBook extends the user's oldest matching open loan.
+func (r *BookMemory) RenewBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration, maxRenewals int) (models.RentedBook, error) {
+	var rentedBook models.RentedBook
+	err := r.store.do(ctx, func(d *memoryData) error {
+		loans := d.openLoans(userID, bookID, copyID)
+		if len(loans) == 0 {
+			return ErrNotRented
+		}
+		rentedBook = loans[0]
+
+		if rentedBook.RenewalCount >= maxRenewals {
+			return fmt.Errorf("%w: %d renewals allowed", ErrRenewalLimit, maxRenewals)
+		}
+		if rentedBook.DueAt.Before(time.Now()) {
+			return fmt.Errorf("%w: it was due %s", ErrLoanOverdue, rentedBook.DueAt.Format(time.DateOnly))
+		}
+		rentedBook.DueAt = rentedBook.DueAt.Add(loanPeriod)
+		rentedBook.RenewalCount++
+		d.loans[rentedBook.ID] = rentedBook
+		return nil
+	})
+	return rentedBook, err
+}
+
+func (r *BookMemory) GetOverdue(ctx context.Context) ([]models.RentedBook, error) {
+	var rentedBooks []models.RentedBook
+	err := r.store.do(ctx, func(d *memoryData) error {
+		now := time.Now()
+		loans := sortedRows(d.loans, func(loan models.RentedBook) bool {
+			return loan.ReturnedAt == nil && loan.DueAt.Before(now)
+		})
+		slices.SortStableFunc(loans, func(a, b models.RentedBook) int {
+			return a.DueAt.Compare(b.DueAt)
+		})
+		for _, loan := range loans {
+			rentedBooks = append(rentedBooks, d.loan(loan))
+		}
+		return nil
+	})
+	return rentedBooks, err
+}
+
+func (r *BookMemory) CountOpenLoans(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.store.do(ctx, func(d *memoryData) error {
+		count = len(sortedRows(d.loans, func(loan models.RentedBook) bool {
+			return loan.UserID == userID && loan.ReturnedAt == nil
+		}))
+		return nil
+	})
+	return count, err
+}
+
+func (r *BookMemory) GetLoans(ctx context.Context, query models.LoanQuery) ([]models.RentedBook, int64, error) {
+	var (
+		rentedBooks []models.RentedBook
+		total       int64
+	)
+	err := r.store.do(ctx, func(d *memoryData) error {
+		loans := sortedRows(d.loans, func(loan models.RentedBook) bool {
+			switch {
+			case query.UserID != 0 && loan.UserID != query.UserID:
+				return false
+			case query.BookID != 0 && loan.BookID != query.BookID:
+				return false
+			case query.Status == models.LoanOpen && loan.ReturnedAt != nil:
+				return false
+			case query.Status == models.LoanReturned && loan.ReturnedAt == nil:
+				return false
+			}
+			return true
+		})
+		if !query.Ascending {
+			slices.Reverse(loans)
+		}
+		slices.SortStableFunc(loans, func(a, b models.RentedBook) int {
+			if query.Ascending {
+				return a.RentedAt.Compare(b.RentedAt)
+			}
+			return b.RentedAt.Compare(a.RentedAt)
+		})
+
+		total = int64(len(loans))
+		for _, loan := range paged(loans, query.Page, query.PageSize) {
+			rentedBooks = append(rentedBooks, d.loan(loan))
+		}
+		return nil
+	})
+	return rentedBooks, total, err
+}
+
+// openLoans returns the open loans of a user, of the book and copy unless
+// they are 0, oldest first.
+func (d *memoryData) openLoans(userID, bookID, copyID int) []models.RentedBook {
+	loans := sortedRows(d.loans, func(loan models.RentedBook) bool {
+		return loan.UserID == userID && loan.ReturnedAt == nil &&
+			(bookID == 0 || loan.BookID == bookID) &&
+			(copyID == 0 || loan.CopyID == copyID)
+	})
+	// sortedRows leaves loans rented at the same time in ID order
+	slices.SortStableFunc(loans, func(a, b models.RentedBook) int {
+		return a.RentedAt.Compare(b.RentedAt)
+	})
+	return loans
+}
+
+// loan returns a stored loan with its user, its book and contributors, and
+// its copy.
+func (d *memoryData) loan(loan models.RentedBook) models.RentedBook {
+	loan.User = d.users[loan.UserID]
+	loan.Book = d.creditedBook(d.books[loan.BookID])
+	loan.Copy = d.copies[loan.CopyID]
+	return loan
+}
+
+// hasRow tells whether any row of a table matches.
+func hasRow[T any](rows map[int]T, match func(T) bool) bool {
+	for _, row := range rows {
+		if match(row) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"library/models"
 	"time"
 )
@@ -15,52 +18,437 @@ func NewBookPostgres(db *gorm.DB) *BookPostgres {
 	return &BookPostgres{db: db}
 }
 
-func (r *BookPostgres) GetAll() ([]models.Book, error) {
+func (r *BookPostgres) GetAll(ctx context.Context, query models.BookQuery) ([]models.Book, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where(archived("books", query.Deleted))
+		if query.Title != "" {
+			db = db.Where(containsMatch(db, "books.title"), containsPattern(query.Title))
+		}
+		if query.AuthorID != 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.book_id = books.id AND book_contributors.author_id = ?)", query.AuthorID)
+		}
+		if query.SubjectID != 0 {
+			db = db.Where("EXISTS (SELECT 1 FROM book_subjects WHERE book_subjects.book_id = books.id AND book_subjects.subject_id IN ("+subjectTree+"))", query.SubjectID)
+		}
+		if query.Tag != "" {
+			db = db.Where("EXISTS (SELECT 1 FROM book_tags JOIN tags ON tags.id = book_tags.tag_id WHERE book_tags.book_id = books.id AND tags.name = ?)", query.Tag)
+		}
+		if query.PublishedFrom != nil {
+			db = db.Where("books.published_at >= ?", *query.PublishedFrom)
+		}
+		if query.PublishedTo != nil {
+			db = db.Where("books.published_at <= ?", *query.PublishedTo)
+		}
+		if query.Available != nil {
+			if *query.Available {
+				db = db.Where(availableCopiesExpr + " > 0")
+			} else {
+				db = db.Where(availableCopiesExpr + " = 0")
+			}
+		}
+		return db
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).Model(&models.Book{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var books []models.Book
-	err := r.db.Preload("Author").Find(&books).Error
-	return books, err
+	err := conn(ctx, r.db).Select("books.*, "+availableCopiesSQL).
+		Scopes(filter, paginate(query.Page, query.PageSize), preloadContributors("Contributors")).
+		Preload("Subjects").Preload("Tags").
+		Clauses(sortOrder("books", query.Sort)).
+		Find(&books).Error
+	return books, total, err
 }
 
-func (r *BookPostgres) Create(book models.Book) error {
-	return r.db.Create(&book).Error
+// Search returns a page of the books matching the text of a catalogue
+// search, best matches first, and the number of matches.
+func (r *BookPostgres) Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, int64, error) {
+	db := conn(ctx, r.db)
+	if isSQLite(db) {
+		return searchSQLite(db, query)
+	}
+
+	args := searchArgs(query.Text)
+	match := func(db *gorm.DB) *gorm.DB {
+		return db.Table("books").Where("books.deleted_at IS NULL").Where(searchMatch, args)
+	}
+
+	var (
+		hits  []models.SearchHit
+		total int64
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", typoThreshold).Error; err != nil {
+			return err
+		}
+		if err := tx.Scopes(match).Count(&total).Error; err != nil {
+			return err
+		}
+		return tx.Scopes(match, paginate(query.Page, query.PageSize)).
+			Joins(searchCredits).
+			Select(searchColumns, args).
+			Order("rank DESC, books.id").
+			Scan(&hits).Error
+	})
+	return hits, total, err
 }
 
-func (r *BookPostgres) GetByID(id int) (models.Book, error) {
+// Create adds a book together with its contributors, subjects and tags.
+// Tags are created as needed.
+func (r *BookPostgres) Create(ctx context.Context, book models.Book) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&book).Error; err != nil {
+			return err
+		}
+		if err := saveContributors(tx, book); err != nil {
+			return err
+		}
+		return saveClassification(tx, book)
+	})
+}
+
+func (r *BookPostgres) GetByID(ctx context.Context, id int) (models.Book, error) {
 	var book models.Book
-	err := r.db.Preload("Author").First(&book, id).Error
+	err := conn(ctx, r.db).Select("books.*, "+availableCopiesSQL).Scopes(preloadContributors("Contributors")).
+		Preload("Subjects").Preload("Tags").Preload("Copies").
+		Where("books.deleted_at IS NULL").
+		First(&book, id).Error
 	return book, err
 }
 
-func (r *BookPostgres) Delete(id int) error {
-	return r.db.Delete(&models.Book{}, id).Error
+// Delete archives a book, unless it is out on loan or on hold. Its copies,
+// and the loans and holds of the past, are kept.
+func (r *BookPostgres) Delete(ctx context.Context, id int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := archiveByID(tx, &models.Book{}, id, &now); err != nil {
+			return err
+		}
+		return checkIdle(tx, "book_id", []int{id})
+	})
 }
 
-func (r *BookPostgres) Update(book models.Book) error {
-	return r.db.Save(&book).Error
+// Restore brings back an archived book, unless an author of it is still
+// archived.
+func (r *BookPostgres) Restore(ctx context.Context, id int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := archiveByID(tx, &models.Book{}, id, nil); err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.BookContributor{}).
+			Joins("JOIN authors ON authors.id = book_contributors.author_id").
+			Where("book_contributors.book_id = ? AND authors.deleted_at IS NOT NULL", id).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAuthorArchived
+		}
+		return nil
+	})
 }
 
-func (r *BookPostgres) RentBook(userID, bookID int) error {
-	var rentedBook models.RentedBook
-	if err := r.db.Where("book_id = ? AND returned_at IS NULL", bookID).First(&rentedBook).Error; err == nil {
-		return fmt.Errorf("book is already rented")
+// Update saves a live book and replaces its contributors, subjects and tags
+// with the ones given.
+func (r *BookPostgres) Update(ctx context.Context, book models.Book) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := updateByID(tx.Where("deleted_at IS NULL"), &book, "deleted_at"); err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookContributor{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM book_subjects WHERE book_id = ?", book.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM book_tags WHERE book_id = ?", book.ID).Error; err != nil {
+			return err
+		}
+		if err := saveContributors(tx, book); err != nil {
+			return err
+		}
+		return saveClassification(tx, book)
+	})
+}
+
+// saveContributors credits the contributors of a book. Archived authors
+// cannot be credited, as if they did not exist.
+func saveContributors(tx *gorm.DB, book models.Book) error {
+	if len(book.Contributors) == 0 {
+		return nil
+	}
+
+	authorIDs := make([]int, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		authorIDs[i] = contributor.AuthorID
+	}
+	var count int64
+	if err := tx.Model(&models.Author{}).Where("id IN ? AND deleted_at IS NOT NULL", authorIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrForeignKey
+	}
+
+	contributors := make([]models.BookContributor, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		contributors[i] = models.BookContributor{
+			BookID:   book.ID,
+			AuthorID: contributor.AuthorID,
+			Role:     contributor.Role,
+			Position: contributor.Position,
+		}
 	}
+	return tx.Omit(clause.Associations).Create(&contributors).Error
+}
 
-	rentedBook = models.RentedBook{
-		UserID:   userID,
-		BookID:   bookID,
-		RentedAt: time.Now(),
+// saveClassification files a book under its subjects and labels it with its
+// tags, looking tags up by name and creating the missing ones.
+func saveClassification(tx *gorm.DB, book models.Book) error {
+	if len(book.Subjects) > 0 {
+		rows := make([]map[string]interface{}, len(book.Subjects))
+		for i, subject := range book.Subjects {
+			rows[i] = map[string]interface{}{"book_id": book.ID, "subject_id": subject.ID}
+		}
+		err := tx.Table("book_subjects").Create(rows).Error
+		if errors.Is(err, ErrForeignKey) {
+			return ErrUnknownSubject
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	return r.db.Create(&rentedBook).Error
+	if len(book.Tags) > 0 {
+		tags := make([]models.Tag, len(book.Tags))
+		for i, tag := range book.Tags {
+			tags[i] = models.Tag{Name: tag.Name}
+		}
+		// updating the name to itself makes RETURNING report the id of tags
+		// that already exist as well
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).Create(&tags).Error
+		if err != nil {
+			return err
+		}
+
+		rows := make([]map[string]interface{}, len(tags))
+		for i, tag := range tags {
+			rows[i] = map[string]interface{}{"book_id": book.ID, "tag_id": tag.ID}
+		}
+		return tx.Table("book_tags").Create(rows).Error
+	}
+	return nil
 }
 
-func (r *BookPostgres) ReturnBook(userID, bookID int) error {
+// preloadContributors preloads the contributors of the books at path, in
+// credit order, with their authors.
+func preloadContributors(path string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload(path, func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).Preload(path + ".Author")
+	}
+}
+
+// RentBook lends a copy of a book to the user. A specific copy can be asked
+// for with copyID; otherwise the copy set aside by the user's ready hold or
+// the first free copy is used.
+//
+// The copy row is locked for the rest of the transaction so concurrent
+// renters queue up behind it, or move on to another copy when any copy will
+// do. The partial unique index on open loans backs this up: a second open
+// loan of the same copy can never be committed.
+func (r *BookPostgres) RentBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if copyID != 0 {
+			var bookCopy models.BookCopy
+			if err := tx.First(&bookCopy, copyID).Error; err != nil {
+				return err
+			}
+			if bookID != 0 && bookCopy.BookID != bookID {
+				return ErrCopyMismatch
+			}
+			bookID = bookCopy.BookID
+		}
+
+		var hold models.Hold
+		holdErr := tx.Where("user_id = ? AND book_id = ? AND status = ?", userID, bookID, models.HoldReady).First(&hold).Error
+		if holdErr == nil && copyID == 0 && hold.CopyID != nil {
+			copyID = *hold.CopyID
+		}
+
+		var bookCopy models.BookCopy
+		query := freeCopies(tx, userID).Where("book_copies.book_id = ?", bookID)
+		if copyID != 0 {
+			query = query.Where("book_copies.id = ?", copyID).Clauses(clause.Locking{Strength: "UPDATE"})
+		} else {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Order("book_copies.id").First(&bookCopy).Error; err != nil {
+			if copyID != 0 {
+				return ErrCopyUnavailable
+			}
+			return ErrNoCopyAvailable
+		}
+
+		now := time.Now()
+		rentedBook := models.RentedBook{
+			UserID:   userID,
+			BookID:   bookID,
+			CopyID:   bookCopy.ID,
+			RentedAt: now,
+			DueAt:    now.Add(loanPeriod),
+		}
+
+		if err := tx.Create(&rentedBook).Error; err != nil {
+			if errors.Is(err, ErrDuplicate) {
+				return ErrCopyUnavailable
+			}
+			return err
+		}
+
+		if holdErr == nil {
+			return tx.Model(&hold).Updates(map[string]interface{}{"status": models.HoldFulfilled, "closed_at": now}).Error
+		}
+		return nil
+	})
+}
+
+// ReturnBook closes the user's oldest matching open loan. The loan row is
+// locked so a loan returned twice at the same time is only closed once.
+func (r *BookPostgres) ReturnBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error) {
 	var rentedBook models.RentedBook
-	if err := r.db.Where("user_id = ? AND book_id = ? AND returned_at IS NULL", userID, bookID).First(&rentedBook).Error; err != nil {
-		return fmt.Errorf("book is not rented by this user")
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("user_id = ? AND returned_at IS NULL", userID)
+		if bookID != 0 {
+			query = query.Where("book_id = ?", bookID)
+		}
+		if copyID != 0 {
+			query = query.Where("copy_id = ?", copyID)
+		}
+
+		if err := query.Clauses(clause.Locking{Strength: "UPDATE"}).Order("rented_at").First(&rentedBook).Error; err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return ErrNotRented
+			}
+			return err
+		}
+
+		now := time.Now()
+		res := tx.Model(&models.RentedBook{}).
+			Where("id = ? AND returned_at IS NULL", rentedBook.ID).
+			Update("returned_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotRented
+		}
+		rentedBook.ReturnedAt = &now
+		return nil
+	})
+	return rentedBook, err
+}
+
+// RenewBook extends the user's oldest matching open loan, found as
+// ReturnBook finds it.
+func (r *BookPostgres) RenewBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration, maxRenewals int) (models.RentedBook, error) {
+	db := conn(ctx, r.db)
+	query := db.Where("user_id = ? AND returned_at IS NULL", userID)
+	if bookID != 0 {
+		query = query.Where("book_id = ?", bookID)
+	}
+	if copyID != 0 {
+		query = query.Where("copy_id = ?", copyID)
+	}
+
+	var rentedBook models.RentedBook
+	if err := query.Order("rented_at").First(&rentedBook).Error; err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return rentedBook, ErrNotRented
+		}
+		return rentedBook, err
+	}
+
+	if rentedBook.RenewalCount >= maxRenewals {
+		return rentedBook, fmt.Errorf("%w: %d renewals allowed", ErrRenewalLimit, maxRenewals)
+	}
+	// renewing from now would forgive the days it is already late
+	if rentedBook.DueAt.Before(time.Now()) {
+		return rentedBook, fmt.Errorf("%w: it was due %s", ErrLoanOverdue, rentedBook.DueAt.Format(time.DateOnly))
+	}
+	dueAt := rentedBook.DueAt.Add(loanPeriod)
+
+	res := db.Model(&models.RentedBook{}).
+		Where("id = ? AND returned_at IS NULL AND renewal_count = ?", rentedBook.ID, rentedBook.RenewalCount).
+		Updates(map[string]interface{}{"due_at": dueAt, "renewal_count": rentedBook.RenewalCount + 1})
+	if res.Error != nil {
+		return rentedBook, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return rentedBook, ErrConcurrentUpdate
+	}
+
+	rentedBook.DueAt = dueAt
+	rentedBook.RenewalCount++
+	return rentedBook, nil
+}
+
+func (r *BookPostgres) GetOverdue(ctx context.Context) ([]models.RentedBook, error) {
+	var rentedBooks []models.RentedBook
+	err := conn(ctx, r.db).Preload("User").Scopes(preloadContributors("Book.Contributors")).Preload("Copy").
+		Where("returned_at IS NULL AND due_at < ?", time.Now()).
+		Order("due_at").
+		Find(&rentedBooks).Error
+	return rentedBooks, err
+}
+
+func (r *BookPostgres) CountOpenLoans(ctx context.Context, userID int) (int, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.RentedBook{}).Where("user_id = ? AND returned_at IS NULL", userID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *BookPostgres) GetLoans(ctx context.Context, query models.LoanQuery) ([]models.RentedBook, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		if query.UserID != 0 {
+			db = db.Where("user_id = ?", query.UserID)
+		}
+		if query.BookID != 0 {
+			db = db.Where("book_id = ?", query.BookID)
+		}
+		switch query.Status {
+		case models.LoanOpen:
+			db = db.Where("returned_at IS NULL")
+		case models.LoanReturned:
+			db = db.Where("returned_at IS NOT NULL")
+		}
+		return db
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).Model(&models.RentedBook{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "rented_at DESC, id DESC"
+	if query.Ascending {
+		order = "rented_at, id"
 	}
 
-	now := time.Now()
-	rentedBook.ReturnedAt = &now
-	return r.db.Save(&rentedBook).Error
+	var rentedBooks []models.RentedBook
+	err := conn(ctx, r.db).Preload("User").Scopes(preloadContributors("Book.Contributors")).Preload("Copy").
+		Scopes(filter, paginate(query.Page, query.PageSize)).
+		Order(order).
+		Find(&rentedBooks).Error
+	return rentedBooks, total, err
 }
@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"library/models"
 	"testing"
+	"time"
 )
 
 func TestBookPostgres_GetAll(t *testing.T) {
@@ -14,15 +16,18 @@ func TestBookPostgres_GetAll(t *testing.T) {
 	mockDB := NewMockBooks(ctrl)
 
 	expectedBooks := []models.Book{
-		{ID: 1, Title: "Book 1", AuthorID: 1},
-		{ID: 2, Title: "Book 2", AuthorID: 2},
+		{ID: 1, Title: "Book 1", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}},
+		{ID: 2, Title: "Book 2", Contributors: []models.BookContributor{{AuthorID: 2, Role: models.RoleAuthor, Position: 1}}},
 	}
 
-	mockDB.EXPECT().GetAll().Return(expectedBooks, nil)
+	query := models.BookQuery{Page: 1, PageSize: 20}
 
-	books, err := mockDB.GetAll()
+	mockDB.EXPECT().GetAll(gomock.Any(), query).Return(expectedBooks, int64(2), nil)
+
+	books, total, err := mockDB.GetAll(context.Background(), query)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBooks, books)
+	assert.Equal(t, int64(2), total)
 }
 
 func TestBookPostgres_Create(t *testing.T) {
@@ -31,11 +36,11 @@ func TestBookPostgres_Create(t *testing.T) {
 
 	mockDB := NewMockBooks(ctrl)
 
-	newBook := models.Book{Title: "New Book", AuthorID: 1}
+	newBook := models.Book{Title: "New Book", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}}
 
-	mockDB.EXPECT().Create(newBook).Return(nil)
+	mockDB.EXPECT().Create(gomock.Any(), newBook).Return(nil)
 
-	err := mockDB.Create(newBook)
+	err := mockDB.Create(context.Background(), newBook)
 	assert.NoError(t, err)
 }
 
@@ -45,11 +50,11 @@ func TestBookPostgres_GetByID(t *testing.T) {
 
 	mockDB := NewMockBooks(ctrl)
 
-	expectedBook := models.Book{ID: 1, Title: "Book 1", AuthorID: 1}
+	expectedBook := models.Book{ID: 1, Title: "Book 1", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}}
 
-	mockDB.EXPECT().GetByID(1).Return(expectedBook, nil)
+	mockDB.EXPECT().GetByID(gomock.Any(), 1).Return(expectedBook, nil)
 
-	book, err := mockDB.GetByID(1)
+	book, err := mockDB.GetByID(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBook, book)
 }
@@ -62,9 +67,9 @@ func TestBookPostgres_Delete(t *testing.T) {
 
 	bookID := 1
 
-	mockDB.EXPECT().Delete(bookID).Return(nil)
+	mockDB.EXPECT().Delete(gomock.Any(), bookID).Return(nil)
 
-	err := mockDB.Delete(bookID)
+	err := mockDB.Delete(context.Background(), bookID)
 	assert.NoError(t, err)
 }
 
@@ -74,11 +79,11 @@ func TestBookPostgres_Update(t *testing.T) {
 
 	mockDB := NewMockBooks(ctrl)
 
-	updatedBook := models.Book{ID: 1, Title: "Updated Book", AuthorID: 1}
+	updatedBook := models.Book{ID: 1, Title: "Updated Book", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}}
 
-	mockDB.EXPECT().Update(updatedBook).Return(nil)
+	mockDB.EXPECT().Update(gomock.Any(), updatedBook).Return(nil)
 
-	err := mockDB.Update(updatedBook)
+	err := mockDB.Update(context.Background(), updatedBook)
 	assert.NoError(t, err)
 }
 
@@ -89,11 +94,12 @@ func TestBookPostgres_RentBook(t *testing.T) {
 	mockDB := NewMockBooks(ctrl)
 	userID := 1
 	bookID := 1
+	loanPeriod := 14 * 24 * time.Hour
 
 	// Test case where the book is already rented
-	mockDB.EXPECT().RentBook(userID, bookID).Return(nil).Times(1)
+	mockDB.EXPECT().RentBook(gomock.Any(), userID, bookID, 0, loanPeriod).Return(nil).Times(1)
 
-	err := mockDB.RentBook(userID, bookID)
+	err := mockDB.RentBook(context.Background(), userID, bookID, 0, loanPeriod)
 	assert.NoError(t, err)
 }
 
@@ -106,8 +112,76 @@ func TestBookPostgres_ReturnBook(t *testing.T) {
 	userID := 1
 	bookID := 1
 
-	mockDB.EXPECT().ReturnBook(userID, bookID).Return(nil).Times(1)
+	expectedRental := models.RentedBook{ID: 1, UserID: userID, BookID: bookID}
+
+	mockDB.EXPECT().ReturnBook(gomock.Any(), userID, bookID, 0).Return(expectedRental, nil).Times(1)
+
+	rental, err := mockDB.ReturnBook(context.Background(), userID, bookID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRental, rental)
+}
+
+func TestBookPostgres_RenewBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockBooks(ctrl)
+
+	userID := 1
+	bookID := 1
+	loanPeriod := 14 * 24 * time.Hour
+	expectedRental := models.RentedBook{ID: 1, UserID: userID, BookID: bookID, RenewalCount: 1}
+
+	mockDB.EXPECT().RenewBook(gomock.Any(), userID, bookID, 0, loanPeriod, 2).Return(expectedRental, nil).Times(1)
+
+	rental, err := mockDB.RenewBook(context.Background(), userID, bookID, 0, loanPeriod, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRental, rental)
+}
+
+func TestBookPostgres_GetOverdue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockBooks(ctrl)
+
+	expectedRentals := []models.RentedBook{
+		{ID: 1, UserID: 1, BookID: 1, DueAt: time.Now().Add(-24 * time.Hour)},
+	}
+
+	mockDB.EXPECT().GetOverdue(gomock.Any()).Return(expectedRentals, nil)
+
+	rentals, err := mockDB.GetOverdue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRentals, rentals)
+}
+
+func TestBookPostgres_CountOpenLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockBooks(ctrl)
+
+	mockDB.EXPECT().CountOpenLoans(gomock.Any(), 1).Return(3, nil)
+
+	count, err := mockDB.CountOpenLoans(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestBookPostgres_GetLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockBooks(ctrl)
+
+	query := models.LoanQuery{UserID: 1, Status: models.LoanOpen, Page: 1, PageSize: 20}
+	expectedRentals := []models.RentedBook{{ID: 1, UserID: 1, BookID: 1}}
+
+	mockDB.EXPECT().GetLoans(gomock.Any(), query).Return(expectedRentals, int64(1), nil)
 
-	err := mockDB.ReturnBook(userID, bookID)
+	rentals, total, err := mockDB.GetLoans(context.Background(), query)
 	assert.NoError(t, err)
+	assert.Equal(t, expectedRentals, rentals)
+	assert.Equal(t, int64(1), total)
 }
@@ -0,0 +1,878 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"library/models"
+)
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	testConformance(t, NewMemoryRepository())
+}
+
+func TestPostgresRepository_Conformance(t *testing.T) {
+	testConformance(t, NewRepository(testDB(t)))
+}
+
+func TestSQLiteRepository_Conformance(t *testing.T) {
+	testConformance(t, NewRepository(testSQLiteDB(t)))
+}
+
+// conformance checks that a Repository keeps the rules every storage has to
+// keep. It only goes through the repositories, and names everything it
+// creates uniquely, so it can run against a database that is in use.
+type conformance struct {
+	repos  *Repository
+	ctx    context.Context
+	suffix string
+	made   int
+}
+
+// testConformance runs the conformance checks against repos.
+func testConformance(t *testing.T, repos *Repository) {
+	c := &conformance{repos: repos, ctx: context.Background(), suffix: fmt.Sprint(time.Now().UnixNano())}
+
+	t.Run("authors", c.authors)
+	t.Run("books", c.books)
+	t.Run("copies", c.copies)
+	t.Run("subjects", c.subjects)
+	t.Run("tags", c.tags)
+	t.Run("rent rules", c.rentRules)
+	t.Run("concurrent rent", c.concurrentRent)
+	t.Run("holds", c.holds)
+	t.Run("fines", c.fines)
+	t.Run("policies", c.policies)
+	t.Run("users", c.users)
+	t.Run("api keys", c.apiKeys)
+	t.Run("archival", c.archival)
+	t.Run("transactions", c.transactions)
+}
+
+// name returns a name no other run has used.
+func (c *conformance) name(label string) string {
+	c.made++
+	return fmt.Sprintf("conformance %s %s-%d", label, c.suffix, c.made)
+}
+
+func (c *conformance) author(t *testing.T) models.Author {
+	name := c.name("author")
+	require.NoError(t, c.repos.Authors.Create(c.ctx, models.Author{Name: name}))
+	authors, _, err := c.repos.Authors.GetAll(c.ctx, models.AuthorQuery{Name: name, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, authors, 1)
+	return authors[0]
+}
+
+// book creates a book by author with the given number of copies.
+func (c *conformance) book(t *testing.T, author models.Author, copies int) (models.Book, []models.BookCopy) {
+	title := c.name("book")
+	require.NoError(t, c.repos.Books.Create(c.ctx, models.Book{
+		Title:        title,
+		PublishedAt:  time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+		ISBN:         title,
+		Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1}},
+	}))
+	books, _, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{Title: title, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+
+	for i := 0; i < copies; i++ {
+		require.NoError(t, c.repos.Copies.Create(c.ctx, models.BookCopy{BookID: books[0].ID, Barcode: c.name("copy")}))
+	}
+	bookCopies, err := c.repos.Copies.GetByBook(c.ctx, books[0].ID)
+	require.NoError(t, err)
+	require.Len(t, bookCopies, copies)
+	return books[0], bookCopies
+}
+
+func (c *conformance) user(t *testing.T) models.User {
+	email := strings.ReplaceAll(c.name("user"), " ", "-") + "@example.com"
+	require.NoError(t, c.repos.Users.Create(c.ctx, models.User{Name: "Conformance", Email: email}))
+	user, err := c.repos.Users.GetByEmail(c.ctx, email)
+	require.NoError(t, err)
+	return user
+}
+
+func (c *conformance) authors(t *testing.T) {
+	author := c.author(t)
+
+	got, err := c.repos.Authors.GetByID(c.ctx, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, author, got)
+
+	author.Name = c.name("renamed author")
+	require.NoError(t, c.repos.Authors.Update(c.ctx, author))
+	got, err = c.repos.Authors.GetByID(c.ctx, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, author.Name, got.Name)
+
+	book, _ := c.book(t, author, 0)
+	credits, err := c.repos.Authors.GetBooks(c.ctx, author.ID)
+	require.NoError(t, err)
+	require.Len(t, credits, 1)
+	assert.Equal(t, book.Title, credits[0].Book.Title)
+
+	other := c.author(t)
+	require.NoError(t, c.repos.Authors.Delete(c.ctx, other.ID))
+	_, err = c.repos.Authors.GetByID(c.ctx, other.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, c.repos.Authors.Delete(c.ctx, other.ID), ErrNotFound)
+	assert.ErrorIs(t, c.repos.Authors.Update(c.ctx, other), ErrNotFound)
+}
+
+func (c *conformance) books(t *testing.T) {
+	author, translator := c.author(t), c.author(t)
+	subjectName := c.name("subject")
+	require.NoError(t, c.repos.Subjects.Create(c.ctx, models.Subject{Name: subjectName}))
+	subjects, err := c.repos.Subjects.GetAll(c.ctx)
+	require.NoError(t, err)
+	var subject models.Subject
+	for _, s := range subjects {
+		if s.Name == subjectName {
+			subject = s
+		}
+	}
+	require.NotZero(t, subject.ID)
+	tag := c.name("tag")
+
+	book, copies := c.book(t, author, 2)
+	got, err := c.repos.Books.GetByID(c.ctx, book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.AvailableCopies)
+
+	book.Title = c.name("retitled book")
+	book.Contributors = []models.BookContributor{
+		{AuthorID: translator.ID, Role: models.RoleTranslator, Position: 2},
+		{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1},
+	}
+	book.Subjects = []models.Subject{{ID: subject.ID}}
+	book.Tags = []models.Tag{{Name: tag}}
+	require.NoError(t, c.repos.Books.Update(c.ctx, book))
+
+	got, err = c.repos.Books.GetByID(c.ctx, book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, book.Title, got.Title)
+	assert.True(t, book.PublishedAt.Equal(got.PublishedAt))
+	require.Len(t, got.Contributors, 2)
+	assert.Equal(t, author.Name, got.Contributors[0].Author.Name)
+	assert.Equal(t, translator.Name, got.Contributors[1].Author.Name)
+	require.Len(t, got.Subjects, 1)
+	assert.Equal(t, subjectName, got.Subjects[0].Name)
+	require.Len(t, got.Tags, 1)
+	assert.Equal(t, tag, got.Tags[0].Name)
+	assert.Len(t, got.Copies, len(copies))
+
+	tagged, total, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{Tag: tag, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, book.ID, tagged[0].ID)
+
+	// an existing tag is reused rather than duplicated
+	require.NoError(t, c.repos.Books.Update(c.ctx, book))
+	_, total, err = c.repos.Books.GetAll(c.ctx, models.BookQuery{Tag: tag, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	t.Run("unique ISBN", func(t *testing.T) {
+		other, _ := c.book(t, author, 0)
+		duplicate := models.Book{Title: c.name("book"), PublishedAt: time.Now(), ISBN: other.ISBN,
+			Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1}}}
+		assert.ErrorIs(t, c.repos.Books.Create(c.ctx, duplicate), ErrDuplicate)
+
+		other.ISBN = got.ISBN
+		assert.ErrorIs(t, c.repos.Books.Update(c.ctx, other), ErrDuplicate)
+	})
+
+	t.Run("unknown references", func(t *testing.T) {
+		unknownAuthor := models.Book{Title: c.name("book"), PublishedAt: time.Now(), ISBN: c.name("isbn"),
+			Contributors: []models.BookContributor{{AuthorID: -1, Role: models.RoleAuthor, Position: 1}}}
+		assert.ErrorIs(t, c.repos.Books.Create(c.ctx, unknownAuthor), ErrForeignKey)
+
+		unknownSubject := models.Book{Title: c.name("book"), PublishedAt: time.Now(), ISBN: c.name("isbn"),
+			Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1}},
+			Subjects:     []models.Subject{{ID: -1}}}
+		assert.ErrorIs(t, c.repos.Books.Create(c.ctx, unknownSubject), ErrUnknownSubject)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		removed, _ := c.book(t, author, 0)
+		require.NoError(t, c.repos.Books.Delete(c.ctx, removed.ID))
+
+		_, err := c.repos.Books.GetByID(c.ctx, removed.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, c.repos.Books.Update(c.ctx, removed), ErrNotFound)
+		assert.ErrorIs(t, c.repos.Books.Delete(c.ctx, removed.ID), ErrNotFound)
+	})
+}
+
+func (c *conformance) copies(t *testing.T) {
+	author := c.author(t)
+	book, _ := c.book(t, author, 0)
+	other, _ := c.book(t, author, 0)
+
+	barcodes := []string{c.name("copy"), c.name("copy")}
+	for _, barcode := range barcodes {
+		require.NoError(t, c.repos.Copies.Create(c.ctx, models.BookCopy{BookID: book.ID, Barcode: barcode}))
+	}
+	copies, err := c.repos.Copies.GetByBook(c.ctx, book.ID)
+	require.NoError(t, err)
+	require.Len(t, copies, 2)
+	assert.Equal(t, barcodes, []string{copies[0].Barcode, copies[1].Barcode})
+	assert.Equal(t, models.CopyConditionGood, copies[0].Condition)
+
+	assert.ErrorIs(t, c.repos.Copies.Create(c.ctx, models.BookCopy{BookID: other.ID, Barcode: barcodes[0]}), ErrDuplicate)
+	assert.ErrorIs(t, c.repos.Copies.Create(c.ctx, models.BookCopy{BookID: 1 << 30, Barcode: c.name("copy")}), ErrForeignKey)
+
+	bookCopy := copies[0]
+	bookCopy.ShelfLocation = "B-2"
+	bookCopy.Condition = models.CopyConditionPoor
+	require.NoError(t, c.repos.Copies.Update(c.ctx, bookCopy))
+	got, err := c.repos.Copies.GetByID(c.ctx, bookCopy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, bookCopy, got)
+
+	// a copy with a loan on record is kept, one without goes
+	user := c.user(t)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, user.ID, book.ID, bookCopy.ID, time.Hour))
+	_, err = c.repos.Books.ReturnBook(c.ctx, user.ID, book.ID, 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, c.repos.Copies.Delete(c.ctx, bookCopy.ID), ErrForeignKey)
+	require.NoError(t, c.repos.Copies.Delete(c.ctx, copies[1].ID))
+	_, err = c.repos.Copies.GetByID(c.ctx, copies[1].ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, c.repos.Copies.Delete(c.ctx, copies[1].ID), ErrNotFound)
+
+	// updating an unknown copy does not create it
+	assert.ErrorIs(t, c.repos.Copies.Update(c.ctx, copies[1]), ErrNotFound)
+	_, err = c.repos.Copies.GetByID(c.ctx, copies[1].ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// subject creates a subject under parent, or at the top when it is nil.
+func (c *conformance) subject(t *testing.T, name string, parent *models.Subject) models.Subject {
+	subject := models.Subject{Name: name}
+	if parent != nil {
+		subject.ParentID = &parent.ID
+	}
+	require.NoError(t, c.repos.Subjects.Create(c.ctx, subject))
+
+	subjects, err := c.repos.Subjects.GetAll(c.ctx)
+	require.NoError(t, err)
+	i := slices.IndexFunc(subjects, func(s models.Subject) bool {
+		return s.Name == name && (s.ParentID == nil) == (parent == nil) && (parent == nil || *s.ParentID == parent.ID)
+	})
+	require.GreaterOrEqual(t, i, 0)
+	return subjects[i]
+}
+
+func (c *conformance) subjects(t *testing.T) {
+	fiction := c.subject(t, c.name("fiction"), nil)
+	fantasy := c.subject(t, "Fantasy", &fiction)
+	epic := c.subject(t, "Epic", &fantasy)
+	history := c.subject(t, c.name("history"), nil)
+
+	assert.ErrorIs(t, c.repos.Subjects.Create(c.ctx, models.Subject{Name: "fantasy", ParentID: &fiction.ID}), ErrDuplicate, "siblings have distinct names")
+	unknown := 1 << 30
+	assert.ErrorIs(t, c.repos.Subjects.Create(c.ctx, models.Subject{Name: "Orphan", ParentID: &unknown}), ErrForeignKey)
+
+	got, err := c.repos.Subjects.GetByID(c.ctx, fiction.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Children, 1)
+	assert.Equal(t, fantasy.ID, got.Children[0].ID)
+
+	// what the service asks before moving a subject, so none ends up below
+	// itself
+	for _, tc := range []struct {
+		id, ancestorID int
+		below          bool
+	}{
+		{epic.ID, fiction.ID, true},
+		{fantasy.ID, fiction.ID, true},
+		{fiction.ID, epic.ID, false},
+		{fiction.ID, fiction.ID, false},
+		{history.ID, fiction.ID, false},
+	} {
+		below, err := c.repos.Subjects.IsDescendant(c.ctx, tc.id, tc.ancestorID)
+		require.NoError(t, err)
+		assert.Equal(t, tc.below, below, "subject %d below %d", tc.id, tc.ancestorID)
+	}
+
+	// and what it locks around that question and the move
+	require.NoError(t, c.repos.WithinTransaction(c.ctx, func(ctx context.Context) error {
+		assert.NoError(t, c.repos.Subjects.LockPath(ctx, history.ID, epic.ID))
+		assert.NoError(t, c.repos.Subjects.LockPath(ctx, fantasy.ID, 0))
+		assert.ErrorIs(t, c.repos.Subjects.LockPath(ctx, unknown, fiction.ID), ErrNotFound)
+		return nil
+	}))
+
+	// books are found under every subject above the one they are filed under
+	book, _ := c.book(t, c.author(t), 0)
+	book.Subjects = []models.Subject{{ID: epic.ID}}
+	require.NoError(t, c.repos.Books.Update(c.ctx, book))
+	for _, subject := range []models.Subject{fiction, fantasy, epic} {
+		found, total, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{SubjectID: subject.ID, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total, subject.Name)
+		require.Len(t, found, 1)
+		assert.Equal(t, book.ID, found[0].ID)
+	}
+	_, total, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{SubjectID: history.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// moving a subject takes its subtree along
+	fantasy.ParentID = &history.ID
+	require.NoError(t, c.repos.Subjects.Update(c.ctx, fantasy))
+	below, err := c.repos.Subjects.IsDescendant(c.ctx, epic.ID, history.ID)
+	require.NoError(t, err)
+	assert.True(t, below)
+	_, total, err = c.repos.Books.GetAll(c.ctx, models.BookQuery{SubjectID: fiction.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// subjects with children or books are kept
+	assert.ErrorIs(t, c.repos.Subjects.Delete(c.ctx, history.ID), ErrForeignKey)
+	assert.ErrorIs(t, c.repos.Subjects.Delete(c.ctx, epic.ID), ErrForeignKey)
+	require.NoError(t, c.repos.Subjects.Delete(c.ctx, fiction.ID))
+	_, err = c.repos.Subjects.GetByID(c.ctx, fiction.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// updating an unknown subject does not create it
+	assert.ErrorIs(t, c.repos.Subjects.Update(c.ctx, fiction), ErrNotFound)
+	_, err = c.repos.Subjects.GetByID(c.ctx, fiction.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func (c *conformance) tags(t *testing.T) {
+	book, _ := c.book(t, c.author(t), 0)
+	book.Tags = []models.Tag{{Name: c.name("tag")}, {Name: c.name("tag")}}
+	require.NoError(t, c.repos.Books.Update(c.ctx, book))
+	got, err := c.repos.Books.GetByID(c.ctx, book.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Tags, 2)
+	tag, other := got.Tags[0], got.Tags[1]
+
+	tag.Name = c.name("renamed tag")
+	require.NoError(t, c.repos.Tags.Update(c.ctx, tag))
+	renamed, err := c.repos.Tags.GetByID(c.ctx, tag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tag, renamed)
+	other.Name = tag.Name
+	assert.ErrorIs(t, c.repos.Tags.Update(c.ctx, other), ErrDuplicate)
+
+	// updating an unknown tag does not create it
+	require.NoError(t, c.repos.Tags.Delete(c.ctx, tag.ID))
+	assert.ErrorIs(t, c.repos.Tags.Update(c.ctx, tag), ErrNotFound)
+	_, err = c.repos.Tags.GetByID(c.ctx, tag.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func (c *conformance) rentRules(t *testing.T) {
+	author := c.author(t)
+	book, copies := c.book(t, author, 1)
+	other, otherCopies := c.book(t, author, 1)
+	renter, patron := c.user(t), c.user(t)
+
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, book.ID, 0, time.Hour))
+
+	count, err := c.repos.Books.CountOpenLoans(c.ctx, renter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	got, err := c.repos.Books.GetByID(c.ctx, book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.AvailableCopies)
+
+	assert.ErrorIs(t, c.repos.Books.RentBook(c.ctx, patron.ID, book.ID, 0, time.Hour), ErrNoCopyAvailable)
+	assert.ErrorIs(t, c.repos.Books.RentBook(c.ctx, patron.ID, book.ID, copies[0].ID, time.Hour), ErrCopyUnavailable)
+	assert.ErrorIs(t, c.repos.Books.RentBook(c.ctx, patron.ID, book.ID, otherCopies[0].ID, time.Hour), ErrCopyMismatch)
+	assert.ErrorIs(t, c.repos.Copies.Delete(c.ctx, copies[0].ID), ErrCopyRented)
+
+	_, err = c.repos.Books.ReturnBook(c.ctx, patron.ID, book.ID, 0)
+	assert.ErrorIs(t, err, ErrNotRented)
+	_, err = c.repos.Books.RenewBook(c.ctx, patron.ID, book.ID, 0, time.Hour, 1)
+	assert.ErrorIs(t, err, ErrNotRented)
+
+	renewed, err := c.repos.Books.RenewBook(c.ctx, renter.ID, book.ID, 0, time.Hour, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed.RenewalCount)
+	_, err = c.repos.Books.RenewBook(c.ctx, renter.ID, book.ID, 0, time.Hour, 1)
+	assert.ErrorIs(t, err, ErrRenewalLimit)
+
+	// of two copies of a title, the one asked for is renewed, or else the
+	// one rented first
+	pair, pairCopies := c.book(t, author, 2)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, patron.ID, pair.ID, pairCopies[1].ID, time.Hour))
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, patron.ID, pair.ID, pairCopies[0].ID, time.Hour))
+	renewed, err = c.repos.Books.RenewBook(c.ctx, patron.ID, pair.ID, pairCopies[0].ID, time.Hour, 1)
+	require.NoError(t, err)
+	assert.Equal(t, pairCopies[0].ID, renewed.CopyID)
+	renewed, err = c.repos.Books.RenewBook(c.ctx, patron.ID, pair.ID, 0, time.Hour, 1)
+	require.NoError(t, err)
+	assert.Equal(t, pairCopies[1].ID, renewed.CopyID)
+	renewed, err = c.repos.Books.RenewBook(c.ctx, patron.ID, 0, pairCopies[1].ID, time.Hour, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, renewed.RenewalCount)
+	_, err = c.repos.Books.RenewBook(c.ctx, patron.ID, other.ID, pairCopies[0].ID, time.Hour, 2)
+	assert.ErrorIs(t, err, ErrNotRented, "a copy of another book")
+
+	// an overdue loan keeps its due date, so the fine for it is not forgiven
+	late, _ := c.book(t, author, 1)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, patron.ID, late.ID, 0, -time.Hour))
+	_, err = c.repos.Books.RenewBook(c.ctx, patron.ID, late.ID, 0, time.Hour, 1)
+	assert.ErrorIs(t, err, ErrLoanOverdue)
+	overdue, _, err := c.repos.Books.GetLoans(c.ctx, models.LoanQuery{BookID: late.ID, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	assert.Zero(t, overdue[0].RenewalCount)
+	assert.True(t, overdue[0].DueAt.Before(time.Now()))
+
+	returned, err := c.repos.Books.ReturnBook(c.ctx, renter.ID, book.ID, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, returned.ReturnedAt)
+	assert.Equal(t, copies[0].ID, returned.CopyID)
+	_, err = c.repos.Books.ReturnBook(c.ctx, renter.ID, book.ID, 0)
+	assert.ErrorIs(t, err, ErrNotRented)
+
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, patron.ID, 0, copies[0].ID, time.Hour))
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, other.ID, 0, time.Hour))
+
+	loans, total, err := c.repos.Books.GetLoans(c.ctx, models.LoanQuery{UserID: renter.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, loans, 2)
+	assert.Equal(t, other.ID, loans[0].BookID, "newest first")
+	assert.Equal(t, author.Name, loans[0].Book.Contributors[0].Author.Name)
+}
+
+func (c *conformance) concurrentRent(t *testing.T) {
+	const renters = 10
+	book, _ := c.book(t, c.author(t), 1)
+	users := make([]models.User, renters)
+	for i := range users {
+		users[i] = c.user(t)
+	}
+
+	succeeded := parallel(renters, func(i int) error {
+		return c.repos.Books.RentBook(c.ctx, users[i].ID, book.ID, 0, time.Hour)
+	})
+	assert.Equal(t, 1, succeeded)
+
+	loans, _, err := c.repos.Books.GetLoans(c.ctx, models.LoanQuery{BookID: book.ID, Status: models.LoanOpen, Page: 1, PageSize: renters})
+	require.NoError(t, err)
+	assert.Len(t, loans, 1)
+
+	// a user renting several books at once is checked against their limit
+	// one rental at a time, as the service does with the user locked
+	const limit = 2
+	errLimit := errors.New("over the limit")
+	renter, author := c.user(t), c.author(t)
+	books := make([]models.Book, renters)
+	for i := range books {
+		books[i], _ = c.book(t, author, 1)
+	}
+
+	succeeded = parallel(renters, func(i int) error {
+		return c.repos.WithinTransaction(c.ctx, func(ctx context.Context) error {
+			if _, err := c.repos.Users.Lock(ctx, renter.ID); err != nil {
+				return err
+			}
+			loans, err := c.repos.Books.CountOpenLoans(ctx, renter.ID)
+			if err != nil {
+				return err
+			}
+			if loans >= limit {
+				return errLimit
+			}
+			return c.repos.Books.RentBook(ctx, renter.ID, books[i].ID, 0, time.Hour)
+		})
+	})
+	assert.Equal(t, limit, succeeded)
+
+	count, err := c.repos.Books.CountOpenLoans(c.ctx, renter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, limit, count)
+}
+
+func (c *conformance) holds(t *testing.T) {
+	author := c.author(t)
+	book, copies := c.book(t, author, 1)
+	free, _ := c.book(t, author, 1)
+	renter, first, second, third := c.user(t), c.user(t), c.user(t), c.user(t)
+
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, book.ID, 0, time.Hour))
+	_, err := c.repos.Holds.Create(c.ctx, renter.ID, book.ID)
+	assert.ErrorIs(t, err, ErrAlreadyRenting)
+	_, err = c.repos.Holds.Create(c.ctx, first.ID, free.ID)
+	assert.ErrorIs(t, err, ErrBookAvailable)
+
+	var holds []models.Hold
+	for _, user := range []models.User{first, second, third} {
+		hold, err := c.repos.Holds.Create(c.ctx, user.ID, book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldWaiting, hold.Status)
+		holds = append(holds, hold)
+	}
+	_, err = c.repos.Holds.Create(c.ctx, first.ID, book.ID)
+	assert.ErrorIs(t, err, ErrHoldExists)
+
+	// the copy goes to the patron waiting longest, whose pickup window here
+	// has passed as soon as it opens
+	_, err = c.repos.Books.ReturnBook(c.ctx, renter.ID, book.ID, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.repos.Holds.ProcessQueue(c.ctx, book.ID, -time.Second))
+	got, err := c.repos.Holds.GetByID(c.ctx, holds[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldReady, got.Status)
+	require.NotNil(t, got.CopyID)
+	assert.Equal(t, copies[0].ID, *got.CopyID)
+	assert.Equal(t, book.Title, got.Book.Title)
+
+	// once it has passed the copy moves on to the next in the queue
+	require.NoError(t, c.repos.Holds.ProcessQueue(c.ctx, book.ID, time.Hour))
+	got, err = c.repos.Holds.GetByID(c.ctx, holds[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldExpired, got.Status)
+	queue, err := c.repos.Holds.GetByBook(c.ctx, book.ID)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, []int{holds[1].ID, holds[2].ID}, []int{queue[0].ID, queue[1].ID}, "first come, first served")
+	assert.Equal(t, models.HoldReady, queue[0].Status)
+	assert.Equal(t, second.ID, queue[0].User.ID)
+	assert.Equal(t, models.HoldWaiting, queue[1].Status)
+
+	// the copy set aside is only lent to the patron it is set aside for
+	assert.ErrorIs(t, c.repos.Books.RentBook(c.ctx, third.ID, book.ID, 0, time.Hour), ErrNoCopyAvailable)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, second.ID, book.ID, 0, time.Hour))
+	got, err = c.repos.Holds.GetByID(c.ctx, holds[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldFulfilled, got.Status)
+	held, err := c.repos.Holds.GetByUser(c.ctx, second.ID)
+	require.NoError(t, err)
+	assert.Empty(t, held, "a fulfilled hold is no longer active")
+
+	held, err = c.repos.Holds.GetByUser(c.ctx, third.ID)
+	require.NoError(t, err)
+	require.Len(t, held, 1)
+	assert.Equal(t, book.Title, held[0].Book.Title)
+	require.NoError(t, c.repos.Holds.Cancel(c.ctx, holds[2].ID))
+	assert.ErrorIs(t, c.repos.Holds.Cancel(c.ctx, holds[2].ID), ErrHoldNotActive)
+	assert.ErrorIs(t, c.repos.Holds.Cancel(c.ctx, 1<<30), ErrNotFound)
+
+	// processing one queue leaves the others alone
+	other, _ := c.book(t, author, 1)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, other.ID, 0, time.Hour))
+	waiting, err := c.repos.Holds.Create(c.ctx, first.ID, other.ID)
+	require.NoError(t, err)
+	_, err = c.repos.Books.ReturnBook(c.ctx, renter.ID, other.ID, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.repos.Holds.ProcessQueue(c.ctx, book.ID, time.Hour))
+	got, err = c.repos.Holds.GetByID(c.ctx, waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldWaiting, got.Status)
+	require.NoError(t, c.repos.Holds.ProcessQueue(c.ctx, other.ID, time.Hour))
+	got, err = c.repos.Holds.GetByID(c.ctx, waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldReady, got.Status)
+}
+
+func (c *conformance) fines(t *testing.T) {
+	author := c.author(t)
+	user := c.user(t)
+	var loans []models.RentedBook
+	for i := 0; i < 2; i++ {
+		book, _ := c.book(t, author, 1)
+		require.NoError(t, c.repos.Books.RentBook(c.ctx, user.ID, book.ID, 0, -time.Hour))
+		loan, err := c.repos.Books.ReturnBook(c.ctx, user.ID, book.ID, 0)
+		require.NoError(t, err)
+		loan.Book = book
+		loans = append(loans, loan)
+	}
+
+	require.NoError(t, c.repos.Fines.Create(c.ctx, models.Fine{UserID: user.ID, RentedBookID: loans[0].ID, DaysLate: 3, Amount: 75}))
+	require.NoError(t, c.repos.Fines.Create(c.ctx, models.Fine{UserID: user.ID, RentedBookID: loans[1].ID, DaysLate: 2, Amount: 50}))
+	assert.ErrorIs(t, c.repos.Fines.Create(c.ctx, models.Fine{UserID: user.ID, RentedBookID: loans[0].ID, DaysLate: 3, Amount: 75}), ErrDuplicate, "a loan is fined once")
+	assert.ErrorIs(t, c.repos.Fines.Create(c.ctx, models.Fine{UserID: 1 << 30, RentedBookID: 1 << 30, Amount: 75}), ErrForeignKey)
+
+	fines, err := c.repos.Fines.GetByUser(c.ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, fines, 2)
+	assert.Equal(t, []int64{75, 50}, []int64{fines[0].Amount, fines[1].Amount}, "oldest first")
+	assert.Equal(t, loans[0].Book.Title, fines[0].RentedBook.Book.Title)
+	balance, err := c.repos.Fines.Balance(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(125), balance)
+
+	// a waived fine is no longer owed, and is waived once
+	require.NoError(t, c.repos.Fines.Waive(c.ctx, fines[1].ID, "returned to the wrong branch"))
+	assert.ErrorIs(t, c.repos.Fines.Waive(c.ctx, fines[1].ID, "again"), ErrFineWaived)
+	assert.ErrorIs(t, c.repos.Fines.Waive(c.ctx, 1<<30, "unknown"), ErrNotFound)
+	waived, err := c.repos.Fines.GetByID(c.ctx, fines[1].ID)
+	require.NoError(t, err)
+	assert.NotNil(t, waived.WaivedAt)
+	assert.Equal(t, "returned to the wrong branch", waived.WaiveReason)
+	balance, err = c.repos.Fines.Balance(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(75), balance)
+
+	// payments count against every fine still owed
+	require.NoError(t, c.repos.Fines.CreatePayment(c.ctx, models.Payment{UserID: user.ID, Amount: 25, Note: "cash"}))
+	require.NoError(t, c.repos.Fines.CreatePayment(c.ctx, models.Payment{UserID: user.ID, Amount: 30, Note: "card"}))
+	payments, err := c.repos.Fines.GetPayments(c.ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+	assert.Equal(t, []string{"cash", "card"}, []string{payments[0].Note, payments[1].Note})
+	balance, err = c.repos.Fines.Balance(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), balance)
+
+	balance, err = c.repos.Fines.Balance(c.ctx, c.user(t).ID)
+	require.NoError(t, err)
+	assert.Zero(t, balance, "a user without fines")
+}
+
+func (c *conformance) policies(t *testing.T) {
+	policy := models.LoanPolicy{Name: c.name("policy"), MaxLoans: 3, LoanDays: 7, MaxRenewals: 1}
+	require.NoError(t, c.repos.LoanPolicies.Create(c.ctx, policy))
+	assert.ErrorIs(t, c.repos.LoanPolicies.Create(c.ctx, policy), ErrDuplicate)
+
+	policies, err := c.repos.LoanPolicies.GetAll(c.ctx)
+	require.NoError(t, err)
+	assert.True(t, slices.IsSortedFunc(policies, func(a, b models.LoanPolicy) int { return a.ID - b.ID }))
+	i := slices.IndexFunc(policies, func(p models.LoanPolicy) bool { return p.Name == policy.Name })
+	require.GreaterOrEqual(t, i, 0)
+	policy.ID = policies[i].ID
+
+	policy.MaxLoans = 4
+	require.NoError(t, c.repos.LoanPolicies.Update(c.ctx, policy))
+	got, err := c.repos.LoanPolicies.GetByID(c.ctx, policy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, policy, got)
+
+	// users under a policy are lent by it, and keep it from being deleted
+	user := c.user(t)
+	user.LoanPolicyID = &policy.ID
+	require.NoError(t, c.repos.Users.Update(c.ctx, user))
+	locked, err := c.repos.Users.Lock(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, policy, locked.LoanPolicy)
+	assert.ErrorIs(t, c.repos.LoanPolicies.Delete(c.ctx, policy.ID), ErrForeignKey)
+
+	user.LoanPolicyID = nil
+	require.NoError(t, c.repos.Users.Update(c.ctx, user))
+	require.NoError(t, c.repos.LoanPolicies.Delete(c.ctx, policy.ID))
+	_, err = c.repos.LoanPolicies.GetByID(c.ctx, policy.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, c.repos.LoanPolicies.Delete(c.ctx, policy.ID), ErrNotFound)
+
+	// updating an unknown policy does not create it
+	assert.ErrorIs(t, c.repos.LoanPolicies.Update(c.ctx, policy), ErrNotFound)
+	_, err = c.repos.LoanPolicies.GetByID(c.ctx, policy.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func (c *conformance) users(t *testing.T) {
+	user := c.user(t)
+
+	duplicate := models.User{Name: "Conformance", Email: user.Email}
+	assert.ErrorIs(t, c.repos.Users.Create(c.ctx, duplicate), ErrDuplicate)
+	duplicate.Email = strings.ToUpper(user.Email)
+	assert.ErrorIs(t, c.repos.Users.Create(c.ctx, duplicate), ErrDuplicate, "emails are unique ignoring case")
+
+	got, err := c.repos.Users.GetByEmail(c.ctx, strings.ToUpper(user.Email))
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+	assert.Equal(t, models.RolePatron, got.Role)
+
+	require.NoError(t, c.repos.Users.SetRole(c.ctx, user.ID, models.RoleLibrarian))
+	user.Name = "Renamed"
+	user.Role = models.RoleAdmin
+	user.PasswordHash = "hash"
+	require.NoError(t, c.repos.Users.Update(c.ctx, user))
+	got, err = c.repos.Users.GetByID(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Name)
+	assert.Equal(t, models.RoleLibrarian, got.Role, "Update leaves the role alone")
+	assert.Empty(t, got.PasswordHash, "Update leaves the password hash alone")
+
+	book, _ := c.book(t, c.author(t), 1)
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, user.ID, book.ID, 0, time.Hour))
+	assert.ErrorIs(t, c.repos.Users.Delete(c.ctx, user.ID), ErrOpenLoans, "a user with loans")
+
+	removed := c.user(t)
+	require.NoError(t, c.repos.Users.Delete(c.ctx, removed.ID))
+	_, err = c.repos.Users.GetByID(c.ctx, removed.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, c.repos.Users.Delete(c.ctx, removed.ID), ErrNotFound)
+	assert.ErrorIs(t, c.repos.Users.Update(c.ctx, removed), ErrNotFound)
+	assert.ErrorIs(t, c.repos.Users.SetRole(c.ctx, removed.ID, models.RoleAdmin), ErrNotFound)
+	_, err = c.repos.Users.Lock(c.ctx, removed.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func (c *conformance) apiKeys(t *testing.T) {
+	user := c.user(t)
+	key, err := c.repos.APIKeys.Create(c.ctx, models.APIKey{
+		Name:        c.name("key"),
+		Prefix:      "lib_conform",
+		KeyHash:     c.name("hash"),
+		Scopes:      []string{"loans:write", "books:read"},
+		CreatedByID: &user.ID,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, key.ID)
+	assert.False(t, key.CreatedAt.IsZero())
+
+	_, err = c.repos.APIKeys.Create(c.ctx, models.APIKey{Name: c.name("key"), Prefix: "lib_conform", KeyHash: key.KeyHash, Scopes: []string{}})
+	assert.ErrorIs(t, err, ErrDuplicate)
+	unknown := 1 << 30
+	_, err = c.repos.APIKeys.Create(c.ctx, models.APIKey{Name: c.name("key"), Prefix: "lib_conform", KeyHash: c.name("hash"), Scopes: []string{}, CreatedByID: &unknown})
+	assert.ErrorIs(t, err, ErrForeignKey)
+
+	got, err := c.repos.APIKeys.GetByHash(c.ctx, key.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, got.ID)
+	assert.Equal(t, key.Scopes, got.Scopes)
+	assert.Equal(t, &user.ID, got.CreatedByID)
+	_, err = c.repos.APIKeys.GetByHash(c.ctx, c.name("hash"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	keys, err := c.repos.APIKeys.GetAll(c.ctx)
+	require.NoError(t, err)
+	assert.True(t, slices.IsSortedFunc(keys, func(a, b models.APIKey) int { return a.ID - b.ID }))
+	assert.True(t, slices.ContainsFunc(keys, func(k models.APIKey) bool { return k.ID == key.ID }))
+
+	// a use is recorded only once the last one is touchInterval old
+	lastUsed := func() time.Time {
+		got, err := c.repos.APIKeys.GetByHash(c.ctx, key.KeyHash)
+		require.NoError(t, err)
+		require.NotNil(t, got.LastUsedAt)
+		return *got.LastUsedAt
+	}
+	used := time.Now()
+	require.NoError(t, c.repos.APIKeys.Touch(c.ctx, key.ID, used))
+	assert.WithinDuration(t, used, lastUsed(), time.Millisecond, "first use")
+	require.NoError(t, c.repos.APIKeys.Touch(c.ctx, key.ID, used.Add(touchInterval/2)))
+	assert.WithinDuration(t, used, lastUsed(), time.Millisecond, "a use within touchInterval")
+	require.NoError(t, c.repos.APIKeys.Touch(c.ctx, key.ID, used.Add(2*touchInterval)))
+	assert.WithinDuration(t, used.Add(2*touchInterval), lastUsed(), time.Millisecond, "a use after touchInterval")
+	assert.NoError(t, c.repos.APIKeys.Touch(c.ctx, unknown, used), "an unknown key")
+
+	// a key is revoked once, and revoking tells a revoked key from none
+	revoked := time.Now()
+	require.NoError(t, c.repos.APIKeys.Revoke(c.ctx, key.ID, revoked))
+	got, err = c.repos.APIKeys.GetByHash(c.ctx, key.KeyHash)
+	require.NoError(t, err)
+	if assert.NotNil(t, got.RevokedAt) {
+		assert.WithinDuration(t, revoked, *got.RevokedAt, time.Millisecond)
+	}
+	assert.ErrorIs(t, c.repos.APIKeys.Revoke(c.ctx, key.ID, time.Now()), ErrAPIKeyRevoked)
+	err = c.repos.APIKeys.Revoke(c.ctx, unknown, time.Now())
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrAPIKeyRevoked)
+}
+
+func (c *conformance) archival(t *testing.T) {
+	author := c.author(t)
+	book, copies := c.book(t, author, 1)
+	renter, waiting := c.user(t), c.user(t)
+
+	// open loans and holds keep books, their authors and users from being archived
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, book.ID, 0, time.Hour))
+	assert.ErrorIs(t, c.repos.Books.Delete(c.ctx, book.ID), ErrOpenLoans)
+	assert.ErrorIs(t, c.repos.Authors.Delete(c.ctx, author.ID), ErrOpenLoans)
+	assert.ErrorIs(t, c.repos.Users.Delete(c.ctx, renter.ID), ErrOpenLoans)
+	_, err := c.repos.Authors.GetByID(c.ctx, author.ID)
+	require.NoError(t, err, "a refused archival leaves the author alone")
+
+	hold, err := c.repos.Holds.Create(c.ctx, waiting.ID, book.ID)
+	require.NoError(t, err)
+	_, err = c.repos.Books.ReturnBook(c.ctx, renter.ID, book.ID, 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, c.repos.Books.Delete(c.ctx, book.ID), ErrActiveHolds)
+	assert.ErrorIs(t, c.repos.Users.Delete(c.ctx, waiting.ID), ErrActiveHolds)
+	require.NoError(t, c.repos.Holds.Cancel(c.ctx, hold.ID))
+
+	// archiving an author archives their books, which only admin listings show
+	require.NoError(t, c.repos.Authors.Delete(c.ctx, author.ID))
+	_, err = c.repos.Books.GetByID(c.ctx, book.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, total, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{Title: book.Title, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	archived, total, err := c.repos.Books.GetAll(c.ctx, models.BookQuery{Title: book.Title, Deleted: true, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, archived, 1)
+	assert.NotNil(t, archived[0].DeletedAt)
+	authors, _, err := c.repos.Authors.GetAll(c.ctx, models.AuthorQuery{Name: author.Name, Deleted: true, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Len(t, authors, 1)
+	hits, _, err := c.repos.Books.Search(c.ctx, models.SearchQuery{Text: book.Title, Page: 1, PageSize: 100})
+	require.NoError(t, err)
+	assert.False(t, slices.ContainsFunc(hits, func(hit models.SearchHit) bool { return hit.BookID == book.ID }), "archived books are not found")
+
+	// nothing new can refer to what is archived
+	assert.ErrorIs(t, c.repos.Books.RentBook(c.ctx, renter.ID, book.ID, 0, time.Hour), ErrNoCopyAvailable)
+	_, err = c.repos.Holds.Create(c.ctx, renter.ID, book.ID)
+	assert.ErrorIs(t, err, ErrForeignKey)
+	assert.ErrorIs(t, c.repos.Books.Create(c.ctx, models.Book{
+		Title:        c.name("book"),
+		PublishedAt:  time.Now(),
+		ISBN:         c.name("isbn"),
+		Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1}},
+	}), ErrForeignKey)
+	assert.ErrorIs(t, c.repos.Books.Restore(c.ctx, book.ID), ErrAuthorArchived)
+
+	// restoring the author brings back the books archived with them, but not
+	// a book archived on its own before
+	require.NoError(t, c.repos.Authors.Restore(c.ctx, author.ID))
+	_, err = c.repos.Books.GetByID(c.ctx, book.ID)
+	require.NoError(t, err)
+	assert.ErrorIs(t, c.repos.Authors.Restore(c.ctx, author.ID), ErrNotFound, "an author who is not archived")
+
+	require.NoError(t, c.repos.Books.Delete(c.ctx, book.ID))
+	require.NoError(t, c.repos.Authors.Delete(c.ctx, author.ID))
+	require.NoError(t, c.repos.Authors.Restore(c.ctx, author.ID))
+	_, err = c.repos.Books.GetByID(c.ctx, book.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, c.repos.Books.Restore(c.ctx, book.ID))
+	require.NoError(t, c.repos.Books.RentBook(c.ctx, renter.ID, book.ID, copies[0].ID, time.Hour))
+
+	// archived users cannot log in until they are restored
+	require.NoError(t, c.repos.Users.Delete(c.ctx, waiting.ID))
+	_, err = c.repos.Users.GetByEmail(c.ctx, waiting.Email)
+	assert.ErrorIs(t, err, ErrNotFound)
+	users, _, err := c.repos.Users.GetAll(c.ctx, models.UserQuery{Email: waiting.Email, Deleted: true, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	require.NoError(t, c.repos.Users.Restore(c.ctx, waiting.ID))
+	_, err = c.repos.Users.GetByEmail(c.ctx, waiting.Email)
+	assert.NoError(t, err)
+}
+
+func (c *conformance) transactions(t *testing.T) {
+	failure := errors.New("failure")
+	name := c.name("rolled back author")
+
+	err := c.repos.WithinTransaction(c.ctx, func(ctx context.Context) error {
+		require.NoError(t, c.repos.Authors.Create(ctx, models.Author{Name: name}))
+		_, total, err := c.repos.Authors.GetAll(ctx, models.AuthorQuery{Name: name, Page: 1, PageSize: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total, "the transaction sees its own work")
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, total, err := c.repos.Authors.GetAll(c.ctx, models.AuthorQuery{Name: name, Page: 1, PageSize: 1})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
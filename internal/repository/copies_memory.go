@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"library/models"
+)
+
+// availableCopies counts the copies of a book that are neither lent out nor
+// set aside for a ready hold.
+func (d *memoryData) availableCopies(bookID int) int {
+	return len(sortedRows(d.copies, func(bookCopy models.BookCopy) bool {
+		return bookCopy.BookID == bookID && !d.lentOut(bookCopy.ID) &&
+			!hasRow(d.holds, func(hold models.Hold) bool {
+				return hold.Status == models.HoldReady && hold.CopyID != nil && *hold.CopyID == bookCopy.ID
+			})
+	}))
+}
+
+// freeCopies returns the copies of a book, in ID order, that are not lent
+// out and not set aside for a ready hold of anyone other than userID.
+// Copies of archived books are never free.
+func (d *memoryData) freeCopies(bookID, userID int) []models.BookCopy {
+	if book, ok := d.books[bookID]; ok && book.DeletedAt != nil {
+		return nil
+	}
+	return sortedRows(d.copies, func(bookCopy models.BookCopy) bool {
+		return bookCopy.BookID == bookID && !d.lentOut(bookCopy.ID) &&
+			!hasRow(d.holds, func(hold models.Hold) bool {
+				return hold.Status == models.HoldReady && hold.CopyID != nil && *hold.CopyID == bookCopy.ID && hold.UserID != userID
+			})
+	})
+}
+
+// lentOut tells whether a copy has an open loan.
+func (d *memoryData) lentOut(copyID int) bool {
+	return hasRow(d.loans, func(loan models.RentedBook) bool {
+		return loan.CopyID == copyID && loan.ReturnedAt == nil
+	})
+}
+
+type CopyMemory struct {
+	store *memoryStore
+}
+
+func (r *CopyMemory) GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error) {
+	var copies []models.BookCopy
+	err := r.store.do(ctx, func(d *memoryData) error {
+		copies = sortedRows(d.copies, func(bookCopy models.BookCopy) bool {
+			return bookCopy.BookID == bookID
+		})
+		return nil
+	})
+	return copies, err
+}
+
+func (r *CopyMemory) Create(ctx context.Context, bookCopy models.BookCopy) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		bookCopy.ID = 0
+		return d.saveCopy(bookCopy)
+	})
+}
+
+func (r *CopyMemory) GetByID(ctx context.Context, id int) (models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if bookCopy, ok = d.copies[id]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return bookCopy, err
+}
+
+// Delete refuses to delete a copy that is lent out, or that loans or holds
+// of the past refer to.
+func (r *CopyMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if d.lentOut(id) {
+			return ErrCopyRented
+		}
+		if _, ok := d.copies[id]; !ok {
+			return ErrNotFound
+		}
+		if hasRow(d.loans, func(loan models.RentedBook) bool { return loan.CopyID == id }) ||
+			hasRow(d.holds, func(hold models.Hold) bool { return hold.CopyID != nil && *hold.CopyID == id }) {
+			return ErrForeignKey
+		}
+		delete(d.copies, id)
+		return nil
+	})
+}
+
+// Update saves a copy, adding it when there is none with its ID, as Save
+// does.
+func (r *CopyMemory) Update(ctx context.Context, bookCopy models.BookCopy) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.copies[bookCopy.ID]; !ok {
+			return ErrNotFound
+		}
+		return d.saveCopy(bookCopy)
+	})
+}
+
+// saveCopy stores a copy, as a new one when it has no ID, unless its
+// barcode is taken or its book does not exist.
+func (d *memoryData) saveCopy(bookCopy models.BookCopy) error {
+	if hasRow(d.copies, func(other models.BookCopy) bool {
+		return other.ID != bookCopy.ID && other.Barcode == bookCopy.Barcode
+	}) {
+		return ErrDuplicate
+	}
+	if _, ok := d.books[bookCopy.BookID]; !ok {
+		return ErrForeignKey
+	}
+
+	if bookCopy.ID == 0 {
+		bookCopy.ID = d.nextID("book_copies")
+	}
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = models.CopyConditionGood
+	}
+	d.copies[bookCopy.ID] = bookCopy
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"library/models"
+)
+
+// availableCopiesExpr counts the copies of a book that are neither lent out
+// nor set aside for a ready hold.
+const availableCopiesExpr = `(SELECT COUNT(*) FROM book_copies
+	WHERE book_copies.book_id = books.id
+	AND NOT EXISTS (SELECT 1 FROM rented_books WHERE rented_books.copy_id = book_copies.id AND rented_books.returned_at IS NULL)
+	AND NOT EXISTS (SELECT 1 FROM holds WHERE holds.copy_id = book_copies.id AND holds.status = 'ready'))`
+
+const availableCopiesSQL = availableCopiesExpr + " AS available_copies"
+
+// freeCopies selects copies that are not lent out and not set aside for a
+// ready hold of anyone other than userID. Copies of archived books are never
+// free.
+func freeCopies(db *gorm.DB, userID int) *gorm.DB {
+	return db.Model(&models.BookCopy{}).
+		Where("NOT EXISTS (SELECT 1 FROM books WHERE books.id = book_copies.book_id AND books.deleted_at IS NOT NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM rented_books WHERE rented_books.copy_id = book_copies.id AND rented_books.returned_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM holds WHERE holds.copy_id = book_copies.id AND holds.status = ? AND holds.user_id <> ?)", models.HoldReady, userID)
+}
+
+type CopyPostgres struct {
+	db *gorm.DB
+}
+
+func NewCopyPostgres(db *gorm.DB) *CopyPostgres {
+	return &CopyPostgres{db: db}
+}
+
+func (r *CopyPostgres) GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error) {
+	var copies []models.BookCopy
+	err := conn(ctx, r.db).Where("book_id = ?", bookID).Order("id").Find(&copies).Error
+	return copies, err
+}
+
+func (r *CopyPostgres) Create(ctx context.Context, bookCopy models.BookCopy) error {
+	return conn(ctx, r.db).Create(&bookCopy).Error
+}
+
+func (r *CopyPostgres) GetByID(ctx context.Context, id int) (models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := conn(ctx, r.db).First(&bookCopy, id).Error
+	return bookCopy, err
+}
+
+func (r *CopyPostgres) Delete(ctx context.Context, id int) error {
+	db := conn(ctx, r.db)
+	var count int64
+	if err := db.Model(&models.RentedBook{}).Where("copy_id = ? AND returned_at IS NULL", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCopyRented
+	}
+	return deleteByID(db, &models.BookCopy{}, id)
+}
+
+func (r *CopyPostgres) Update(ctx context.Context, bookCopy models.BookCopy) error {
+	return updateByID(conn(ctx, r.db), &bookCopy)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Drivers of the databases NewDB opens.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Config tells NewDB which database to open. Path is the file of a SQLite
+// database, or ":memory:" for one that lives as long as the process; the
+// other fields are for Postgres.
+type Config struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	DBName   string
+	SSLMode  string
+	Path     string
+}
+
+// NewDB opens the database of cfg.Driver, Postgres unless it says sqlite.
+// The repositories of NewRepository and the migrations work with either.
+func NewDB(cfg Config) (*gorm.DB, error) {
+	config := &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
+	}
+
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
+		return gorm.Open(postgres.Open(dsn), config)
+	case DriverSQLite:
+		return openSQLite(cfg.Path, config)
+	default:
+		return nil, fmt.Errorf("unknown db driver %q, expected %s or %s", cfg.Driver, DriverPostgres, DriverSQLite)
+	}
+}
+
+// openSQLite opens the SQLite database at path with foreign keys enforced.
+// The pool keeps to a single connection: SQLite lets one transaction write
+// at a time, so transactions wait for each other instead of failing as
+// busy, and an in-memory database is only seen by the connection that made
+// it.
+func openSQLite(path string, config *gorm.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file:"+path+"?_pragma=foreign_keys(1)"), config)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// isSQLite tells whether db is a SQLite database, for the few queries that
+// are written differently for it.
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == DriverSQLite
+}
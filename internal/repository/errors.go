@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned by the repositories. The service layer translates them
+// into domain errors; any other error is a fault.
+var (
+	ErrNotFound   = gorm.ErrRecordNotFound
+	ErrDuplicate  = gorm.ErrDuplicatedKey
+	ErrForeignKey = gorm.ErrForeignKeyViolated
+
+	ErrCopyMismatch     = errors.New("copy does not belong to this book")
+	ErrCopyUnavailable  = errors.New("copy is not available")
+	ErrNoCopyAvailable  = errors.New("no copy of this book is available")
+	ErrNotRented        = errors.New("book is not rented by this user")
+	ErrRenewalLimit     = errors.New("renewal limit reached")
+	ErrLoanOverdue      = errors.New("loan is overdue")
+	ErrConcurrentUpdate = errors.New("rental was modified concurrently")
+	ErrCopyRented       = errors.New("copy is rented")
+	ErrHoldExists       = errors.New("book is already on hold for this user")
+	ErrAlreadyRenting   = errors.New("book is already rented by this user")
+	ErrBookAvailable    = errors.New("book is available for rent")
+	ErrHoldNotActive    = errors.New("hold is not active")
+	ErrFineWaived       = errors.New("fine is already waived")
+	ErrUnknownSubject   = errors.New("subject does not exist")
+	ErrAPIKeyRevoked    = errors.New("API key is already revoked")
+	ErrOpenLoans        = errors.New("books are still out on loan")
+	ErrActiveHolds      = errors.New("books are still on hold")
+	ErrAuthorArchived   = errors.New("an author of the book is archived")
+)
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"library/models"
+	"slices"
+	"time"
+)
+
+type FineMemory struct {
+	store *memoryStore
+}
+
+func (r *FineMemory) Create(ctx context.Context, fine models.Fine) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if hasRow(d.fines, func(other models.Fine) bool { return other.RentedBookID == fine.RentedBookID }) {
+			return ErrDuplicate
+		}
+		if _, ok := d.users[fine.UserID]; !ok {
+			return ErrForeignKey
+		}
+		if _, ok := d.loans[fine.RentedBookID]; !ok {
+			return ErrForeignKey
+		}
+
+		fine.ID = d.nextID("fines")
+		if fine.CreatedAt.IsZero() {
+			fine.CreatedAt = time.Now()
+		}
+		fine.RentedBook = models.RentedBook{}
+		d.fines[fine.ID] = fine
+		return nil
+	})
+}
+
+func (r *FineMemory) GetByID(ctx context.Context, id int) (models.Fine, error) {
+	var fine models.Fine
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if fine, ok = d.fines[id]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return fine, err
+}
+
+// GetByUser returns the fines of a user, oldest first, with the loans they
+// are for and the books lent.
+func (r *FineMemory) GetByUser(ctx context.Context, userID int) ([]models.Fine, error) {
+	var fines []models.Fine
+	err := r.store.do(ctx, func(d *memoryData) error {
+		fines = sortedRows(d.fines, func(fine models.Fine) bool { return fine.UserID == userID })
+		slices.SortStableFunc(fines, func(a, b models.Fine) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		for i, fine := range fines {
+			fines[i].RentedBook = d.loans[fine.RentedBookID]
+			fines[i].RentedBook.Book = d.plainBook(fines[i].RentedBook.BookID)
+		}
+		return nil
+	})
+	return fines, err
+}
+
+func (r *FineMemory) Waive(ctx context.Context, id int, reason string) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		fine, ok := d.fines[id]
+		if !ok {
+			return ErrNotFound
+		}
+		if fine.WaivedAt != nil {
+			return ErrFineWaived
+		}
+		now := time.Now()
+		fine.WaivedAt = &now
+		fine.WaiveReason = reason
+		d.fines[id] = fine
+		return nil
+	})
+}
+
+func (r *FineMemory) CreatePayment(ctx context.Context, payment models.Payment) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.users[payment.UserID]; !ok {
+			return ErrForeignKey
+		}
+		payment.ID = d.nextID("payments")
+		if payment.CreatedAt.IsZero() {
+			payment.CreatedAt = time.Now()
+		}
+		d.payments[payment.ID] = payment
+		return nil
+	})
+}
+
+func (r *FineMemory) GetPayments(ctx context.Context, userID int) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := r.store.do(ctx, func(d *memoryData) error {
+		payments = sortedRows(d.payments, func(payment models.Payment) bool { return payment.UserID == userID })
+		slices.SortStableFunc(payments, func(a, b models.Payment) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		return nil
+	})
+	return payments, err
+}
+
+// Balance is what a user owes: fines that were not waived minus payments.
+func (r *FineMemory) Balance(ctx context.Context, userID int) (int64, error) {
+	var balance int64
+	err := r.store.do(ctx, func(d *memoryData) error {
+		for _, fine := range d.fines {
+			if fine.UserID == userID && fine.WaivedAt == nil {
+				balance += fine.Amount
+			}
+		}
+		for _, payment := range d.payments {
+			if payment.UserID == userID {
+				balance -= payment.Amount
+			}
+		}
+		return nil
+	})
+	return balance, err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"library/models"
+	"time"
+)
+
+type FinePostgres struct {
+	db *gorm.DB
+}
+
+func NewFinePostgres(db *gorm.DB) *FinePostgres {
+	return &FinePostgres{db: db}
+}
+
+func (r *FinePostgres) Create(ctx context.Context, fine models.Fine) error {
+	return conn(ctx, r.db).Create(&fine).Error
+}
+
+func (r *FinePostgres) GetByID(ctx context.Context, id int) (models.Fine, error) {
+	var fine models.Fine
+	err := conn(ctx, r.db).First(&fine, id).Error
+	return fine, err
+}
+
+func (r *FinePostgres) GetByUser(ctx context.Context, userID int) ([]models.Fine, error) {
+	var fines []models.Fine
+	err := conn(ctx, r.db).Preload("RentedBook.Book").Where("user_id = ?", userID).Order("created_at").Find(&fines).Error
+	return fines, err
+}
+
+func (r *FinePostgres) Waive(ctx context.Context, id int, reason string) error {
+	db := conn(ctx, r.db)
+	res := db.Model(&models.Fine{}).
+		Where("id = ? AND waived_at IS NULL", id).
+		Updates(map[string]interface{}{"waived_at": time.Now(), "waive_reason": reason})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if err := db.First(&models.Fine{}, id).Error; err != nil {
+			return err
+		}
+		return ErrFineWaived
+	}
+	return nil
+}
+
+func (r *FinePostgres) CreatePayment(ctx context.Context, payment models.Payment) error {
+	return conn(ctx, r.db).Create(&payment).Error
+}
+
+func (r *FinePostgres) GetPayments(ctx context.Context, userID int) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at").Find(&payments).Error
+	return payments, err
+}
+
+// Balance is what a user owes: fines that were not waived minus payments.
+func (r *FinePostgres) Balance(ctx context.Context, userID int) (int64, error) {
+	db := conn(ctx, r.db)
+	var fined, paid int64
+	if err := db.Model(&models.Fine{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND waived_at IS NULL", userID).
+		Scan(&fined).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Model(&models.Payment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", userID).
+		Scan(&paid).Error; err != nil {
+		return 0, err
+	}
+	return fined - paid, nil
+}
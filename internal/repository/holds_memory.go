@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"library/models"
+	"slices"
+	"time"
+)
+
+type HoldMemory struct {
+	store *memoryStore
+}
+
+// Create puts a book on hold for a user. Archived books and users cannot be
+// held for, as if they did not exist.
+func (r *HoldMemory) Create(ctx context.Context, userID, bookID int) (models.Hold, error) {
+	var hold models.Hold
+	err := r.store.do(ctx, func(d *memoryData) error {
+		if d.books[bookID].DeletedAt != nil || d.users[userID].DeletedAt != nil {
+			return ErrForeignKey
+		}
+		if hasRow(d.holds, func(hold models.Hold) bool {
+			return hold.UserID == userID && hold.BookID == bookID && slices.Contains(activeHoldStatuses, hold.Status)
+		}) {
+			return ErrHoldExists
+		}
+		if hasRow(d.loans, func(loan models.RentedBook) bool {
+			return loan.UserID == userID && loan.BookID == bookID && loan.ReturnedAt == nil
+		}) {
+			return ErrAlreadyRenting
+		}
+		if len(d.freeCopies(bookID, 0)) > 0 {
+			return ErrBookAvailable
+		}
+		if _, ok := d.users[userID]; !ok {
+			return ErrForeignKey
+		}
+		if _, ok := d.books[bookID]; !ok {
+			return ErrForeignKey
+		}
+
+		hold = models.Hold{
+			ID:        d.nextID("holds"),
+			UserID:    userID,
+			BookID:    bookID,
+			Status:    models.HoldWaiting,
+			CreatedAt: time.Now(),
+		}
+		d.holds[hold.ID] = hold
+		return nil
+	})
+	return hold, err
+}
+
+func (r *HoldMemory) GetByID(ctx context.Context, id int) (models.Hold, error) {
+	var hold models.Hold
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if hold, ok = d.holds[id]; !ok {
+			return ErrNotFound
+		}
+		hold.User = d.users[hold.UserID]
+		hold.Book = d.plainBook(hold.BookID)
+		return nil
+	})
+	return hold, err
+}
+
+func (r *HoldMemory) GetByBook(ctx context.Context, bookID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.store.do(ctx, func(d *memoryData) error {
+		holds = d.activeHolds(func(hold models.Hold) bool { return hold.BookID == bookID })
+		for i := range holds {
+			holds[i].User = d.users[holds[i].UserID]
+		}
+		return nil
+	})
+	return holds, err
+}
+
+func (r *HoldMemory) GetByUser(ctx context.Context, userID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.store.do(ctx, func(d *memoryData) error {
+		holds = d.activeHolds(func(hold models.Hold) bool { return hold.UserID == userID })
+		for i := range holds {
+			holds[i].Book = d.plainBook(holds[i].BookID)
+		}
+		return nil
+	})
+	return holds, err
+}
+
+func (r *HoldMemory) Cancel(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		hold, ok := d.holds[id]
+		if !ok {
+			return ErrNotFound
+		}
+		if !slices.Contains(activeHoldStatuses, hold.Status) {
+			return ErrHoldNotActive
+		}
+		now := time.Now()
+		hold.Status = models.HoldCancelled
+		hold.ClosedAt = &now
+		d.holds[id] = hold
+		return nil
+	})
+}
+
+// ProcessQueue brings the hold queue of a book up to date: it expires the
+// ready holds whose pickup window has passed and sets the free copies aside
+// for the patrons waiting longest.
+func (r *HoldMemory) ProcessQueue(ctx context.Context, bookID int, pickupWindow time.Duration) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		now := time.Now()
+		for _, hold := range d.holds {
+			if hold.BookID == bookID && hold.Status == models.HoldReady && hold.ExpiresAt != nil && !hold.ExpiresAt.After(now) {
+				hold.Status = models.HoldExpired
+				hold.ClosedAt = &now
+				d.holds[hold.ID] = hold
+			}
+		}
+
+		waiting := d.queued(func(hold models.Hold) bool { return hold.BookID == bookID && hold.Status == models.HoldWaiting })
+		free := d.freeCopies(bookID, 0)
+		for i := 0; i < len(waiting) && i < len(free); i++ {
+			hold := waiting[i]
+			expiresAt := now.Add(pickupWindow)
+			hold.Status = models.HoldReady
+			hold.CopyID = &free[i].ID
+			hold.ReadyAt = &now
+			hold.ExpiresAt = &expiresAt
+			d.holds[hold.ID] = hold
+		}
+		return nil
+	})
+}
+
+// activeHolds returns the waiting and ready holds that match, in queue
+// order.
+func (d *memoryData) activeHolds(match func(models.Hold) bool) []models.Hold {
+	return d.queued(func(hold models.Hold) bool {
+		return slices.Contains(activeHoldStatuses, hold.Status) && match(hold)
+	})
+}
+
+// queued returns the holds that match, in the order they were placed.
+func (d *memoryData) queued(match func(models.Hold) bool) []models.Hold {
+	holds := sortedRows(d.holds, match)
+	slices.SortStableFunc(holds, func(a, b models.Hold) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return holds
+}
+
+// plainBook returns a book without any of its associations.
+func (d *memoryData) plainBook(id int) models.Book {
+	book := d.books[id]
+	return models.Book{ID: book.ID, Title: book.Title, PublishedAt: book.PublishedAt, ISBN: book.ISBN, DeletedAt: book.DeletedAt}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"library/models"
+	"time"
+)
+
+var activeHoldStatuses = []string{models.HoldWaiting, models.HoldReady}
+
+type HoldPostgres struct {
+	db *gorm.DB
+}
+
+func NewHoldPostgres(db *gorm.DB) *HoldPostgres {
+	return &HoldPostgres{db: db}
+}
+
+// Create puts a book on hold for a user. Archived books and users cannot be
+// held for, as if they did not exist.
+func (r *HoldPostgres) Create(ctx context.Context, userID, bookID int) (models.Hold, error) {
+	db := conn(ctx, r.db)
+	var count int64
+	if err := db.Model(&models.Book{}).Where("id = ? AND deleted_at IS NOT NULL", bookID).Count(&count).Error; err != nil {
+		return models.Hold{}, err
+	}
+	if count == 0 {
+		err := db.Model(&models.User{}).Where("id = ? AND deleted_at IS NOT NULL", userID).Count(&count).Error
+		if err != nil {
+			return models.Hold{}, err
+		}
+	}
+	if count > 0 {
+		return models.Hold{}, ErrForeignKey
+	}
+
+	if err := db.Model(&models.Hold{}).
+		Where("user_id = ? AND book_id = ? AND status IN ?", userID, bookID, activeHoldStatuses).
+		Count(&count).Error; err != nil {
+		return models.Hold{}, err
+	}
+	if count > 0 {
+		return models.Hold{}, ErrHoldExists
+	}
+
+	if err := db.Model(&models.RentedBook{}).
+		Where("user_id = ? AND book_id = ? AND returned_at IS NULL", userID, bookID).
+		Count(&count).Error; err != nil {
+		return models.Hold{}, err
+	}
+	if count > 0 {
+		return models.Hold{}, ErrAlreadyRenting
+	}
+
+	if err := freeCopies(db, 0).Where("book_copies.book_id = ?", bookID).Count(&count).Error; err != nil {
+		return models.Hold{}, err
+	}
+	if count > 0 {
+		return models.Hold{}, ErrBookAvailable
+	}
+
+	hold := models.Hold{
+		UserID: userID,
+		BookID: bookID,
+		Status: models.HoldWaiting,
+	}
+	err := db.Create(&hold).Error
+	return hold, err
+}
+
+func (r *HoldPostgres) GetByID(ctx context.Context, id int) (models.Hold, error) {
+	var hold models.Hold
+	err := conn(ctx, r.db).Preload("User").Preload("Book").First(&hold, id).Error
+	return hold, err
+}
+
+func (r *HoldPostgres) GetByBook(ctx context.Context, bookID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := conn(ctx, r.db).Preload("User").
+		Where("book_id = ? AND status IN ?", bookID, activeHoldStatuses).
+		Order("created_at, id").
+		Find(&holds).Error
+	return holds, err
+}
+
+func (r *HoldPostgres) GetByUser(ctx context.Context, userID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := conn(ctx, r.db).Preload("Book").
+		Where("user_id = ? AND status IN ?", userID, activeHoldStatuses).
+		Order("created_at, id").
+		Find(&holds).Error
+	return holds, err
+}
+
+func (r *HoldPostgres) Cancel(ctx context.Context, id int) error {
+	db := conn(ctx, r.db)
+	res := db.Model(&models.Hold{}).
+		Where("id = ? AND status IN ?", id, activeHoldStatuses).
+		Updates(map[string]interface{}{"status": models.HoldCancelled, "closed_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if err := db.First(&models.Hold{}, id).Error; err != nil {
+			return err
+		}
+		return ErrHoldNotActive
+	}
+	return nil
+}
+
+// ProcessQueue brings the hold queue of a book up to date: it expires the
+// ready holds whose pickup window has passed and sets the free copies aside
+// for the patrons waiting longest. The waiting holds are locked first, so
+// two calls for the same book hand out each copy once.
+func (r *HoldPostgres) ProcessQueue(ctx context.Context, bookID int, pickupWindow time.Duration) error {
+	db := conn(ctx, r.db)
+	now := time.Now()
+	if err := db.Model(&models.Hold{}).
+		Where("book_id = ? AND status = ? AND expires_at <= ?", bookID, models.HoldReady, now).
+		Updates(map[string]interface{}{"status": models.HoldExpired, "closed_at": now}).Error; err != nil {
+		return err
+	}
+
+	var waiting []models.Hold
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("book_id = ? AND status = ?", bookID, models.HoldWaiting).
+		Order("created_at, id").
+		Find(&waiting).Error; err != nil {
+		return err
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	var free []models.BookCopy
+	if err := freeCopies(db, 0).Where("book_copies.book_id = ?", bookID).
+		Order("book_copies.id").
+		Limit(len(waiting)).
+		Find(&free).Error; err != nil {
+		return err
+	}
+
+	for i, bookCopy := range free {
+		if err := db.Model(&waiting[i]).Updates(map[string]interface{}{
+			"status":     models.HoldReady,
+			"copy_id":    bookCopy.ID,
+			"ready_at":   now,
+			"expires_at": now.Add(pickupWindow),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
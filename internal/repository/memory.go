@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"library/models"
+)
+
+// memoryStore holds the tables of the in-memory repositories. Rows are kept
+// without their associations, which the repositories fill in as the
+// Postgres ones preload them, and are replaced rather than changed in place
+// so that a snapshot of the tables stays valid.
+type memoryStore struct {
+	mu   sync.Mutex
+	data memoryData
+}
+
+type memoryData struct {
+	lastIDs map[string]int
+
+	apiKeys map[int]models.APIKey
+	authors map[int]models.Author
+	// books keep their contributors without Author and Book, and their
+	// subjects and tags by ID only
+	books    map[int]models.Book
+	copies   map[int]models.BookCopy
+	fines    map[int]models.Fine
+	holds    map[int]models.Hold
+	loans    map[int]models.RentedBook
+	payments map[int]models.Payment
+	policies map[int]models.LoanPolicy
+	subjects map[int]models.Subject
+	tags     map[int]models.Tag
+	users    map[int]models.User
+}
+
+// memoryTxKey is the context key marking that the store in its value is
+// already held by WithinTransaction.
+type memoryTxKey struct{}
+
+// NewMemoryRepository returns repositories that keep everything in memory,
+// for tests and for running the server without a database. They are safe
+// for concurrent use and report the same errors as the Postgres ones. The
+// catalogue search only matches word prefixes and ISBNs, without the typo
+// tolerance of Postgres.
+func NewMemoryRepository() *Repository {
+	store := &memoryStore{data: memoryData{
+		lastIDs:  map[string]int{},
+		apiKeys:  map[int]models.APIKey{},
+		authors:  map[int]models.Author{},
+		books:    map[int]models.Book{},
+		copies:   map[int]models.BookCopy{},
+		fines:    map[int]models.Fine{},
+		holds:    map[int]models.Hold{},
+		loans:    map[int]models.RentedBook{},
+		payments: map[int]models.Payment{},
+		policies: map[int]models.LoanPolicy{},
+		subjects: map[int]models.Subject{},
+		tags:     map[int]models.Tag{},
+		users:    map[int]models.User{},
+	}}
+
+	return &Repository{
+		APIKeys:      &APIKeyMemory{store: store},
+		Authors:      &AuthorMemory{store: store},
+		Books:        &BookMemory{store: store},
+		Copies:       &CopyMemory{store: store},
+		Fines:        &FineMemory{store: store},
+		Holds:        &HoldMemory{store: store},
+		LoanPolicies: &LoanPolicyMemory{store: store},
+		Subjects:     &SubjectMemory{store: store},
+		Tags:         &TagMemory{store: store},
+		Transactor:   &TransactorMemory{store: store},
+		Users:        &UserMemory{store: store},
+	}
+}
+
+// do runs fn with the tables, holding the store unless ctx is inside a
+// transaction on it, which holds it already. Like a query, it fails once
+// ctx is done.
+func (s *memoryStore) do(ctx context.Context, fn func(d *memoryData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Value(memoryTxKey{}) != s {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return fn(&s.data)
+}
+
+// clone copies the tables. The rows themselves are shared, see memoryStore.
+func (d *memoryData) clone() memoryData {
+	return memoryData{
+		lastIDs:  maps.Clone(d.lastIDs),
+		apiKeys:  maps.Clone(d.apiKeys),
+		authors:  maps.Clone(d.authors),
+		books:    maps.Clone(d.books),
+		copies:   maps.Clone(d.copies),
+		fines:    maps.Clone(d.fines),
+		holds:    maps.Clone(d.holds),
+		loans:    maps.Clone(d.loans),
+		payments: maps.Clone(d.payments),
+		policies: maps.Clone(d.policies),
+		subjects: maps.Clone(d.subjects),
+		tags:     maps.Clone(d.tags),
+		users:    maps.Clone(d.users),
+	}
+}
+
+// nextID is the ID of the next row of table, counting from 1 as a serial
+// column does.
+func (d *memoryData) nextID(table string) int {
+	d.lastIDs[table]++
+	return d.lastIDs[table]
+}
+
+// TransactorMemory runs a function with the store held throughout and puts
+// the tables back as they were when it fails or panics. Nested calls do the
+// same for the work of the inner function only, like savepoints.
+type TransactorMemory struct {
+	store *memoryStore
+}
+
+func (r *TransactorMemory) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	s := r.store
+	if ctx.Value(memoryTxKey{}) != s {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ctx = context.WithValue(ctx, memoryTxKey{}, s)
+	}
+
+	saved := s.data.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			s.data = saved
+			panic(p)
+		}
+		if err != nil {
+			s.data = saved
+		}
+	}()
+	return fn(ctx)
+}
+
+// checkIdle is checkIdle of the Postgres repositories: it reports
+// ErrOpenLoans or ErrActiveHolds when a loan or a hold of a user and book
+// that belong to what is archived is still open.
+func (d *memoryData) checkIdle(belongs func(userID, bookID int) bool) error {
+	if hasRow(d.loans, func(loan models.RentedBook) bool {
+		return loan.ReturnedAt == nil && belongs(loan.UserID, loan.BookID)
+	}) {
+		return ErrOpenLoans
+	}
+	if hasRow(d.holds, func(hold models.Hold) bool {
+		return slices.Contains(activeHoldStatuses, hold.Status) && belongs(hold.UserID, hold.BookID)
+	}) {
+		return ErrActiveHolds
+	}
+	return nil
+}
+
+// sortedRows returns the rows of a table that match, in ID order.
+func sortedRows[T any](rows map[int]T, match func(T) bool) []T {
+	ids := make([]int, 0, len(rows))
+	for id, row := range rows {
+		if match == nil || match(row) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	result := make([]T, len(ids))
+	for i, id := range ids {
+		result[i] = rows[id]
+	}
+	return result
+}
+
+// sortBy orders rows by a sort key such as "-name" as sortOrder does, given
+// how to compare rows by each field; IDs break ties. rows must be in ID
+// order already.
+func sortBy[T any](rows []T, sort string, fields map[string]func(a, b T) int) {
+	desc := strings.HasPrefix(sort, "-")
+	if desc {
+		slices.Reverse(rows)
+	}
+	compare := fields[strings.TrimPrefix(sort, "-")]
+	if compare == nil {
+		return
+	}
+	slices.SortStableFunc(rows, func(a, b T) int {
+		if desc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
+
+// paged returns the 1-based page of pageSize rows, as paginate selects it.
+func paged[T any](rows []T, page, pageSize int) []T {
+	start := (page - 1) * pageSize
+	if start < 0 || start >= len(rows) {
+		return nil
+	}
+	return rows[start:min(start+pageSize, len(rows))]
+}
+
+// containsFold is ILIKE with containsPattern: whether s contains substr,
+// ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"library/models"
+)
+
+func TestTransactorMemory_WithinTransaction(t *testing.T) {
+	repos := NewMemoryRepository()
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	authors := func() int64 {
+		_, total, err := repos.Authors.GetAll(ctx, models.AuthorQuery{Page: 1, PageSize: 1})
+		require.NoError(t, err)
+		return total
+	}
+
+	assert.Panics(t, func() {
+		_ = repos.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: "Panicked"}))
+			panic("failure")
+		})
+	})
+	assert.Zero(t, authors())
+
+	err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+		require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: "Outer"}))
+		err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: "Inner"}))
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		return nil
+	})
+	require.NoError(t, err)
+
+	got, _, err := repos.Authors.GetAll(ctx, models.AuthorQuery{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Outer", got[0].Name)
+}
+
+func TestMemoryStore_CanceledContext(t *testing.T) {
+	repos := NewMemoryRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repos.Books.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds the migrations of each database in a directory
+// named after its dialect, such as migrations/postgres.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationLockID is the advisory lock key that keeps migrators started at
+// the same time, e.g. by several instances of the app, from racing.
+const migrationLockID = 7_140_213
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change, read from a pair of
+// NNNN_name.up.sql and NNNN_name.down.sql files.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus tells whether a migration has been applied, and when.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the embedded migrations for the database
+// it is given, recording the applied versions in the schema_migrations
+// table.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", db.Dialector.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations reads the migrations in dir, sorted by version.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: expected a NNNN_name.up.sql or NNNN_name.down.sql file name", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		body, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the ones applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.run(migration, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and
+// returns the ones rolled back.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := m.run(migration, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status lists every known migration with the time it was applied, if it was.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// applied returns the applied versions with the time they were applied,
+// creating the schema_migrations table on first use.
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	// the SQLite driver only reads times back from columns declared as one
+	timestamp := "timestamptz"
+	if isSQLite(m.db) {
+		timestamp = "datetime"
+	}
+	err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations
+	(
+		version    bigint PRIMARY KEY,
+		name       text        NOT NULL,
+		applied_at ` + timestamp + ` NOT NULL
+	)`).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Version   int
+		AppliedAt time.Time
+	}
+	if err := m.db.Table("schema_migrations").Select("version, applied_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// run applies or rolls back a single migration in its own transaction. The
+// version is checked again under the lock in case another migrator got
+// there first. SQLite takes no lock of its own: of two migrators writing at
+// the same time, one fails as the database is busy.
+func (m *Migrator) run(migration Migration, up bool) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if !isSQLite(tx) {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+		}
+
+		var count int64
+		if err := tx.Table("schema_migrations").Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if (count > 0) == up {
+			return nil
+		}
+
+		script := migration.Down
+		if up {
+			script = migration.Up
+		}
+		if err := tx.Exec(script).Error; err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if up {
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				migration.Version, migration.Name, time.Now()).Error
+		}
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+	})
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"library/models"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	postgres, err := loadMigrations(migrationFiles, "migrations/postgres")
+	require.NoError(t, err)
+	sqlite, err := loadMigrations(migrationFiles, "migrations/sqlite")
+	require.NoError(t, err)
+	require.NotEmpty(t, postgres)
+	require.NotEmpty(t, sqlite)
+
+	for _, migrations := range [][]Migration{postgres, sqlite} {
+		first := migrations[0].Version
+		for i, migration := range migrations {
+			assert.Equal(t, first+i, migration.Version, "migration versions must be consecutive")
+			assert.NotEmpty(t, migration.Up)
+			assert.NotEmpty(t, migration.Down)
+		}
+	}
+	assert.Equal(t, 1, postgres[0].Version)
+	assert.Equal(t, postgres[len(postgres)-1].Version, sqlite[len(sqlite)-1].Version, "both databases must be at the same version")
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"m/0002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"m/0001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"m/0001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+	}
+
+	migrations, err := loadMigrations(fsys, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE b ();", Down: "DROP TABLE b;"},
+	}, migrations)
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		err  string
+	}{
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"m/0001_first.up.sql": {Data: []byte("SELECT 1;")}},
+			err:  "migration 0001_first needs both an up and a down script",
+		},
+		{
+			name: "bad name",
+			fsys: fstest.MapFS{"m/first.sql": {Data: []byte("SELECT 1;")}},
+			err:  "migration first.sql: expected a NNNN_name.up.sql or NNNN_name.down.sql file name",
+		},
+		{
+			name: "two names",
+			fsys: fstest.MapFS{
+				"m/0001_first.up.sql":   {Data: []byte("SELECT 1;")},
+				"m/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "migration 1 has two names: first and other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.fsys, "m")
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	testMigratorUpDown(t, testDB(t))
+}
+
+func TestMigrator_UpDownSQLite(t *testing.T) {
+	testMigratorUpDown(t, testSQLiteDB(t))
+}
+
+// testMigratorUpDown checks that db is fully migrated and that its newest
+// migration can be rolled back and applied again.
+func testMigratorUpDown(t *testing.T, db *gorm.DB) {
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, "migration %d should be applied", status.Version)
+	}
+
+	// roll the newest migration back and forth to check its down script
+	rolledBack, err := migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 1)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Equal(t, rolledBack, applied)
+}
+
+func TestMigrator_NormalizeISBNsSQLite(t *testing.T) {
+	db := testSQLiteDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	// go back to before the normalization and store ISBNs as they used to be
+	rollBackTo(t, migrator, "normalize_isbns")
+
+	tests := []struct {
+		isbn string
+		want string
+	}{
+		{isbn: "0-306-40615-2", want: "9780306406157"},
+		{isbn: "978-0-19-852663-6", want: "9780198526636"},
+		{isbn: "080442957x", want: "9780804429573"},
+		{isbn: "978 3 16 148410 0", want: "9783161484100"},
+		{isbn: "9786021234563", want: "9786021234563"},
+		// invalid ISBNs are left for a librarian to fix
+		{isbn: "0-306-40615-3", want: "0-306-40615-3"},
+		{isbn: "978-0-306-4061X-7", want: "978-0-306-4061X-7"},
+		{isbn: "legacy-1", want: "legacy-1"},
+		// as are two forms of one ISBN, or one already stored
+		{isbn: "1-4028-9462-7", want: "1-4028-9462-7"},
+		{isbn: "978-1-4028-9462-6", want: "978-1-4028-9462-6"},
+		{isbn: "9791090636071", want: "9791090636071"},
+		{isbn: "979-10-90636-07-1", want: "979-10-90636-07-1"},
+	}
+	ids := make([]int, len(tests))
+	for i, tt := range tests {
+		book := models.Book{Title: tt.isbn, PublishedAt: time.Now(), ISBN: tt.isbn}
+		require.NoError(t, db.Create(&book).Error)
+		ids[i] = book.ID
+	}
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	for i, tt := range tests {
+		var book models.Book
+		require.NoError(t, db.First(&book, ids[i]).Error)
+		assert.Equal(t, tt.want, book.ISBN, tt.isbn)
+	}
+}
+
+func TestMigrator_UserEmailCaseSQLite(t *testing.T) {
+	db := testSQLiteDB(t)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	rollBackTo(t, migrator, "user_email_case")
+
+	ann := models.User{Name: "Ann", Email: "Ann@Example.com"}
+	require.NoError(t, db.Create(&ann).Error)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	require.NoError(t, db.First(&ann, ann.ID).Error)
+	assert.Equal(t, "ann@example.com", ann.Email)
+	assert.ErrorIs(t, db.Create(&models.User{Name: "Ann", Email: "ANN@example.com"}).Error, gorm.ErrDuplicatedKey)
+
+	// two users that differ only in case have to be told apart by hand
+	rollBackTo(t, migrator, "user_email_case")
+	require.NoError(t, db.Create(&models.User{Name: "Bob", Email: "bob@example.com"}).Error)
+	require.NoError(t, db.Create(&models.User{Name: "Bob", Email: "Bob@Example.com"}).Error)
+	_, err = migrator.Up()
+	assert.ErrorContains(t, err, "migration 0014_user_email_case")
+}
+
+// rollBackTo rolls back the newest migrations up to and including the one
+// named name.
+func rollBackTo(t *testing.T, migrator *Migrator, name string) {
+	for {
+		rolledBack, err := migrator.Down(1)
+		require.NoError(t, err)
+		require.Len(t, rolledBack, 1, "migration %s is not applied", name)
+		if rolledBack[0].Name == name {
+			return
+		}
+	}
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS rented_books;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS authors;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS authors
+(
+    id   bigserial PRIMARY KEY,
+    name text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS books
+(
+    id           bigserial PRIMARY KEY,
+    title        text        NOT NULL,
+    author_id    bigint      NOT NULL REFERENCES authors (id),
+    published_at timestamptz NOT NULL,
+    isbn         text        NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS users
+(
+    id    bigserial PRIMARY KEY,
+    name  text NOT NULL,
+    email text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS rented_books
+(
+    id          bigserial PRIMARY KEY,
+    user_id     bigint      NOT NULL REFERENCES users (id),
+    book_id     bigint      NOT NULL REFERENCES books (id),
+    rented_at   timestamptz NOT NULL,
+    returned_at timestamptz
+);
@@ -0,0 +1,7 @@
+ALTER TABLE users DROP COLUMN IF EXISTS loan_policy_id;
+
+DROP TABLE IF EXISTS loan_policies;
+
+ALTER TABLE rented_books
+    DROP COLUMN IF EXISTS renewal_count,
+    DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,18 @@
+ALTER TABLE rented_books
+    ADD COLUMN IF NOT EXISTS due_at        timestamptz,
+    ADD COLUMN IF NOT EXISTS renewal_count bigint NOT NULL DEFAULT 0;
+
+-- loans recorded before due dates existed get the default loan period
+UPDATE rented_books SET due_at = rented_at + interval '14 days' WHERE due_at IS NULL;
+ALTER TABLE rented_books ALTER COLUMN due_at SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS loan_policies
+(
+    id           bigserial PRIMARY KEY,
+    name         text   NOT NULL UNIQUE,
+    max_loans    bigint NOT NULL,
+    loan_days    bigint NOT NULL,
+    max_renewals bigint NOT NULL
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS loan_policy_id bigint REFERENCES loan_policies (id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_rented_books_open_copy;
+
+ALTER TABLE rented_books DROP COLUMN IF EXISTS copy_id;
+
+DROP TABLE IF EXISTS book_copies;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS book_copies
+(
+    id             bigserial PRIMARY KEY,
+    book_id        bigint NOT NULL REFERENCES books (id),
+    barcode        text   NOT NULL UNIQUE,
+    shelf_location text,
+    condition      text   NOT NULL DEFAULT 'good'
+);
+
+-- every book recorded before copies existed gets a single copy, and its
+-- loans are pointed at that copy
+INSERT INTO book_copies (book_id, barcode, condition)
+SELECT id, LPAD(id::text, 7, '0') || '01', 'good'
+FROM books
+WHERE NOT EXISTS (SELECT 1 FROM book_copies WHERE book_copies.book_id = books.id);
+
+ALTER TABLE rented_books ADD COLUMN IF NOT EXISTS copy_id bigint REFERENCES book_copies (id);
+
+UPDATE rented_books
+SET copy_id = (SELECT MIN(book_copies.id) FROM book_copies WHERE book_copies.book_id = rented_books.book_id)
+WHERE copy_id IS NULL;
+
+ALTER TABLE rented_books ALTER COLUMN copy_id SET NOT NULL;
+
+-- a copy can only be in one open loan at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rented_books_open_copy ON rented_books (copy_id) WHERE returned_at IS NULL;
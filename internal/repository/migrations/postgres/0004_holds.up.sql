@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS holds
+(
+    id         bigserial PRIMARY KEY,
+    user_id    bigint      NOT NULL REFERENCES users (id),
+    book_id    bigint      NOT NULL REFERENCES books (id),
+    copy_id    bigint REFERENCES book_copies (id),
+    status     text        NOT NULL,
+    created_at timestamptz NOT NULL,
+    ready_at   timestamptz,
+    expires_at timestamptz,
+    closed_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds (book_id, status, created_at);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS fines
+(
+    id             bigserial PRIMARY KEY,
+    user_id        bigint      NOT NULL REFERENCES users (id),
+    rented_book_id bigint      NOT NULL UNIQUE REFERENCES rented_books (id),
+    days_late      bigint      NOT NULL,
+    amount         bigint      NOT NULL,
+    created_at     timestamptz NOT NULL,
+    waived_at      timestamptz,
+    waive_reason   text
+);
+
+CREATE TABLE IF NOT EXISTS payments
+(
+    id         bigserial PRIMARY KEY,
+    user_id    bigint      NOT NULL REFERENCES users (id),
+    amount     bigint      NOT NULL,
+    note       text,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fines_user ON fines (user_id);
+CREATE INDEX IF NOT EXISTS idx_payments_user ON payments (user_id);
@@ -0,0 +1,13 @@
+-- pg_trgm stays installed, other schemas in the database may rely on it.
+DROP INDEX IF EXISTS idx_books_isbn_digits;
+DROP INDEX IF EXISTS idx_authors_name_trgm;
+DROP INDEX IF EXISTS idx_books_title_trgm;
+DROP INDEX IF EXISTS idx_books_search_document;
+
+DROP TRIGGER IF EXISTS authors_search_document ON authors;
+DROP TRIGGER IF EXISTS books_search_document ON books;
+DROP FUNCTION IF EXISTS authors_refresh_search_document();
+DROP FUNCTION IF EXISTS books_refresh_search_document();
+DROP FUNCTION IF EXISTS book_search_document(text, text, bigint);
+
+ALTER TABLE books DROP COLUMN IF EXISTS search_document;
@@ -0,0 +1,64 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- search_document holds the words of a book's title, its author's name and
+-- its ISBN without hyphens, weighted in that order. It is kept up to date by
+-- the triggers below so that no code path writing books or authors has to.
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS search_document tsvector;
+
+CREATE OR REPLACE FUNCTION book_search_document(book_title text, book_isbn text, book_author_id bigint)
+    RETURNS tsvector
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT setweight(to_tsvector('simple', book_title), 'A') ||
+       setweight(to_tsvector('simple', coalesce((SELECT name FROM authors WHERE id = book_author_id), '')), 'B') ||
+       setweight(to_tsvector('simple', replace(book_isbn, '-', '')), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION books_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    NEW.search_document := book_search_document(NEW.title, NEW.isbn, NEW.author_id);
+    RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION authors_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    UPDATE books
+    SET search_document = book_search_document(title, isbn, author_id)
+    WHERE author_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS books_search_document ON books;
+CREATE TRIGGER books_search_document
+    BEFORE INSERT OR UPDATE OF title, isbn, author_id
+    ON books
+    FOR EACH ROW
+EXECUTE FUNCTION books_refresh_search_document();
+
+DROP TRIGGER IF EXISTS authors_search_document ON authors;
+CREATE TRIGGER authors_search_document
+    AFTER UPDATE OF name
+    ON authors
+    FOR EACH ROW
+EXECUTE FUNCTION authors_refresh_search_document();
+
+UPDATE books
+SET search_document = book_search_document(title, isbn, author_id);
+
+CREATE INDEX IF NOT EXISTS idx_books_search_document ON books USING gin (search_document);
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_authors_name_trgm ON authors USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_isbn_digits ON books (replace(isbn, '-', ''));
@@ -0,0 +1,72 @@
+-- each book keeps only its first credited author, or failing that its first
+-- contributor of any role
+ALTER TABLE books ADD COLUMN IF NOT EXISTS author_id bigint REFERENCES authors (id);
+
+UPDATE books
+SET author_id = (SELECT author_id
+                 FROM book_contributors
+                 WHERE book_contributors.book_id = books.id
+                 ORDER BY role <> 'author', position
+                 LIMIT 1)
+WHERE author_id IS NULL;
+
+ALTER TABLE books ALTER COLUMN author_id SET NOT NULL;
+
+DROP TRIGGER IF EXISTS book_contributors_search_document ON book_contributors;
+DROP TRIGGER IF EXISTS authors_search_document ON authors;
+DROP TRIGGER IF EXISTS books_search_document ON books;
+DROP FUNCTION IF EXISTS book_contributors_refresh_search_document();
+DROP FUNCTION IF EXISTS book_search_document(bigint, text, text);
+
+DROP TABLE IF EXISTS book_contributors;
+
+-- back to the search document of 0006_book_search
+CREATE OR REPLACE FUNCTION book_search_document(book_title text, book_isbn text, book_author_id bigint)
+    RETURNS tsvector
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT setweight(to_tsvector('simple', book_title), 'A') ||
+       setweight(to_tsvector('simple', coalesce((SELECT name FROM authors WHERE id = book_author_id), '')), 'B') ||
+       setweight(to_tsvector('simple', replace(book_isbn, '-', '')), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION books_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    NEW.search_document := book_search_document(NEW.title, NEW.isbn, NEW.author_id);
+    RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION authors_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    UPDATE books
+    SET search_document = book_search_document(title, isbn, author_id)
+    WHERE author_id = NEW.id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER books_search_document
+    BEFORE INSERT OR UPDATE OF title, isbn, author_id
+    ON books
+    FOR EACH ROW
+EXECUTE FUNCTION books_refresh_search_document();
+
+CREATE TRIGGER authors_search_document
+    AFTER UPDATE OF name
+    ON authors
+    FOR EACH ROW
+EXECUTE FUNCTION authors_refresh_search_document();
+
+UPDATE books
+SET search_document = book_search_document(title, isbn, author_id);
@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS book_contributors
+(
+    book_id   bigint NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id bigint NOT NULL REFERENCES authors (id),
+    role      text   NOT NULL CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position  bigint NOT NULL,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_contributors_author ON book_contributors (author_id);
+
+-- the single author of every existing book becomes its first contributor
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1
+FROM books
+ON CONFLICT DO NOTHING;
+
+-- the search document now takes the names of all of a book's contributors,
+-- so it is refreshed when the credits of a book change as well
+DROP TRIGGER IF EXISTS authors_search_document ON authors;
+DROP TRIGGER IF EXISTS books_search_document ON books;
+DROP FUNCTION IF EXISTS book_search_document(text, text, bigint);
+
+ALTER TABLE books DROP COLUMN IF EXISTS author_id;
+
+CREATE OR REPLACE FUNCTION book_search_document(for_book bigint, book_title text, book_isbn text)
+    RETURNS tsvector
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT setweight(to_tsvector('simple', book_title), 'A') ||
+       setweight(to_tsvector('simple', coalesce((SELECT string_agg(authors.name, ' ')
+                                                 FROM book_contributors
+                                                          JOIN authors ON authors.id = book_contributors.author_id
+                                                 WHERE book_contributors.book_id = for_book), '')), 'B') ||
+       setweight(to_tsvector('simple', replace(book_isbn, '-', '')), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION books_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    NEW.search_document := book_search_document(NEW.id, NEW.title, NEW.isbn);
+    RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION authors_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    UPDATE books
+    SET search_document = book_search_document(id, title, isbn)
+    WHERE id IN (SELECT book_id FROM book_contributors WHERE author_id = NEW.id);
+    RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION book_contributors_refresh_search_document()
+    RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    UPDATE books
+    SET search_document = book_search_document(id, title, isbn)
+    WHERE id IN (OLD.book_id, NEW.book_id);
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER books_search_document
+    BEFORE INSERT OR UPDATE OF title, isbn
+    ON books
+    FOR EACH ROW
+EXECUTE FUNCTION books_refresh_search_document();
+
+CREATE TRIGGER authors_search_document
+    AFTER UPDATE OF name
+    ON authors
+    FOR EACH ROW
+EXECUTE FUNCTION authors_refresh_search_document();
+
+DROP TRIGGER IF EXISTS book_contributors_search_document ON book_contributors;
+CREATE TRIGGER book_contributors_search_document
+    AFTER INSERT OR UPDATE OR DELETE
+    ON book_contributors
+    FOR EACH ROW
+EXECUTE FUNCTION book_contributors_refresh_search_document();
+
+UPDATE books
+SET search_document = book_search_document(id, title, isbn);
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS subjects
+(
+    id        bigserial PRIMARY KEY,
+    name      text NOT NULL,
+    parent_id bigint REFERENCES subjects (id)
+);
+
+-- sibling subjects have distinct names; top-level subjects count as
+-- siblings of each other
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subjects_parent_name ON subjects (coalesce(parent_id, 0), lower(name));
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id   bigserial PRIMARY KEY,
+    name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS book_subjects
+(
+    book_id    bigint NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    subject_id bigint NOT NULL REFERENCES subjects (id),
+    PRIMARY KEY (book_id, subject_id)
+);
+
+CREATE TABLE IF NOT EXISTS book_tags
+(
+    book_id bigint NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    tag_id  bigint NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subjects_parent ON subjects (parent_id);
+CREATE INDEX IF NOT EXISTS idx_book_subjects_subject ON book_subjects (subject_id);
+CREATE INDEX IF NOT EXISTS idx_book_tags_tag ON book_tags (tag_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'patron'
+        CHECK (role IN ('patron', 'librarian', 'admin'));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id            bigserial PRIMARY KEY,
+    name          text        NOT NULL,
+    prefix        text        NOT NULL,
+    key_hash      text        NOT NULL UNIQUE,
+    -- JSON array of scopes
+    scopes        text        NOT NULL,
+    created_by_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    created_at    timestamptz NOT NULL,
+    expires_at    timestamptz,
+    revoked_at    timestamptz,
+    last_used_at  timestamptz
+);
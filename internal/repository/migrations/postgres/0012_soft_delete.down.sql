@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE books
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE authors
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,9 @@
+-- Deleting a book, author or user archives it by setting deleted_at. The row
+-- stays, and so do the loans, holds and fines that refer to it; archived
+-- rows keep their ISBN or email as well, so those stay taken.
+ALTER TABLE authors
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
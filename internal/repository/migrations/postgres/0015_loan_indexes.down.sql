@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_rented_books_book;
+DROP INDEX IF EXISTS idx_rented_books_user;
+DROP INDEX IF EXISTS idx_book_copies_book;
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS book_tags;
+DROP TABLE IF EXISTS book_subjects;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS subjects;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS fines;
+DROP TABLE IF EXISTS holds;
+DROP TABLE IF EXISTS rented_books;
+DROP TABLE IF EXISTS book_copies;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS loan_policies;
+DROP TABLE IF EXISTS book_contributors;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS authors;
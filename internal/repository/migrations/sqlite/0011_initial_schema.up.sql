@@ -0,0 +1,164 @@
+-- SQLite is supported from version 0011 of the Postgres schema on, so its
+-- first migration has that version and creates the schema as it was then.
+-- A later change to the schema comes with a migration of the same version
+-- for each database. There is no search document: searchSQLite matches
+-- books without one.
+
+CREATE TABLE IF NOT EXISTS authors
+(
+    id   integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS books
+(
+    id           integer PRIMARY KEY AUTOINCREMENT,
+    title        text     NOT NULL,
+    published_at datetime NOT NULL,
+    isbn         text     NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_isbn_digits ON books (replace(isbn, '-', ''));
+
+CREATE TABLE IF NOT EXISTS book_contributors
+(
+    book_id   integer NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id integer NOT NULL REFERENCES authors (id),
+    role      text    NOT NULL CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position  integer NOT NULL,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_contributors_author ON book_contributors (author_id);
+
+CREATE TABLE IF NOT EXISTS loan_policies
+(
+    id           integer PRIMARY KEY AUTOINCREMENT,
+    name         text    NOT NULL UNIQUE,
+    max_loans    integer NOT NULL,
+    loan_days    integer NOT NULL,
+    max_renewals integer NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users
+(
+    id             integer PRIMARY KEY AUTOINCREMENT,
+    name           text NOT NULL,
+    email          text NOT NULL UNIQUE,
+    loan_policy_id integer REFERENCES loan_policies (id),
+    password_hash  text NOT NULL DEFAULT '',
+    role           text NOT NULL DEFAULT 'patron' CHECK (role IN ('patron', 'librarian', 'admin'))
+);
+
+CREATE TABLE IF NOT EXISTS book_copies
+(
+    id             integer PRIMARY KEY AUTOINCREMENT,
+    book_id        integer NOT NULL REFERENCES books (id),
+    barcode        text    NOT NULL UNIQUE,
+    shelf_location text,
+    condition      text    NOT NULL DEFAULT 'good'
+);
+
+CREATE TABLE IF NOT EXISTS rented_books
+(
+    id            integer PRIMARY KEY AUTOINCREMENT,
+    user_id       integer  NOT NULL REFERENCES users (id),
+    book_id       integer  NOT NULL REFERENCES books (id),
+    copy_id       integer  NOT NULL REFERENCES book_copies (id),
+    rented_at     datetime NOT NULL,
+    due_at        datetime NOT NULL,
+    renewal_count integer  NOT NULL DEFAULT 0,
+    returned_at   datetime
+);
+
+-- a copy can only be in one open loan at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rented_books_open_copy ON rented_books (copy_id) WHERE returned_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS holds
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    user_id    integer  NOT NULL REFERENCES users (id),
+    book_id    integer  NOT NULL REFERENCES books (id),
+    copy_id    integer REFERENCES book_copies (id),
+    status     text     NOT NULL,
+    created_at datetime NOT NULL,
+    ready_at   datetime,
+    expires_at datetime,
+    closed_at  datetime
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds (book_id, status, created_at);
+
+CREATE TABLE IF NOT EXISTS fines
+(
+    id             integer PRIMARY KEY AUTOINCREMENT,
+    user_id        integer  NOT NULL REFERENCES users (id),
+    rented_book_id integer  NOT NULL UNIQUE REFERENCES rented_books (id),
+    days_late      integer  NOT NULL,
+    amount         integer  NOT NULL,
+    created_at     datetime NOT NULL,
+    waived_at      datetime,
+    waive_reason   text
+);
+
+CREATE TABLE IF NOT EXISTS payments
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    user_id    integer  NOT NULL REFERENCES users (id),
+    amount     integer  NOT NULL,
+    note       text,
+    created_at datetime NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_fines_user ON fines (user_id);
+CREATE INDEX IF NOT EXISTS idx_payments_user ON payments (user_id);
+
+CREATE TABLE IF NOT EXISTS subjects
+(
+    id        integer PRIMARY KEY AUTOINCREMENT,
+    name      text NOT NULL,
+    parent_id integer REFERENCES subjects (id)
+);
+
+-- sibling subjects have distinct names; top-level subjects count as
+-- siblings of each other
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subjects_parent_name ON subjects (coalesce(parent_id, 0), lower(name));
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id   integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS book_subjects
+(
+    book_id    integer NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    subject_id integer NOT NULL REFERENCES subjects (id),
+    PRIMARY KEY (book_id, subject_id)
+);
+
+CREATE TABLE IF NOT EXISTS book_tags
+(
+    book_id integer NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    tag_id  integer NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subjects_parent ON subjects (parent_id);
+CREATE INDEX IF NOT EXISTS idx_book_subjects_subject ON book_subjects (subject_id);
+CREATE INDEX IF NOT EXISTS idx_book_tags_tag ON book_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id            integer PRIMARY KEY AUTOINCREMENT,
+    name          text     NOT NULL,
+    prefix        text     NOT NULL,
+    key_hash      text     NOT NULL UNIQUE,
+    -- JSON array of scopes
+    scopes        text     NOT NULL,
+    created_by_id integer REFERENCES users (id) ON DELETE SET NULL,
+    created_at    datetime NOT NULL,
+    expires_at    datetime,
+    revoked_at    datetime,
+    last_used_at  datetime
+);
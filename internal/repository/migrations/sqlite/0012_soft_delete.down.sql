@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN deleted_at;
+ALTER TABLE books DROP COLUMN deleted_at;
+ALTER TABLE authors DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+-- Deleting a book, author or user archives it by setting deleted_at, as on
+-- Postgres.
+ALTER TABLE authors ADD COLUMN deleted_at datetime;
+ALTER TABLE books ADD COLUMN deleted_at datetime;
+ALTER TABLE users ADD COLUMN deleted_at datetime;
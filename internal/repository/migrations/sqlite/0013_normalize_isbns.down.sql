@@ -0,0 +1,3 @@
+-- The ISBNs as they were typed are not kept, and the normalized ones are as
+-- good to the app, so there is nothing to undo.
+SELECT 1;
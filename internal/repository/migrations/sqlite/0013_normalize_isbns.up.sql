@@ -0,0 +1,45 @@
+-- Books created before ISBNs were validated keep their ISBN as it was typed,
+-- hyphens, spaces and ISBN-10s included. Rewrite each valid one to the 13
+-- digits the app stores today. Invalid ISBNs are left alone, and so are the
+-- ISBNs that would become that of another book: a librarian has to sort
+-- those out. The check digits are summed only once the pattern matched, as
+-- the casts fail on anything but a digit.
+WITH cleaned AS (
+    SELECT id, upper(replace(replace(isbn, '-', ''), ' ', '')) AS isbn
+    FROM books
+),
+isbn13 AS (
+    SELECT id, isbn
+    FROM cleaned
+    WHERE CASE WHEN isbn GLOB '97[89][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]'
+          THEN (CAST(substr(isbn, 1, 1) AS integer) + 3 * CAST(substr(isbn, 2, 1) AS integer) + CAST(substr(isbn, 3, 1) AS integer) +
+                      3 * CAST(substr(isbn, 4, 1) AS integer) + CAST(substr(isbn, 5, 1) AS integer) + 3 * CAST(substr(isbn, 6, 1) AS integer) +
+                      CAST(substr(isbn, 7, 1) AS integer) + 3 * CAST(substr(isbn, 8, 1) AS integer) + CAST(substr(isbn, 9, 1) AS integer) +
+                      3 * CAST(substr(isbn, 10, 1) AS integer) + CAST(substr(isbn, 11, 1) AS integer) + 3 * CAST(substr(isbn, 12, 1) AS integer) +
+                      CAST(substr(isbn, 13, 1) AS integer)) % 10 = 0
+          ELSE false END
+    UNION ALL
+    -- the prefix 978 weighs 9 + 3 * 7 + 8 = 38 in the ISBN-13 check digit
+    SELECT id, '978' || substr(isbn, 1, 9) ||
+               ((10 - (38 + 3 * CAST(substr(isbn, 1, 1) AS integer) + CAST(substr(isbn, 2, 1) AS integer) + 3 * CAST(substr(isbn, 3, 1) AS integer) +
+                      CAST(substr(isbn, 4, 1) AS integer) + 3 * CAST(substr(isbn, 5, 1) AS integer) + CAST(substr(isbn, 6, 1) AS integer) +
+                      3 * CAST(substr(isbn, 7, 1) AS integer) + CAST(substr(isbn, 8, 1) AS integer) + 3 * CAST(substr(isbn, 9, 1) AS integer)) % 10) % 10)
+    FROM cleaned
+    WHERE CASE WHEN isbn GLOB '[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9X]'
+          THEN (10 * CAST(substr(isbn, 1, 1) AS integer) + 9 * CAST(substr(isbn, 2, 1) AS integer) + 8 * CAST(substr(isbn, 3, 1) AS integer) +
+                      7 * CAST(substr(isbn, 4, 1) AS integer) + 6 * CAST(substr(isbn, 5, 1) AS integer) + 5 * CAST(substr(isbn, 6, 1) AS integer) +
+                      4 * CAST(substr(isbn, 7, 1) AS integer) + 3 * CAST(substr(isbn, 8, 1) AS integer) + 2 * CAST(substr(isbn, 9, 1) AS integer) +
+                      CASE substr(isbn, 10, 1) WHEN 'X' THEN 10 ELSE CAST(substr(isbn, 10, 1) AS integer) END) % 11 = 0
+          ELSE false END
+),
+normalized AS (
+    SELECT id, isbn, count(*) OVER (PARTITION BY isbn) AS claims
+    FROM isbn13
+)
+UPDATE books
+SET isbn = normalized.isbn
+FROM normalized
+WHERE books.id = normalized.id
+  AND books.isbn <> normalized.isbn
+  AND normalized.claims = 1
+  AND NOT EXISTS (SELECT 1 FROM books other WHERE other.isbn = normalized.isbn);
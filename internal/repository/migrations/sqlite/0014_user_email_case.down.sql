@@ -0,0 +1,2 @@
+-- Emails lower-cased on the way up stay so.
+DROP INDEX IF EXISTS idx_users_email_lower;
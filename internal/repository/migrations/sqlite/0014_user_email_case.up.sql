@@ -0,0 +1,10 @@
+-- Emails are unique ignoring case, as users log in with any case. The app
+-- stores them lower-cased; lower-case the ones stored before, unless that
+-- makes two the same. The index then refuses such pairs: the migration
+-- fails on them, naming the email, until one of the users is given another.
+UPDATE users
+SET email = lower(email)
+WHERE email <> lower(email)
+  AND NOT EXISTS (SELECT 1 FROM users other WHERE other.id <> users.id AND lower(other.email) = lower(users.email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
@@ -0,0 +1,6 @@
+-- Foreign keys are not indexed on their own. The available-copies count of
+-- the book listing looks up the copies of each book, and the loan listings
+-- of a user or a book are filtered by one and sorted by rent date.
+CREATE INDEX IF NOT EXISTS idx_book_copies_book ON book_copies (book_id);
+CREATE INDEX IF NOT EXISTS idx_rented_books_user ON rented_books (user_id, rented_at);
+CREATE INDEX IF NOT EXISTS idx_rented_books_book ON rented_books (book_id, rented_at);
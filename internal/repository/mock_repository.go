@@ -5,12 +5,147 @@
 package repository
 
 import (
+	context "context"
 	models "library/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockAPIKeys is a mock of APIKeys interface.
+type MockAPIKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysMockRecorder
+}
+
+// MockAPIKeysMockRecorder is the mock recorder for MockAPIKeys.
+type MockAPIKeysMockRecorder struct {
+	mock *MockAPIKeys
+}
+
+// NewMockAPIKeys creates a new mock instance.
+func NewMockAPIKeys(ctrl *gomock.Controller) *MockAPIKeys {
+	mock := &MockAPIKeys{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeys) EXPECT() *MockAPIKeysMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKeys) Create(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeysMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeys)(nil).Create), ctx, key)
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKeys) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeysMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKeys)(nil).GetAll), ctx)
+}
+
+// GetByHash mocks base method.
+func (m *MockAPIKeys) GetByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, hash)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAPIKeysMockRecorder) GetByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAPIKeys)(nil).GetByHash), ctx, hash)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeys) Revoke(ctx context.Context, id int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeysMockRecorder) Revoke(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeys)(nil).Revoke), ctx, id, at)
+}
+
+// Touch mocks base method.
+func (m *MockAPIKeys) Touch(ctx context.Context, id int, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockAPIKeysMockRecorder) Touch(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockAPIKeys)(nil).Touch), ctx, id, at)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockTransactorMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockTransactor)(nil).WithinTransaction), ctx, fn)
+}
+
 // MockAuthors is a mock of Authors interface.
 type MockAuthors struct {
 	ctrl     *gomock.Controller
@@ -35,75 +170,105 @@ func (m *MockAuthors) EXPECT() *MockAuthorsMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockAuthors) Create(author models.Author) error {
+func (m *MockAuthors) Create(ctx context.Context, author models.Author) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", author)
+	ret := m.ctrl.Call(m, "Create", ctx, author)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockAuthorsMockRecorder) Create(author interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Create(ctx, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthors)(nil).Create), author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthors)(nil).Create), ctx, author)
 }
 
 // Delete mocks base method.
-func (m *MockAuthors) Delete(id int) error {
+func (m *MockAuthors) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAuthorsMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthors)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthors)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockAuthors) GetAll() ([]models.Author, error) {
+func (m *MockAuthors) GetAll(ctx context.Context, query models.AuthorQuery) ([]models.Author, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
 	ret0, _ := ret[0].([]models.Author)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuthorsMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthors)(nil).GetAll), ctx, query)
+}
+
+// GetBooks mocks base method.
+func (m *MockAuthors) GetBooks(ctx context.Context, id int) ([]models.BookContributor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooks", ctx, id)
+	ret0, _ := ret[0].([]models.BookContributor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockAuthorsMockRecorder) GetAll() *gomock.Call {
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockAuthorsMockRecorder) GetBooks(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthors)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockAuthors)(nil).GetBooks), ctx, id)
 }
 
 // GetByID mocks base method.
-func (m *MockAuthors) GetByID(id int) (models.Author, error) {
+func (m *MockAuthors) GetByID(ctx context.Context, id int) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockAuthorsMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthors)(nil).GetByID), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockAuthors) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockAuthorsMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthors)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAuthors)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockAuthors) Update(author models.Author) error {
+func (m *MockAuthors) Update(ctx context.Context, author models.Author) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", author)
+	ret := m.ctrl.Call(m, "Update", ctx, author)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAuthorsMockRecorder) Update(author interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Update(ctx, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthors)(nil).Update), author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthors)(nil).Update), ctx, author)
 }
 
 // MockBooks is a mock of Books interface.
@@ -129,197 +294,980 @@ func (m *MockBooks) EXPECT() *MockBooksMockRecorder {
 	return m.recorder
 }
 
+// CountOpenLoans mocks base method.
+func (m *MockBooks) CountOpenLoans(ctx context.Context, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenLoans", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenLoans indicates an expected call of CountOpenLoans.
+func (mr *MockBooksMockRecorder) CountOpenLoans(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenLoans", reflect.TypeOf((*MockBooks)(nil).CountOpenLoans), ctx, userID)
+}
+
 // Create mocks base method.
-func (m *MockBooks) Create(book models.Book) error {
+func (m *MockBooks) Create(ctx context.Context, book models.Book) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", book)
+	ret := m.ctrl.Call(m, "Create", ctx, book)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockBooksMockRecorder) Create(book interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) Create(ctx, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBooks)(nil).Create), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBooks)(nil).Create), ctx, book)
 }
 
 // Delete mocks base method.
-func (m *MockBooks) Delete(id int) error {
+func (m *MockBooks) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBooksMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBooks)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBooks)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockBooks) GetAll() ([]models.Book, error) {
+func (m *MockBooks) GetAll(ctx context.Context, query models.BookQuery) ([]models.Book, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
 	ret0, _ := ret[0].([]models.Book)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockBooksMockRecorder) GetAll() *gomock.Call {
+func (mr *MockBooksMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBooks)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBooks)(nil).GetAll), ctx, query)
 }
 
 // GetByID mocks base method.
-func (m *MockBooks) GetByID(id int) (models.Book, error) {
+func (m *MockBooks) GetByID(ctx context.Context, id int) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockBooksMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBooks)(nil).GetByID), ctx, id)
+}
+
+// GetLoans mocks base method.
+func (m *MockBooks) GetLoans(ctx context.Context, query models.LoanQuery) ([]models.RentedBook, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", ctx, query)
+	ret0, _ := ret[0].([]models.RentedBook)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockBooksMockRecorder) GetLoans(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockBooks)(nil).GetLoans), ctx, query)
+}
+
+// GetOverdue mocks base method.
+func (m *MockBooks) GetOverdue(ctx context.Context) ([]models.RentedBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdue", ctx)
+	ret0, _ := ret[0].([]models.RentedBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdue indicates an expected call of GetOverdue.
+func (mr *MockBooksMockRecorder) GetOverdue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdue", reflect.TypeOf((*MockBooks)(nil).GetOverdue), ctx)
+}
+
+// RenewBook mocks base method.
+func (m *MockBooks) RenewBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration, maxRenewals int) (models.RentedBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", ctx, userID, bookID, copyID, loanPeriod, maxRenewals)
+	ret0, _ := ret[0].(models.RentedBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockBooksMockRecorder) RenewBook(ctx, userID, bookID, copyID, loanPeriod, maxRenewals interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBooks)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockBooks)(nil).RenewBook), ctx, userID, bookID, copyID, loanPeriod, maxRenewals)
 }
 
 // RentBook mocks base method.
-func (m *MockBooks) RentBook(userID, bookID int) error {
+func (m *MockBooks) RentBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RentBook", userID, bookID)
+	ret := m.ctrl.Call(m, "RentBook", ctx, userID, bookID, copyID, loanPeriod)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RentBook indicates an expected call of RentBook.
-func (mr *MockBooksMockRecorder) RentBook(userID, bookID interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) RentBook(ctx, userID, bookID, copyID, loanPeriod interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RentBook", reflect.TypeOf((*MockBooks)(nil).RentBook), userID, bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RentBook", reflect.TypeOf((*MockBooks)(nil).RentBook), ctx, userID, bookID, copyID, loanPeriod)
 }
 
-// ReturnBook mocks base method.
-func (m *MockBooks) ReturnBook(userID, bookID int) error {
+// Restore mocks base method.
+func (m *MockBooks) Restore(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReturnBook", userID, bookID)
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Restore indicates an expected call of Restore.
+func (mr *MockBooksMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBooks)(nil).Restore), ctx, id)
+}
+
+// ReturnBook mocks base method.
+func (m *MockBooks) ReturnBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnBook", ctx, userID, bookID, copyID)
+	ret0, _ := ret[0].(models.RentedBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // ReturnBook indicates an expected call of ReturnBook.
-func (mr *MockBooksMockRecorder) ReturnBook(userID, bookID interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) ReturnBook(ctx, userID, bookID, copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockBooks)(nil).ReturnBook), ctx, userID, bookID, copyID)
+}
+
+// Search mocks base method.
+func (m *MockBooks) Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]models.SearchHit)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockBooksMockRecorder) Search(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockBooks)(nil).ReturnBook), userID, bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockBooks)(nil).Search), ctx, query)
 }
 
 // Update mocks base method.
-func (m *MockBooks) Update(book models.Book) error {
+func (m *MockBooks) Update(ctx context.Context, book models.Book) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", book)
+	ret := m.ctrl.Call(m, "Update", ctx, book)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBooksMockRecorder) Update(book interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) Update(ctx, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBooks)(nil).Update), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBooks)(nil).Update), ctx, book)
 }
 
-// MockUsers is a mock of Users interface.
-type MockUsers struct {
+// MockCopies is a mock of Copies interface.
+type MockCopies struct {
 	ctrl     *gomock.Controller
-	recorder *MockUsersMockRecorder
+	recorder *MockCopiesMockRecorder
 }
 
-// MockUsersMockRecorder is the mock recorder for MockUsers.
-type MockUsersMockRecorder struct {
-	mock *MockUsers
+// MockCopiesMockRecorder is the mock recorder for MockCopies.
+type MockCopiesMockRecorder struct {
+	mock *MockCopies
 }
 
-// NewMockUsers creates a new mock instance.
-func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
-	mock := &MockUsers{ctrl: ctrl}
-	mock.recorder = &MockUsersMockRecorder{mock}
+// NewMockCopies creates a new mock instance.
+func NewMockCopies(ctrl *gomock.Controller) *MockCopies {
+	mock := &MockCopies{ctrl: ctrl}
+	mock.recorder = &MockCopiesMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+func (m *MockCopies) EXPECT() *MockCopiesMockRecorder {
 	return m.recorder
 }
 
 // Create mocks base method.
-func (m *MockUsers) Create(user models.User) error {
+func (m *MockCopies) Create(ctx context.Context, bookCopy models.BookCopy) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, bookCopy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUsersMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockCopiesMockRecorder) Create(ctx, bookCopy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsers)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCopies)(nil).Create), ctx, bookCopy)
 }
 
 // Delete mocks base method.
-func (m *MockUsers) Delete(id int) error {
+func (m *MockCopies) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUsersMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockCopiesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsers)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCopies)(nil).Delete), ctx, id)
 }
 
-// GetAll mocks base method.
-func (m *MockUsers) GetAll() ([]models.User, error) {
+// GetByBook mocks base method.
+func (m *MockCopies) GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]models.User)
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID)
+	ret0, _ := ret[0].([]models.BookCopy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockUsersMockRecorder) GetAll() *gomock.Call {
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockCopiesMockRecorder) GetByBook(ctx, bookID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockCopies)(nil).GetByBook), ctx, bookID)
 }
 
 // GetByID mocks base method.
-func (m *MockUsers) GetByID(id int) (models.User, error) {
+func (m *MockCopies) GetByID(ctx context.Context, id int) (models.BookCopy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
-	ret0, _ := ret[0].(models.User)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCopiesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCopies)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCopies) Update(ctx context.Context, bookCopy models.BookCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, bookCopy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCopiesMockRecorder) Update(ctx, bookCopy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCopies)(nil).Update), ctx, bookCopy)
+}
+
+// MockFines is a mock of Fines interface.
+type MockFines struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinesMockRecorder
+}
+
+// MockFinesMockRecorder is the mock recorder for MockFines.
+type MockFinesMockRecorder struct {
+	mock *MockFines
+}
+
+// NewMockFines creates a new mock instance.
+func NewMockFines(ctrl *gomock.Controller) *MockFines {
+	mock := &MockFines{ctrl: ctrl}
+	mock.recorder = &MockFinesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFines) EXPECT() *MockFinesMockRecorder {
+	return m.recorder
+}
+
+// Balance mocks base method.
+func (m *MockFines) Balance(ctx context.Context, userID int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balance", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balance indicates an expected call of Balance.
+func (mr *MockFinesMockRecorder) Balance(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockFines)(nil).Balance), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockFines) Create(ctx context.Context, fine models.Fine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, fine)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFinesMockRecorder) Create(ctx, fine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFines)(nil).Create), ctx, fine)
+}
+
+// CreatePayment mocks base method.
+func (m *MockFines) CreatePayment(ctx context.Context, payment models.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockFinesMockRecorder) CreatePayment(ctx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockFines)(nil).CreatePayment), ctx, payment)
+}
+
+// GetByID mocks base method.
+func (m *MockFines) GetByID(ctx context.Context, id int) (models.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Fine)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockUsersMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockFinesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFines)(nil).GetByID), ctx, id)
+}
+
+// GetByUser mocks base method.
+func (m *MockFines) GetByUser(ctx context.Context, userID int) ([]models.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Fine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockFinesMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockFines)(nil).GetByUser), ctx, userID)
+}
+
+// GetPayments mocks base method.
+func (m *MockFines) GetPayments(ctx context.Context, userID int) ([]models.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayments", ctx, userID)
+	ret0, _ := ret[0].([]models.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayments indicates an expected call of GetPayments.
+func (mr *MockFinesMockRecorder) GetPayments(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayments", reflect.TypeOf((*MockFines)(nil).GetPayments), ctx, userID)
+}
+
+// Waive mocks base method.
+func (m *MockFines) Waive(ctx context.Context, id int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Waive", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Waive indicates an expected call of Waive.
+func (mr *MockFinesMockRecorder) Waive(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Waive", reflect.TypeOf((*MockFines)(nil).Waive), ctx, id, reason)
+}
+
+// MockHolds is a mock of Holds interface.
+type MockHolds struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldsMockRecorder
+}
+
+// MockHoldsMockRecorder is the mock recorder for MockHolds.
+type MockHoldsMockRecorder struct {
+	mock *MockHolds
+}
+
+// NewMockHolds creates a new mock instance.
+func NewMockHolds(ctrl *gomock.Controller) *MockHolds {
+	mock := &MockHolds{ctrl: ctrl}
+	mock.recorder = &MockHoldsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHolds) EXPECT() *MockHoldsMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockHolds) Cancel(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockHoldsMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockHolds)(nil).Cancel), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockHolds) Create(ctx context.Context, userID, bookID int) (models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, bookID)
+	ret0, _ := ret[0].(models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHoldsMockRecorder) Create(ctx, userID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHolds)(nil).Create), ctx, userID, bookID)
+}
+
+// GetByBook mocks base method.
+func (m *MockHolds) GetByBook(ctx context.Context, bookID int) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockHoldsMockRecorder) GetByBook(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockHolds)(nil).GetByBook), ctx, bookID)
+}
+
+// GetByID mocks base method.
+func (m *MockHolds) GetByID(ctx context.Context, id int) (models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHolds)(nil).GetByID), ctx, id)
+}
+
+// GetByUser mocks base method.
+func (m *MockHolds) GetByUser(ctx context.Context, userID int) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockHoldsMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockHolds)(nil).GetByUser), ctx, userID)
+}
+
+// ProcessQueue mocks base method.
+func (m *MockHolds) ProcessQueue(ctx context.Context, bookID int, pickupWindow time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessQueue", ctx, bookID, pickupWindow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessQueue indicates an expected call of ProcessQueue.
+func (mr *MockHoldsMockRecorder) ProcessQueue(ctx, bookID, pickupWindow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessQueue", reflect.TypeOf((*MockHolds)(nil).ProcessQueue), ctx, bookID, pickupWindow)
+}
+
+// MockLoanPolicies is a mock of LoanPolicies interface.
+type MockLoanPolicies struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanPoliciesMockRecorder
+}
+
+// MockLoanPoliciesMockRecorder is the mock recorder for MockLoanPolicies.
+type MockLoanPoliciesMockRecorder struct {
+	mock *MockLoanPolicies
+}
+
+// NewMockLoanPolicies creates a new mock instance.
+func NewMockLoanPolicies(ctrl *gomock.Controller) *MockLoanPolicies {
+	mock := &MockLoanPolicies{ctrl: ctrl}
+	mock.recorder = &MockLoanPoliciesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanPolicies) EXPECT() *MockLoanPoliciesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLoanPolicies) Create(ctx context.Context, policy models.LoanPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLoanPoliciesMockRecorder) Create(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLoanPolicies)(nil).Create), ctx, policy)
+}
+
+// Delete mocks base method.
+func (m *MockLoanPolicies) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLoanPoliciesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLoanPolicies)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockLoanPolicies) GetAll(ctx context.Context) ([]models.LoanPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.LoanPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockLoanPoliciesMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLoanPolicies)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockLoanPolicies) GetByID(ctx context.Context, id int) (models.LoanPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.LoanPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockLoanPoliciesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockLoanPolicies)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockLoanPolicies) Update(ctx context.Context, policy models.LoanPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLoanPoliciesMockRecorder) Update(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLoanPolicies)(nil).Update), ctx, policy)
+}
+
+// MockSubjects is a mock of Subjects interface.
+type MockSubjects struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubjectsMockRecorder
+}
+
+// MockSubjectsMockRecorder is the mock recorder for MockSubjects.
+type MockSubjectsMockRecorder struct {
+	mock *MockSubjects
+}
+
+// NewMockSubjects creates a new mock instance.
+func NewMockSubjects(ctrl *gomock.Controller) *MockSubjects {
+	mock := &MockSubjects{ctrl: ctrl}
+	mock.recorder = &MockSubjectsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubjects) EXPECT() *MockSubjectsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubjects) Create(ctx context.Context, subject models.Subject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubjectsMockRecorder) Create(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubjects)(nil).Create), ctx, subject)
+}
+
+// Delete mocks base method.
+func (m *MockSubjects) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubjectsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubjects)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockSubjects) GetAll(ctx context.Context) ([]models.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSubjectsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSubjects)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockSubjects) GetByID(ctx context.Context, id int) (models.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSubjectsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSubjects)(nil).GetByID), ctx, id)
+}
+
+// IsDescendant mocks base method.
+func (m *MockSubjects) IsDescendant(ctx context.Context, id, ancestorID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDescendant", ctx, id, ancestorID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDescendant indicates an expected call of IsDescendant.
+func (mr *MockSubjectsMockRecorder) IsDescendant(ctx, id, ancestorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDescendant", reflect.TypeOf((*MockSubjects)(nil).IsDescendant), ctx, id, ancestorID)
+}
+
+// LockPath mocks base method.
+func (m *MockSubjects) LockPath(ctx context.Context, id, parentID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPath", ctx, id, parentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockPath indicates an expected call of LockPath.
+func (mr *MockSubjectsMockRecorder) LockPath(ctx, id, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPath", reflect.TypeOf((*MockSubjects)(nil).LockPath), ctx, id, parentID)
+}
+
+// Update mocks base method.
+func (m *MockSubjects) Update(ctx context.Context, subject models.Subject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubjectsMockRecorder) Update(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubjects)(nil).Update), ctx, subject)
+}
+
+// MockTags is a mock of Tags interface.
+type MockTags struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsMockRecorder
+}
+
+// MockTagsMockRecorder is the mock recorder for MockTags.
+type MockTagsMockRecorder struct {
+	mock *MockTags
+}
+
+// NewMockTags creates a new mock instance.
+func NewMockTags(ctrl *gomock.Controller) *MockTags {
+	mock := &MockTags{ctrl: ctrl}
+	mock.recorder = &MockTagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTags) EXPECT() *MockTagsMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTags) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTags)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTags) GetAll(ctx context.Context) ([]models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTags)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockTags) GetByID(ctx context.Context, id int) (models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTagsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTags)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockTags) Update(ctx context.Context, tag models.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsMockRecorder) Update(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTags)(nil).Update), ctx, tag)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUsers) Create(ctx context.Context, user models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUsersMockRecorder) Create(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsers)(nil).Create), ctx, user)
+}
+
+// Delete mocks base method.
+func (m *MockUsers) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUsersMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsers)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockUsers) GetAll(ctx context.Context, query models.UserQuery) ([]models.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockUsersMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll), ctx, query)
+}
+
+// GetByEmail mocks base method.
+func (m *MockUsers) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockUsersMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUsers)(nil).GetByEmail), ctx, email)
+}
+
+// GetByID mocks base method.
+func (m *MockUsers) GetByID(ctx context.Context, id int) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUsersMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), ctx, id)
+}
+
+// Lock mocks base method.
+func (m *MockUsers) Lock(ctx context.Context, id int) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockUsersMockRecorder) Lock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockUsers)(nil).Lock), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockUsers) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUsersMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUsers)(nil).Restore), ctx, id)
+}
+
+// SetRole mocks base method.
+func (m *MockUsers) SetRole(ctx context.Context, id int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUsersMockRecorder) SetRole(ctx, id, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUsers)(nil).SetRole), ctx, id, role)
 }
 
 // Update mocks base method.
-func (m *MockUsers) Update(user models.User) error {
+func (m *MockUsers) Update(ctx context.Context, user models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", user)
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUsersMockRecorder) Update(user interface{}) *gomock.Call {
+func (mr *MockUsersMockRecorder) Update(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), ctx, user)
 }
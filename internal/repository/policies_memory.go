@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"library/models"
+)
+
+type LoanPolicyMemory struct {
+	store *memoryStore
+}
+
+func (r *LoanPolicyMemory) GetAll(ctx context.Context) ([]models.LoanPolicy, error) {
+	var policies []models.LoanPolicy
+	err := r.store.do(ctx, func(d *memoryData) error {
+		policies = sortedRows(d.policies, nil)
+		return nil
+	})
+	return policies, err
+}
+
+func (r *LoanPolicyMemory) Create(ctx context.Context, policy models.LoanPolicy) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		policy.ID = 0
+		return d.savePolicy(policy)
+	})
+}
+
+func (r *LoanPolicyMemory) GetByID(ctx context.Context, id int) (models.LoanPolicy, error) {
+	var policy models.LoanPolicy
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if policy, ok = d.policies[id]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return policy, err
+}
+
+// Delete refuses to delete a policy that users are under.
+func (r *LoanPolicyMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.policies[id]; !ok {
+			return ErrNotFound
+		}
+		if hasRow(d.users, func(user models.User) bool {
+			return user.LoanPolicyID != nil && *user.LoanPolicyID == id
+		}) {
+			return ErrForeignKey
+		}
+		delete(d.policies, id)
+		return nil
+	})
+}
+
+// Update saves a policy, adding it when there is none with its ID, as Save
+// does.
+func (r *LoanPolicyMemory) Update(ctx context.Context, policy models.LoanPolicy) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.policies[policy.ID]; !ok {
+			return ErrNotFound
+		}
+		return d.savePolicy(policy)
+	})
+}
+
+// savePolicy stores a policy, as a new one when it has no ID, unless its
+// name is taken.
+func (d *memoryData) savePolicy(policy models.LoanPolicy) error {
+	if hasRow(d.policies, func(other models.LoanPolicy) bool {
+		return other.ID != policy.ID && other.Name == policy.Name
+	}) {
+		return ErrDuplicate
+	}
+	if policy.ID == 0 {
+		policy.ID = d.nextID("loan_policies")
+	}
+	d.policies[policy.ID] = policy
+	return nil
+}
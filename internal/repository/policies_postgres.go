@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"library/models"
+)
+
+type LoanPolicyPostgres struct {
+	db *gorm.DB
+}
+
+func NewLoanPolicyPostgres(db *gorm.DB) *LoanPolicyPostgres {
+	return &LoanPolicyPostgres{db: db}
+}
+
+func (r *LoanPolicyPostgres) GetAll(ctx context.Context) ([]models.LoanPolicy, error) {
+	var policies []models.LoanPolicy
+	err := conn(ctx, r.db).Order("id").Find(&policies).Error
+	return policies, err
+}
+
+func (r *LoanPolicyPostgres) Create(ctx context.Context, policy models.LoanPolicy) error {
+	return conn(ctx, r.db).Create(&policy).Error
+}
+
+func (r *LoanPolicyPostgres) GetByID(ctx context.Context, id int) (models.LoanPolicy, error) {
+	var policy models.LoanPolicy
+	err := conn(ctx, r.db).First(&policy, id).Error
+	return policy, err
+}
+
+func (r *LoanPolicyPostgres) Delete(ctx context.Context, id int) error {
+	return deleteByID(conn(ctx, r.db), &models.LoanPolicy{}, id)
+}
+
+func (r *LoanPolicyPostgres) Update(ctx context.Context, policy models.LoanPolicy) error {
+	return updateByID(conn(ctx, r.db), &policy)
+}
@@ -1,35 +1,89 @@
 package repository
 
 import (
-	"fmt"
-	"gorm.io/driver/postgres"
+	"context"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/gorm/clause"
 	"library/models"
+	"time"
 )
 
-type Config struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	SSLMode  string
+// txKey is the context key of the transaction a Transactor runs a function
+// in.
+type txKey struct{}
+
+// conn is what a repository queries through for ctx: the transaction of
+// WithinTransaction when ctx is inside one, otherwise db itself.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
 }
 
-func NewPostgresDB(cfg Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, cfg.SSLMode)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
-	if err != nil {
-		return nil, err
+// deleteByID deletes the row of model with the given id, reporting
+// ErrNotFound when there is none.
+func deleteByID(db *gorm.DB, model interface{}, id int) error {
+	res := db.Delete(model, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
 	}
+	return nil
+}
 
-	err = db.AutoMigrate(&models.Author{}, &models.Book{}, &models.User{})
-	if err != nil {
-		return nil, err
+// updateByID writes every column of model to its row, found by primary key,
+// except the omitted ones. Unlike Save it never inserts: it reports
+// ErrNotFound when there is no such row. Associations are left alone.
+func updateByID(db *gorm.DB, model interface{}, omit ...string) error {
+	res := db.Model(model).Select("*").Omit(append([]string{clause.Associations}, omit...)...).Updates(model)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// archiveByID archives the live row of model with the given id, or restores
+// the archived one when at is nil. It reports ErrNotFound when there is no
+// such row.
+func archiveByID(db *gorm.DB, model interface{}, id int, at *time.Time) error {
+	state := "deleted_at IS NULL"
+	if at == nil {
+		state = "deleted_at IS NOT NULL"
 	}
 
-	return db, nil
+	res := db.Model(model).Where("id = ? AND "+state, id).Update("deleted_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkIdle reports ErrOpenLoans or ErrActiveHolds when a loan or a hold
+// whose column is one of ids is still open, as none may be for a book or
+// user to be archived.
+func checkIdle(db *gorm.DB, column string, ids []int) error {
+	var count int64
+	if err := db.Model(&models.RentedBook{}).Where(column+" IN ? AND returned_at IS NULL", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrOpenLoans
+	}
+
+	if err := db.Model(&models.Hold{}).Where(column+" IN ? AND status IN ?", ids, activeHoldStatuses).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrActiveHolds
+	}
+	return nil
 }
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paginate limits a query to the 1-based page of pageSize rows.
+func paginate(page, pageSize int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Limit(pageSize).Offset((page - 1) * pageSize)
+	}
+}
+
+// sortOrder orders by a column of table given as a sort key such as
+// "-published_at", then by id so that pages are stable.
+func sortOrder(table, sort string) clause.OrderBy {
+	desc := strings.HasPrefix(sort, "-")
+	column := strings.TrimPrefix(sort, "-")
+	if column == "" {
+		column = "id"
+	}
+
+	columns := []clause.OrderByColumn{{Column: clause.Column{Table: table, Name: column}, Desc: desc}}
+	if column != "id" {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Table: table, Name: "id"}, Desc: desc})
+	}
+	return clause.OrderBy{Columns: columns}
+}
+
+// archived is a condition on the rows of table a listing shows: the
+// archived ones when deleted is set, the others when it is not.
+func archived(table string, deleted bool) string {
+	if deleted {
+		return table + ".deleted_at IS NOT NULL"
+	}
+	return table + ".deleted_at IS NULL"
+}
+
+// containsMatch is a condition that column matches a containsPattern,
+// ignoring case. SQLite has no ILIKE; its LIKE ignores the case of ASCII
+// letters only, and needs to be told the escape character.
+func containsMatch(db *gorm.DB, column string) string {
+	if isSQLite(db) {
+		return column + ` LIKE ? ESCAPE '\'`
+	}
+	return column + " ILIKE ?"
+}
+
+// containsPattern is a LIKE pattern matching s anywhere in a value.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
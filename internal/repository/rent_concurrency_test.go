@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"library/models"
+)
+
+// testDB connects to the database named by LIBRARY_TEST_DSN and migrates it.
+// For the docker-compose database that is
+// "host=localhost port=5437 user=postgres password=1qw23er4 dbname=postgres sslmode=disable".
+func testDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("LIBRARY_TEST_DSN")
+	if dsn == "" {
+		t.Skip("LIBRARY_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	require.NoError(t, err)
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return db
+}
+
+// testSQLiteDB opens a new, migrated SQLite database in a temporary
+// directory, so it needs nothing installed.
+func testSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := openSQLite(filepath.Join(t.TempDir(), "library.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return db
+}
+
+// raceFixture creates a book with a single copy and the given number of users.
+func raceFixture(t *testing.T, db *gorm.DB, users int) (models.Book, models.BookCopy, []models.User) {
+	suffix := time.Now().UnixNano()
+
+	author := models.Author{Name: fmt.Sprintf("Race Author %d", suffix)}
+	require.NoError(t, db.Create(&author).Error)
+	book := models.Book{Title: "Race Book", Contributors: []models.BookContributor{{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1}}, PublishedAt: time.Now(), ISBN: fmt.Sprintf("race-%d", suffix)}
+	require.NoError(t, db.Create(&book).Error)
+	bookCopy := models.BookCopy{BookID: book.ID, Barcode: fmt.Sprintf("race-%d", suffix), Condition: models.CopyConditionGood}
+	require.NoError(t, db.Create(&bookCopy).Error)
+
+	renters := make([]models.User, users)
+	for i := range renters {
+		renters[i] = models.User{Name: "Racer", Email: fmt.Sprintf("racer-%d-%d@example.com", suffix, i)}
+		require.NoError(t, db.Create(&renters[i]).Error)
+	}
+
+	t.Cleanup(func() {
+		db.Where("book_id = ?", book.ID).Delete(&models.RentedBook{})
+		db.Delete(&bookCopy)
+		db.Delete(&book)
+		db.Delete(&author)
+		for _, user := range renters {
+			db.Delete(&user)
+		}
+	})
+
+	return book, bookCopy, renters
+}
+
+// parallel runs fn once per index at the same moment and returns how many
+// calls succeeded.
+func parallel(n int, fn func(i int) error) int {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		start     = make(chan struct{})
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			if fn(i) == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return succeeded
+}
+
+func TestBookPostgres_RentBook_Concurrent(t *testing.T) {
+	db := testDB(t)
+	r := NewBookPostgres(db)
+
+	const renters = 10
+	book, bookCopy, users := raceFixture(t, db, renters)
+
+	t.Run("any copy", func(t *testing.T) {
+		succeeded := parallel(renters, func(i int) error {
+			return r.RentBook(context.Background(), users[i].ID, book.ID, 0, time.Hour)
+		})
+		assert.Equal(t, 1, succeeded)
+
+		var open int64
+		require.NoError(t, db.Model(&models.RentedBook{}).Where("copy_id = ? AND returned_at IS NULL", bookCopy.ID).Count(&open).Error)
+		assert.Equal(t, int64(1), open)
+	})
+
+	var loan models.RentedBook
+	require.NoError(t, db.Where("copy_id = ? AND returned_at IS NULL", bookCopy.ID).First(&loan).Error)
+
+	t.Run("return", func(t *testing.T) {
+		succeeded := parallel(renters, func(int) error {
+			_, err := r.ReturnBook(context.Background(), loan.UserID, book.ID, 0)
+			return err
+		})
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("specific copy", func(t *testing.T) {
+		succeeded := parallel(renters, func(i int) error {
+			return r.RentBook(context.Background(), users[i].ID, 0, bookCopy.ID, time.Hour)
+		})
+		assert.Equal(t, 1, succeeded)
+
+		var open int64
+		require.NoError(t, db.Model(&models.RentedBook{}).Where("copy_id = ? AND returned_at IS NULL", bookCopy.ID).Count(&open).Error)
+		assert.Equal(t, int64(1), open)
+	})
+}
@@ -1,47 +1,156 @@
 package repository
 
 import (
+	"context"
 	"gorm.io/gorm"
 	"library/models"
+	"time"
 )
 
 //go:generate mockgen -source=repository.go -destination=mock_repository.go -package=repository
+type APIKeys interface {
+	Create(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (models.APIKey, error)
+	Revoke(ctx context.Context, id int, at time.Time) error
+	Touch(ctx context.Context, id int, at time.Time) error
+}
+
+// Transactor runs work that spans several repositories as one unit.
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction, which every repository
+	// method called with the context fn is given takes part in. The
+	// transaction is rolled back when fn returns an error or panics, and
+	// committed otherwise. Called inside another transaction it opens a
+	// savepoint, so only the work of fn is undone.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type Authors interface {
-	GetAll() ([]models.Author, error)
-	Create(author models.Author) error
-	GetByID(id int) (models.Author, error)
-	Delete(id int) error
-	Update(author models.Author) error
+	GetAll(ctx context.Context, query models.AuthorQuery) ([]models.Author, int64, error)
+	Create(ctx context.Context, author models.Author) error
+	GetByID(ctx context.Context, id int) (models.Author, error)
+	GetBooks(ctx context.Context, id int) ([]models.BookContributor, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, author models.Author) error
 }
 
 type Books interface {
-	GetAll() ([]models.Book, error)
-	Create(book models.Book) error
-	GetByID(id int) (models.Book, error)
-	Delete(id int) error
-	Update(book models.Book) error
-	RentBook(userID, bookID int) error
-	ReturnBook(userID, bookID int) error
+	GetAll(ctx context.Context, query models.BookQuery) ([]models.Book, int64, error)
+	Search(ctx context.Context, query models.SearchQuery) ([]models.SearchHit, int64, error)
+	Create(ctx context.Context, book models.Book) error
+	GetByID(ctx context.Context, id int) (models.Book, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, book models.Book) error
+	RentBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration) error
+	ReturnBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error)
+	RenewBook(ctx context.Context, userID, bookID, copyID int, loanPeriod time.Duration, maxRenewals int) (models.RentedBook, error)
+	GetOverdue(ctx context.Context) ([]models.RentedBook, error)
+	CountOpenLoans(ctx context.Context, userID int) (int, error)
+	GetLoans(ctx context.Context, query models.LoanQuery) ([]models.RentedBook, int64, error)
+}
+
+type Copies interface {
+	GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error)
+	Create(ctx context.Context, bookCopy models.BookCopy) error
+	GetByID(ctx context.Context, id int) (models.BookCopy, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, bookCopy models.BookCopy) error
+}
+
+type Fines interface {
+	Create(ctx context.Context, fine models.Fine) error
+	GetByID(ctx context.Context, id int) (models.Fine, error)
+	GetByUser(ctx context.Context, userID int) ([]models.Fine, error)
+	Waive(ctx context.Context, id int, reason string) error
+	CreatePayment(ctx context.Context, payment models.Payment) error
+	GetPayments(ctx context.Context, userID int) ([]models.Payment, error)
+	Balance(ctx context.Context, userID int) (int64, error)
+}
+
+type Holds interface {
+	Create(ctx context.Context, userID, bookID int) (models.Hold, error)
+	GetByID(ctx context.Context, id int) (models.Hold, error)
+	GetByBook(ctx context.Context, bookID int) ([]models.Hold, error)
+	GetByUser(ctx context.Context, userID int) ([]models.Hold, error)
+	Cancel(ctx context.Context, id int) error
+	ProcessQueue(ctx context.Context, bookID int, pickupWindow time.Duration) error
+}
+
+type LoanPolicies interface {
+	GetAll(ctx context.Context) ([]models.LoanPolicy, error)
+	Create(ctx context.Context, policy models.LoanPolicy) error
+	GetByID(ctx context.Context, id int) (models.LoanPolicy, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, policy models.LoanPolicy) error
+}
+
+type Subjects interface {
+	GetAll(ctx context.Context) ([]models.Subject, error)
+	Create(ctx context.Context, subject models.Subject) error
+	GetByID(ctx context.Context, id int) (models.Subject, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, subject models.Subject) error
+	IsDescendant(ctx context.Context, id, ancestorID int) (bool, error)
+	// LockPath holds the row of subject id, and those of parentID and every
+	// subject above it, until the transaction of ctx ends. Moves that lock
+	// their path first cannot each pass the cycle check and together make a
+	// cycle. A parentID of 0 locks subject id alone. It reports ErrNotFound
+	// when subject id does not exist.
+	LockPath(ctx context.Context, id, parentID int) error
+}
+
+type Tags interface {
+	GetAll(ctx context.Context) ([]models.Tag, error)
+	GetByID(ctx context.Context, id int) (models.Tag, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, tag models.Tag) error
 }
 
 type Users interface {
-	GetAll() ([]models.User, error)
-	Create(user models.User) error
-	GetByID(id int) (models.User, error)
-	Delete(id int) error
-	Update(user models.User) error
+	GetAll(ctx context.Context, query models.UserQuery) ([]models.User, int64, error)
+	Create(ctx context.Context, user models.User) error
+	GetByID(ctx context.Context, id int) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	// Lock returns a live user with their loan policy and holds their row
+	// until the transaction of ctx ends, so that work done for one user, such
+	// as checking their limits before lending them a book, happens one call
+	// at a time. Outside a transaction nothing stays locked.
+	Lock(ctx context.Context, id int) (models.User, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, user models.User) error
+	SetRole(ctx context.Context, id int, role string) error
 }
 
 type Repository struct {
+	APIKeys
 	Authors
 	Books
+	Copies
+	Fines
+	Holds
+	LoanPolicies
+	Subjects
+	Tags
+	Transactor
 	Users
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		Authors: NewAuthorPostgres(db),
-		Books:   NewBookPostgres(db),
-		Users:   NewUserPostgres(db),
+		APIKeys:      NewAPIKeyPostgres(db),
+		Authors:      NewAuthorPostgres(db),
+		Books:        NewBookPostgres(db),
+		Copies:       NewCopyPostgres(db),
+		Fines:        NewFinePostgres(db),
+		Holds:        NewHoldPostgres(db),
+		LoanPolicies: NewLoanPolicyPostgres(db),
+		Subjects:     NewSubjectPostgres(db),
+		Tags:         NewTagPostgres(db),
+		Transactor:   NewTransactorPostgres(db),
+		Users:        NewUserPostgres(db),
 	}
 }
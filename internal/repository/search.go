@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"cmp"
+	"gorm.io/gorm"
+	"library/internal/isbn"
+	"library/models"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// searchCredits joins the names of a book's contributors, in credit order.
+const searchCredits = `LEFT JOIN LATERAL (SELECT string_agg(authors.name, ', ' ORDER BY book_contributors.position) AS names
+	FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id
+	WHERE book_contributors.book_id = books.id) AS credits ON true`
+
+// A catalogue search matches a book when the words of the query are
+// prefixes of words in its search_document, when the query is close to its
+// title or a contributor's name by trigram word similarity, which tolerates
+// typos, or when the query is its ISBN.
+const searchMatch = `(books.search_document @@ to_tsquery('simple', @words)
+	OR @text <% books.title
+	OR EXISTS (SELECT 1 FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id
+		WHERE book_contributors.book_id = books.id AND @text <% authors.name)
+	OR replace(books.isbn, '-', '') = @isbn)`
+
+// searchColumns ranks full-text matches above near misses and an exact ISBN
+// above both. Trigram-only matches have nothing to highlight, so their
+// snippets are the plain title and contributor names.
+const searchColumns = `books.id AS book_id, books.title, books.isbn, coalesce(credits.names, '') AS contributors,
+	ts_rank(books.search_document, to_tsquery('simple', @words))
+		+ greatest(word_similarity(@text, books.title), word_similarity(@text, coalesce(credits.names, '')))
+		+ CASE WHEN replace(books.isbn, '-', '') = @isbn THEN 1 ELSE 0 END AS rank,
+	ts_headline('simple', books.title, to_tsquery('simple', @words), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_snippet,
+	ts_headline('simple', coalesce(credits.names, ''), to_tsquery('simple', @words), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS contributors_snippet`
+
+// sqliteCredits is searchCredits for SQLite, which has no lateral joins.
+const sqliteCredits = `LEFT JOIN (SELECT book_id, group_concat(name, ', ') AS names
+	FROM (SELECT book_contributors.book_id, authors.name
+		FROM book_contributors JOIN authors ON authors.id = book_contributors.author_id
+		ORDER BY book_contributors.book_id, book_contributors.position)
+	GROUP BY book_id) AS credits ON credits.book_id = books.id`
+
+// sqliteSearchText is what the words of a search are looked for in on
+// SQLite.
+const sqliteSearchText = `(books.title || ' ' || coalesce(credits.names, '') || ' ' || replace(books.isbn, '-', ''))`
+
+// typoThreshold is the trigram word similarity a query needs to a title or
+// an author name to match it. pg_trgm's default of 0.6 misses a swapped pair
+// of letters in a short word.
+const typoThreshold = "0.4"
+
+var isbnLike = regexp.MustCompile(`^[0-9][0-9-]*[0-9Xx]$`)
+
+// searchArgs are the named arguments of searchMatch and searchColumns.
+func searchArgs(text string) map[string]interface{} {
+	text = strings.TrimSpace(text)
+	return map[string]interface{}{
+		"text":  text,
+		"words": prefixQuery(text),
+		"isbn":  exactISBN(text),
+	}
+}
+
+// prefixQuery turns free text into a tsquery matching documents that have a
+// word starting with each word of the text, e.g. "tolst war" becomes
+// "tolst:* & war:*". Anything but letters and digits separates words, so
+// the result never contains tsquery operators from the input.
+func prefixQuery(text string) string {
+	words := queryWords(text)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// queryWords splits the text of a search into lower-case words. A
+// hyphenated ISBN, or part of one, is kept together as a single word.
+func queryWords(text string) []string {
+	var words []string
+	if isbnLike.MatchString(text) {
+		words = []string{strings.ReplaceAll(text, "-", "")}
+	} else {
+		words = strings.FieldsFunc(text, notWordRune)
+	}
+
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
+
+func notWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// exactISBN returns text in the stored form of an ISBN when it is a valid
+// ISBN-10 or ISBN-13, or "" otherwise.
+func exactISBN(text string) string {
+	normalized, err := isbn.Normalize(text)
+	if err != nil {
+		return ""
+	}
+	return normalized
+}
+
+// rankHit tells whether a book matches a search made without a search
+// document, and ranks and highlights its hit when it does. The book matches
+// when each of words starts a word of its title, contributor names or ISBN,
+// or when its ISBN is isbn, the exactISBN of the text. Title words rank
+// above names, names above the ISBN, and an exact ISBN above everything.
+// hit comes with the title, ISBN and contributors of the book.
+func rankHit(hit *models.SearchHit, words []string, isbn string) bool {
+	digits := strings.ReplaceAll(hit.ISBN, "-", "")
+	rank, matched := prefixRank(words, hit.Title, hit.Contributors, digits)
+	exact := isbn != "" && digits == isbn
+
+	hit.TitleSnippet, hit.ContributorsSnippet = hit.Title, hit.Contributors
+	if matched {
+		hit.TitleSnippet = highlight(hit.Title, words)
+		hit.ContributorsSnippet = highlight(hit.Contributors, words)
+	}
+	if exact {
+		rank++
+	}
+	hit.Rank = rank
+	return matched || exact
+}
+
+// sortHits orders hits best first, leaving hits of the same rank in the
+// order they are in.
+func sortHits(hits []models.SearchHit) {
+	slices.SortStableFunc(hits, func(a, b models.SearchHit) int {
+		return cmp.Compare(b.Rank, a.Rank)
+	})
+}
+
+// searchWeights are the weights of the title, contributor names and ISBN in
+// a search, the default weights of ts_rank.
+var searchWeights = []float64{1, 0.4, 0.2}
+
+// prefixRank tells whether each of words starts a word of one of fields,
+// and how well they match: the mean weight of the best field each word is
+// found in, scaled down to the range of ts_rank.
+func prefixRank(words []string, fields ...string) (float64, bool) {
+	if len(words) == 0 {
+		return 0, false
+	}
+
+	var rank float64
+	for _, word := range words {
+		weight := 0.0
+		for i, field := range fields {
+			if slices.ContainsFunc(strings.FieldsFunc(field, notWordRune), func(fieldWord string) bool {
+				return strings.HasPrefix(strings.ToLower(fieldWord), word)
+			}) {
+				weight = searchWeights[i]
+				break
+			}
+		}
+		if weight == 0 {
+			return 0, false
+		}
+		rank += weight
+	}
+	return rank / float64(len(words)) / 10, true
+}
+
+// highlight wraps the words of text that start with one of words in <mark>
+// tags, as ts_headline does.
+func highlight(text string, words []string) string {
+	var (
+		b     strings.Builder
+		start = -1
+	)
+	flush := func(end int) {
+		word := text[start:end]
+		marked := slices.ContainsFunc(words, func(prefix string) bool {
+			return strings.HasPrefix(strings.ToLower(word), prefix)
+		})
+		if marked {
+			b.WriteString("<mark>" + word + "</mark>")
+		} else {
+			b.WriteString(word)
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		if !notWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			flush(i)
+		}
+		b.WriteRune(r)
+	}
+	if start >= 0 {
+		flush(len(text))
+	}
+	return b.String()
+}
+
+// searchSQLite searches the catalogue of a SQLite database, which has no
+// search document or trigrams. The database finds the books that contain
+// each word of the text anywhere, ignoring the case of ASCII letters only,
+// and rankHit keeps those where the words start words, so typos are not
+// forgiven.
+func searchSQLite(db *gorm.DB, query models.SearchQuery) ([]models.SearchHit, int64, error) {
+	text := strings.TrimSpace(query.Text)
+	words := queryWords(text)
+	isbn := exactISBN(text)
+
+	if len(words) == 0 {
+		return nil, 0, nil
+	}
+	conds := make([]string, len(words))
+	args := make([]interface{}, len(words))
+	for i, word := range words {
+		conds[i] = sqliteSearchText + ` LIKE ? ESCAPE '\'`
+		args[i] = containsPattern(word)
+	}
+	match := "books.deleted_at IS NULL AND ((" + strings.Join(conds, " AND ") + ") OR replace(books.isbn, '-', '') = ?)"
+
+	var hits []models.SearchHit
+	err := db.Table("books").Joins(sqliteCredits).
+		Where(match, append(args, isbn)...).
+		Select("books.id AS book_id, books.title, books.isbn, coalesce(credits.names, '') AS contributors").
+		Order("books.id").
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := hits[:0]
+	for _, hit := range hits {
+		if rankHit(&hit, words, isbn) {
+			matches = append(matches, hit)
+		}
+	}
+	sortHits(matches)
+	return paged(matches, query.Page, query.PageSize), int64(len(matches)), nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"library/models"
+)
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "tolst", want: "tolst:*"},
+		{text: "War and Peace", want: "war:* & and:* & peace:*"},
+		{text: "Dostoyevsky's  \"Idiot\"", want: "dostoyevsky:* & s:* & idiot:*"},
+		{text: "fox & !hound | (cat:*)", want: "fox:* & hound:* & cat:*"},
+		{text: "Жизнь и судьба", want: "жизнь:* & и:* & судьба:*"},
+		{text: "978-3-16", want: "978316:*"},
+		{text: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, prefixQuery(tt.text), tt.text)
+	}
+}
+
+func TestExactISBN(t *testing.T) {
+	assert.Equal(t, "9783161484100", exactISBN("978-3-16-148410-0"))
+	assert.Equal(t, "9780804429573", exactISBN("0-8044-2957-x"))
+	assert.Equal(t, "", exactISBN("978-3-16-148410-1"))
+	assert.Equal(t, "", exactISBN("978-3-16"))
+	assert.Equal(t, "", exactISBN("war and peace"))
+}
+
+func TestBookPostgres_Search(t *testing.T) {
+	db := testDB(t)
+	repo := NewBookPostgres(db)
+
+	word := fmt.Sprintf("zq%d", time.Now().UnixNano())
+	author := models.Author{Name: "Marguerite Yourcenar"}
+	require.NoError(t, db.Create(&author).Error)
+	translator := models.Author{Name: "Grace Frick"}
+	require.NoError(t, db.Create(&translator).Error)
+	book := models.Book{Title: "Memoirs of Hadrian " + word, PublishedAt: time.Now(), ISBN: "9780374529260", Contributors: []models.BookContributor{
+		{AuthorID: author.ID, Role: models.RoleAuthor, Position: 1},
+		{AuthorID: translator.ID, Role: models.RoleTranslator, Position: 2},
+	}}
+	require.NoError(t, repo.Create(context.Background(), book))
+	require.NoError(t, db.Where("isbn = ?", book.ISBN).First(&book).Error)
+	t.Cleanup(func() {
+		db.Delete(&book)
+		db.Delete(&author)
+		db.Delete(&translator)
+	})
+
+	search := func(text string) []models.SearchHit {
+		hits, _, err := repo.Search(context.Background(), models.SearchQuery{Text: text, Page: 1, PageSize: 100})
+		require.NoError(t, err)
+		for _, hit := range hits {
+			if hit.BookID == book.ID {
+				return []models.SearchHit{hit}
+			}
+		}
+		return nil
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		hits := search("hadr " + word[:len(word)-3])
+		require.Len(t, hits, 1)
+		assert.Contains(t, hits[0].TitleSnippet, "<mark>Hadrian</mark>")
+	})
+
+	t.Run("contributors", func(t *testing.T) {
+		assert.Len(t, search("yourcenar "+word), 1)
+		hits := search("frick " + word)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Marguerite Yourcenar, Grace Frick", hits[0].Contributors)
+		assert.Equal(t, "Marguerite Yourcenar, Grace <mark>Frick</mark>", hits[0].ContributorsSnippet)
+	})
+
+	t.Run("typo", func(t *testing.T) {
+		assert.Len(t, search("Memiors of Hadrain"), 1)
+	})
+
+	t.Run("isbn", func(t *testing.T) {
+		assert.Len(t, search("9780374529260"), 1)
+		assert.Len(t, search("978-0-374-52926-0"), 1)
+		assert.Len(t, search("0-374-52926-4"), 1)
+	})
+
+	t.Run("renamed author", func(t *testing.T) {
+		require.NoError(t, db.Model(&author).Update("name", "M. Yourcenar-Crayencour").Error)
+		assert.Len(t, search("crayencour "+word), 1)
+	})
+}
+
+func TestBookMemory_Search(t *testing.T) {
+	testPrefixSearch(t, NewMemoryRepository())
+}
+
+func TestBookSQLite_Search(t *testing.T) {
+	testPrefixSearch(t, NewRepository(testSQLiteDB(t)))
+}
+
+// testPrefixSearch checks the search of a storage without a search
+// document, which rankHit does. repos must be empty.
+func testPrefixSearch(t *testing.T, repos *Repository) {
+	ctx := context.Background()
+
+	require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: "Marguerite Yourcenar"}))
+	require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: "Grace Frick"}))
+	require.NoError(t, repos.Books.Create(ctx, models.Book{Title: "Memoirs of Hadrian", PublishedAt: time.Now(), ISBN: "978-0-374-52926-0", Contributors: []models.BookContributor{
+		{AuthorID: 1, Role: models.RoleAuthor, Position: 1},
+		{AuthorID: 2, Role: models.RoleTranslator, Position: 2},
+	}}))
+	require.NoError(t, repos.Books.Create(ctx, models.Book{Title: "Grace Notes", PublishedAt: time.Now(), ISBN: "978-3-16-148410-0", Contributors: []models.BookContributor{
+		{AuthorID: 1, Role: models.RoleAuthor, Position: 1},
+	}}))
+
+	t.Run("word prefixes", func(t *testing.T) {
+		hits, total, err := repos.Books.Search(ctx, models.SearchQuery{Text: "hadr yourc", Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Marguerite Yourcenar, Grace Frick", hits[0].Contributors)
+		assert.Equal(t, "Memoirs of <mark>Hadrian</mark>", hits[0].TitleSnippet)
+		assert.Equal(t, "Marguerite <mark>Yourcenar</mark>, Grace Frick", hits[0].ContributorsSnippet)
+	})
+
+	t.Run("title above names", func(t *testing.T) {
+		hits, _, err := repos.Books.Search(ctx, models.SearchQuery{Text: "grace", Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		assert.Equal(t, "Grace Notes", hits[0].Title)
+	})
+
+	t.Run("exact ISBN", func(t *testing.T) {
+		hits, _, err := repos.Books.Search(ctx, models.SearchQuery{Text: "9780374529260", Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Memoirs of Hadrian", hits[0].Title)
+	})
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"library/models"
+	"slices"
+	"strings"
+)
+
+type SubjectMemory struct {
+	store *memoryStore
+}
+
+// GetAll returns every subject, without children, ordered by name.
+func (r *SubjectMemory) GetAll(ctx context.Context) ([]models.Subject, error) {
+	var subjects []models.Subject
+	err := r.store.do(ctx, func(d *memoryData) error {
+		subjects = d.subjectsByName(nil)
+		return nil
+	})
+	return subjects, err
+}
+
+func (r *SubjectMemory) Create(ctx context.Context, subject models.Subject) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		subject.ID = 0
+		return d.saveSubject(subject)
+	})
+}
+
+// GetByID returns a subject with its direct children.
+func (r *SubjectMemory) GetByID(ctx context.Context, id int) (models.Subject, error) {
+	var subject models.Subject
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if subject, ok = d.subjects[id]; !ok {
+			return ErrNotFound
+		}
+		subject.Children = d.subjectsByName(func(child models.Subject) bool {
+			return child.ParentID != nil && *child.ParentID == id
+		})
+		return nil
+	})
+	return subject, err
+}
+
+// Delete refuses to delete a subject that has children or books filed
+// under it.
+func (r *SubjectMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.subjects[id]; !ok {
+			return ErrNotFound
+		}
+		if hasRow(d.subjects, func(child models.Subject) bool { return child.ParentID != nil && *child.ParentID == id }) ||
+			hasRow(d.books, func(book models.Book) bool {
+				return slices.ContainsFunc(book.Subjects, func(subject models.Subject) bool { return subject.ID == id })
+			}) {
+			return ErrForeignKey
+		}
+		delete(d.subjects, id)
+		return nil
+	})
+}
+
+// Update saves a subject, adding it when there is none with its ID, as Save
+// does.
+func (r *SubjectMemory) Update(ctx context.Context, subject models.Subject) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.subjects[subject.ID]; !ok {
+			return ErrNotFound
+		}
+		return d.saveSubject(subject)
+	})
+}
+
+// IsDescendant reports whether subject id is somewhere below ancestorID.
+func (r *SubjectMemory) IsDescendant(ctx context.Context, id, ancestorID int) (bool, error) {
+	var descendant bool
+	err := r.store.do(ctx, func(d *memoryData) error {
+		descendant = id != ancestorID && d.subjectTree(ancestorID)[id]
+		return nil
+	})
+	return descendant, err
+}
+
+// LockPath needs no lock of its own: the store is held throughout a
+// transaction.
+func (r *SubjectMemory) LockPath(ctx context.Context, id, parentID int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.subjects[id]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// saveSubject stores a subject without its children, as a new one when it
+// has no ID, unless its parent does not exist or has a child of the same
+// name, ignoring case.
+func (d *memoryData) saveSubject(subject models.Subject) error {
+	parentID := func(subject models.Subject) int {
+		if subject.ParentID == nil {
+			return 0
+		}
+		return *subject.ParentID
+	}
+	if hasRow(d.subjects, func(other models.Subject) bool {
+		return other.ID != subject.ID && parentID(other) == parentID(subject) && strings.EqualFold(other.Name, subject.Name)
+	}) {
+		return ErrDuplicate
+	}
+	if subject.ParentID != nil {
+		if _, ok := d.subjects[*subject.ParentID]; !ok {
+			return ErrForeignKey
+		}
+	}
+
+	if subject.ID == 0 {
+		subject.ID = d.nextID("subjects")
+	}
+	subject.Children = nil
+	d.subjects[subject.ID] = subject
+	return nil
+}
+
+// subjectTree returns the ID of a subject and the IDs of all subjects below
+// it.
+func (d *memoryData) subjectTree(id int) map[int]bool {
+	tree := map[int]bool{}
+	if _, ok := d.subjects[id]; !ok {
+		return tree
+	}
+
+	tree[id] = true
+	for grown := true; grown; {
+		grown = false
+		for _, subject := range d.subjects {
+			if subject.ParentID != nil && tree[*subject.ParentID] && !tree[subject.ID] {
+				tree[subject.ID] = true
+				grown = true
+			}
+		}
+	}
+	return tree
+}
+
+// subjectsByName returns the subjects that match ordered by name.
+func (d *memoryData) subjectsByName(match func(models.Subject) bool) []models.Subject {
+	subjects := sortedRows(d.subjects, match)
+	slices.SortStableFunc(subjects, func(a, b models.Subject) int { return cmp.Compare(a.Name, b.Name) })
+	return subjects
+}
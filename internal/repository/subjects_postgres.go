@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"library/models"
+	"slices"
+)
+
+// subjectTree selects the id of the subject given as its argument and the
+// ids of all subjects below it.
+const subjectTree = `WITH RECURSIVE tree AS (
+	SELECT id FROM subjects WHERE id = ?
+	UNION ALL
+	SELECT subjects.id FROM subjects JOIN tree ON subjects.parent_id = tree.id
+) SELECT id FROM tree`
+
+// subjectPath selects the id of the subject given as its argument and the
+// ids of all subjects above it. UNION stops it should the parents ever loop.
+const subjectPath = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM subjects WHERE id = ?
+	UNION
+	SELECT subjects.id, subjects.parent_id FROM subjects JOIN ancestors ON subjects.id = ancestors.parent_id
+) SELECT id FROM ancestors`
+
+type SubjectPostgres struct {
+	db *gorm.DB
+}
+
+func NewSubjectPostgres(db *gorm.DB) *SubjectPostgres {
+	return &SubjectPostgres{db: db}
+}
+
+// GetAll returns every subject, without children, ordered by name.
+func (r *SubjectPostgres) GetAll(ctx context.Context) ([]models.Subject, error) {
+	var subjects []models.Subject
+	err := conn(ctx, r.db).Order("name, id").Find(&subjects).Error
+	return subjects, err
+}
+
+func (r *SubjectPostgres) Create(ctx context.Context, subject models.Subject) error {
+	return conn(ctx, r.db).Omit("Children").Create(&subject).Error
+}
+
+// GetByID returns a subject with its direct children.
+func (r *SubjectPostgres) GetByID(ctx context.Context, id int) (models.Subject, error) {
+	var subject models.Subject
+	err := conn(ctx, r.db).Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name, id")
+	}).First(&subject, id).Error
+	return subject, err
+}
+
+func (r *SubjectPostgres) Delete(ctx context.Context, id int) error {
+	return deleteByID(conn(ctx, r.db), &models.Subject{}, id)
+}
+
+func (r *SubjectPostgres) Update(ctx context.Context, subject models.Subject) error {
+	return updateByID(conn(ctx, r.db), &subject)
+}
+
+// IsDescendant reports whether subject id is somewhere below ancestorID.
+func (r *SubjectPostgres) IsDescendant(ctx context.Context, id, ancestorID int) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Raw("SELECT count(*) FROM ("+subjectTree+") AS tree WHERE id = ? AND id <> ?", ancestorID, id, ancestorID).
+		Scan(&count).Error
+	return count > 0, err
+}
+
+// LockPath locks the rows in id order, so that two moves locking some of
+// the same subjects wait for each other rather than deadlock.
+func (r *SubjectPostgres) LockPath(ctx context.Context, id, parentID int) error {
+	var locked []int
+	err := conn(ctx, r.db).Model(&models.Subject{}).
+		Where("id = ? OR id IN ("+subjectPath+")", id, parentID).
+		Order("id").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &locked).Error
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(locked, id) {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"library/models"
+	"slices"
+)
+
+type TagMemory struct {
+	store *memoryStore
+}
+
+func (r *TagMemory) GetAll(ctx context.Context) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.store.do(ctx, func(d *memoryData) error {
+		tags = sortedRows(d.tags, nil)
+		slices.SortStableFunc(tags, func(a, b models.Tag) int { return cmp.Compare(a.Name, b.Name) })
+		return nil
+	})
+	return tags, err
+}
+
+func (r *TagMemory) GetByID(ctx context.Context, id int) (models.Tag, error) {
+	var tag models.Tag
+	err := r.store.do(ctx, func(d *memoryData) error {
+		var ok bool
+		if tag, ok = d.tags[id]; !ok {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return tag, err
+}
+
+// Delete removes a tag from every book it is on.
+func (r *TagMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.tags[id]; !ok {
+			return ErrNotFound
+		}
+		for _, book := range d.books {
+			tags := slices.DeleteFunc(slices.Clone(book.Tags), func(tag models.Tag) bool { return tag.ID == id })
+			if len(tags) != len(book.Tags) {
+				book.Tags = tags
+				d.books[book.ID] = book
+			}
+		}
+		delete(d.tags, id)
+		return nil
+	})
+}
+
+// Update saves a tag, adding it when there is none with its ID, as Save
+// does.
+func (r *TagMemory) Update(ctx context.Context, tag models.Tag) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if _, ok := d.tags[tag.ID]; !ok {
+			return ErrNotFound
+		}
+		if hasRow(d.tags, func(other models.Tag) bool { return other.ID != tag.ID && other.Name == tag.Name }) {
+			return ErrDuplicate
+		}
+		d.tags[tag.ID] = tag
+		return nil
+	})
+}
+
+// tagID returns the ID of the tag with name, creating the tag if there is
+// none.
+func (d *memoryData) tagID(name string) int {
+	for _, tag := range d.tags {
+		if tag.Name == name {
+			return tag.ID
+		}
+	}
+	tag := models.Tag{ID: d.nextID("tags"), Name: name}
+	d.tags[tag.ID] = tag
+	return tag.ID
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"library/models"
+)
+
+type TagPostgres struct {
+	db *gorm.DB
+}
+
+func NewTagPostgres(db *gorm.DB) *TagPostgres {
+	return &TagPostgres{db: db}
+}
+
+func (r *TagPostgres) GetAll(ctx context.Context) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := conn(ctx, r.db).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagPostgres) GetByID(ctx context.Context, id int) (models.Tag, error) {
+	var tag models.Tag
+	err := conn(ctx, r.db).First(&tag, id).Error
+	return tag, err
+}
+
+// Delete removes a tag from every book it is on.
+func (r *TagPostgres) Delete(ctx context.Context, id int) error {
+	return deleteByID(conn(ctx, r.db), &models.Tag{}, id)
+}
+
+func (r *TagPostgres) Update(ctx context.Context, tag models.Tag) error {
+	return updateByID(conn(ctx, r.db), &tag)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type TransactorPostgres struct {
+	db *gorm.DB
+}
+
+func NewTransactorPostgres(db *gorm.DB) *TransactorPostgres {
+	return &TransactorPostgres{db: db}
+}
+
+// WithinTransaction relies on gorm to roll back on errors and panics, and to
+// use a savepoint when the context already holds a transaction.
+func (r *TransactorPostgres) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"library/models"
+)
+
+func TestTransactorPostgres_WithinTransaction(t *testing.T) {
+	db := testDB(t)
+	repos := NewRepository(db)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	created := func(name string) bool {
+		var count int64
+		require.NoError(t, db.Model(&models.Author{}).Where("name = ?", name).Count(&count).Error)
+		return count > 0
+	}
+	name := func(label string) string {
+		return fmt.Sprintf("Tx %s %d", label, suffix)
+	}
+	t.Cleanup(func() {
+		db.Where("name LIKE ?", fmt.Sprintf("Tx %% %d", suffix)).Delete(&models.Author{})
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+			return repos.Authors.Create(ctx, models.Author{Name: name("committed")})
+		})
+		require.NoError(t, err)
+		assert.True(t, created(name("committed")))
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		failure := errors.New("failure")
+		err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: name("failed")}))
+			// the repositories see what the transaction wrote so far
+			_, total, err := repos.Authors.GetAll(ctx, models.AuthorQuery{Name: name("failed"), Page: 1, PageSize: 1})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		assert.False(t, created(name("failed")))
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = repos.WithinTransaction(ctx, func(ctx context.Context) error {
+				require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: name("panicked")}))
+				panic("failure")
+			})
+		})
+		assert.False(t, created(name("panicked")))
+	})
+
+	t.Run("nested transactions roll back on their own", func(t *testing.T) {
+		err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: name("outer")}))
+			err := repos.WithinTransaction(ctx, func(ctx context.Context) error {
+				require.NoError(t, repos.Authors.Create(ctx, models.Author{Name: name("inner")}))
+				return gorm.ErrInvalidData
+			})
+			assert.ErrorIs(t, err, gorm.ErrInvalidData)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, created(name("outer")))
+		assert.False(t, created(name("inner")))
+	})
+}
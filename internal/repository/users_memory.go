@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"library/models"
+	"strings"
+	"time"
+)
+
+type UserMemory struct {
+	store *memoryStore
+}
+
+// GetAll lists users without their loans, which are paged separately by
+// BookMemory.GetLoans.
+func (r *UserMemory) GetAll(ctx context.Context, query models.UserQuery) ([]models.User, int64, error) {
+	var (
+		users []models.User
+		total int64
+	)
+	err := r.store.do(ctx, func(d *memoryData) error {
+		matches := sortedRows(d.users, func(user models.User) bool {
+			return (user.DeletedAt != nil) == query.Deleted &&
+				(query.Name == "" || containsFold(user.Name, query.Name)) &&
+				(query.Email == "" || containsFold(user.Email, query.Email))
+		})
+		sortBy(matches, query.Sort, map[string]func(a, b models.User) int{
+			"name":  func(a, b models.User) int { return cmp.Compare(a.Name, b.Name) },
+			"email": func(a, b models.User) int { return cmp.Compare(a.Email, b.Email) },
+		})
+
+		total = int64(len(matches))
+		for _, user := range paged(matches, query.Page, query.PageSize) {
+			users = append(users, d.user(user))
+		}
+		return nil
+	})
+	return users, total, err
+}
+
+func (r *UserMemory) Create(ctx context.Context, user models.User) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		if err := d.checkUser(user); err != nil {
+			return err
+		}
+		if user.Role == "" {
+			user.Role = models.RolePatron
+		}
+		user.ID = d.nextID("users")
+		d.saveUser(user)
+		return nil
+	})
+}
+
+func (r *UserMemory) GetByID(ctx context.Context, id int) (models.User, error) {
+	var user models.User
+	err := r.store.do(ctx, func(d *memoryData) error {
+		stored, ok := d.users[id]
+		if !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		user = d.user(stored)
+		user.RentedBooks = sortedRows(d.loans, func(loan models.RentedBook) bool {
+			return loan.UserID == id
+		})
+		return nil
+	})
+	return user, err
+}
+
+// Lock needs no lock of its own: the store is held throughout a
+// transaction.
+func (r *UserMemory) Lock(ctx context.Context, id int) (models.User, error) {
+	var user models.User
+	err := r.store.do(ctx, func(d *memoryData) error {
+		stored, ok := d.users[id]
+		if !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		user = d.user(stored)
+		return nil
+	})
+	return user, err
+}
+
+// GetByEmail finds the user with email, ignoring case, for logging in.
+// Archived users cannot log in.
+func (r *UserMemory) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.store.do(ctx, func(d *memoryData) error {
+		users := sortedRows(d.users, func(user models.User) bool {
+			return user.DeletedAt == nil && strings.EqualFold(user.Email, email)
+		})
+		if len(users) == 0 {
+			return ErrNotFound
+		}
+		user = users[0]
+		return nil
+	})
+	return user, err
+}
+
+// Delete archives a user, unless they have books out on loan or on hold.
+// Their loans, holds, fines and payments are kept.
+func (r *UserMemory) Delete(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		user, ok := d.users[id]
+		if !ok || user.DeletedAt != nil {
+			return ErrNotFound
+		}
+		if err := d.checkIdle(func(userID, _ int) bool { return userID == id }); err != nil {
+			return err
+		}
+		now := time.Now()
+		user.DeletedAt = &now
+		d.users[id] = user
+		return nil
+	})
+}
+
+func (r *UserMemory) Restore(ctx context.Context, id int) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		user, ok := d.users[id]
+		if !ok || user.DeletedAt == nil {
+			return ErrNotFound
+		}
+		user.DeletedAt = nil
+		d.users[id] = user
+		return nil
+	})
+}
+
+// Update saves the details of a live user. The password hash is only set
+// when the user registers and the role only by SetRole.
+func (r *UserMemory) Update(ctx context.Context, user models.User) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		stored, ok := d.users[user.ID]
+		if !ok || stored.DeletedAt != nil {
+			return ErrNotFound
+		}
+		if err := d.checkUser(user); err != nil {
+			return err
+		}
+		user.PasswordHash = stored.PasswordHash
+		user.Role = stored.Role
+		user.DeletedAt = nil
+		d.saveUser(user)
+		return nil
+	})
+}
+
+func (r *UserMemory) SetRole(ctx context.Context, id int, role string) error {
+	return r.store.do(ctx, func(d *memoryData) error {
+		user, ok := d.users[id]
+		if !ok || user.DeletedAt != nil {
+			return ErrNotFound
+		}
+		user.Role = role
+		d.users[id] = user
+		return nil
+	})
+}
+
+// checkUser reports a taken email or an unknown loan policy. Emails are
+// unique ignoring case, as users log in with any case.
+func (d *memoryData) checkUser(user models.User) error {
+	if hasRow(d.users, func(other models.User) bool { return other.ID != user.ID && strings.EqualFold(other.Email, user.Email) }) {
+		return ErrDuplicate
+	}
+	if user.LoanPolicyID != nil {
+		if _, ok := d.policies[*user.LoanPolicyID]; !ok {
+			return ErrForeignKey
+		}
+	}
+	return nil
+}
+
+func (d *memoryData) saveUser(user models.User) {
+	user.LoanPolicy = models.LoanPolicy{}
+	user.RentedBooks = nil
+	d.users[user.ID] = user
+}
+
+// user returns a stored user with their loan policy.
+func (d *memoryData) user(user models.User) models.User {
+	if user.LoanPolicyID != nil {
+		user.LoanPolicy = d.policies[*user.LoanPolicyID]
+	}
+	return user
+}
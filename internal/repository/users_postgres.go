@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"library/models"
+	"time"
 )
 
 type UserPostgres struct {
@@ -13,26 +16,86 @@ func NewUserPostgres(db *gorm.DB) *UserPostgres {
 	return &UserPostgres{db: db}
 }
 
-func (r *UserPostgres) GetAll() ([]models.User, error) {
+// GetAll lists users without their loans, which are paged separately by
+// BookPostgres.GetLoans.
+func (r *UserPostgres) GetAll(ctx context.Context, query models.UserQuery) ([]models.User, int64, error) {
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where(archived("users", query.Deleted))
+		if query.Name != "" {
+			db = db.Where(containsMatch(db, "users.name"), containsPattern(query.Name))
+		}
+		if query.Email != "" {
+			db = db.Where(containsMatch(db, "users.email"), containsPattern(query.Email))
+		}
+		return db
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).Model(&models.User{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var users []models.User
-	err := r.db.Preload("RentedBooks").Find(&users).Error
-	return users, err
+	err := conn(ctx, r.db).Preload("LoanPolicy").
+		Scopes(filter, paginate(query.Page, query.PageSize)).
+		Clauses(sortOrder("users", query.Sort)).
+		Find(&users).Error
+	return users, total, err
+}
+
+func (r *UserPostgres) Create(ctx context.Context, user models.User) error {
+	return conn(ctx, r.db).Create(&user).Error
+}
+
+func (r *UserPostgres) GetByID(ctx context.Context, id int) (models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Preload("LoanPolicy").Preload("RentedBooks").Where("deleted_at IS NULL").First(&user, id).Error
+	return user, err
 }
 
-func (r *UserPostgres) Create(user models.User) error {
-	return r.db.Create(&user).Error
+func (r *UserPostgres) Lock(ctx context.Context, id int) (models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("LoanPolicy").Where("deleted_at IS NULL").First(&user, id).Error
+	return user, err
 }
 
-func (r *UserPostgres) GetByID(id int) (models.User, error) {
+// GetByEmail finds the user with email, ignoring case, for logging in.
+// Archived users cannot log in.
+func (r *UserPostgres) GetByEmail(ctx context.Context, email string) (models.User, error) {
 	var user models.User
-	err := r.db.Preload("RentedBooks").First(&user, id).Error
+	err := conn(ctx, r.db).Where("lower(email) = lower(?) AND deleted_at IS NULL", email).Order("id").First(&user).Error
 	return user, err
 }
 
-func (r *UserPostgres) Delete(id int) error {
-	return r.db.Delete(&models.User{}, id).Error
+// Delete archives a user, unless they have books out on loan or on hold.
+// Their loans, holds, fines and payments are kept.
+func (r *UserPostgres) Delete(ctx context.Context, id int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := archiveByID(tx, &models.User{}, id, &now); err != nil {
+			return err
+		}
+		return checkIdle(tx, "user_id", []int{id})
+	})
+}
+
+func (r *UserPostgres) Restore(ctx context.Context, id int) error {
+	return archiveByID(conn(ctx, r.db), &models.User{}, id, nil)
+}
+
+// Update saves the details of a live user. The password hash is only set
+// when the user registers and the role only by SetRole.
+func (r *UserPostgres) Update(ctx context.Context, user models.User) error {
+	return updateByID(conn(ctx, r.db).Where("deleted_at IS NULL"), &user, "password_hash", "role", "deleted_at")
 }
 
-func (r *UserPostgres) Update(user models.User) error {
-	return r.db.Save(&user).Error
+func (r *UserPostgres) SetRole(ctx context.Context, id int, role string) error {
+	res := conn(ctx, r.db).Model(&models.User{}).Where("id = ? AND deleted_at IS NULL", id).Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"library/models"
 	"testing"
 
@@ -19,11 +20,14 @@ func TestUserPostgres_GetAll(t *testing.T) {
 		{ID: 2, Name: "User 2"},
 	}
 
-	mockDB.EXPECT().GetAll().Return(expectedUsers, nil)
+	query := models.UserQuery{Page: 1, PageSize: 20}
 
-	users, err := mockDB.GetAll()
+	mockDB.EXPECT().GetAll(gomock.Any(), query).Return(expectedUsers, int64(2), nil)
+
+	users, total, err := mockDB.GetAll(context.Background(), query)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUsers, users)
+	assert.Equal(t, int64(2), total)
 }
 
 func TestUserPostgres_Create(t *testing.T) {
@@ -34,9 +38,9 @@ func TestUserPostgres_Create(t *testing.T) {
 
 	newUser := models.User{Name: "New User"}
 
-	mockDB.EXPECT().Create(newUser).Return(nil)
+	mockDB.EXPECT().Create(gomock.Any(), newUser).Return(nil)
 
-	err := mockDB.Create(newUser)
+	err := mockDB.Create(context.Background(), newUser)
 	assert.Nil(t, err)
 }
 
@@ -48,9 +52,9 @@ func TestUserPostgres_GetByID(t *testing.T) {
 
 	expectedUser := models.User{ID: 1, Name: "User 1"}
 
-	mockDB.EXPECT().GetByID(1).Return(expectedUser, nil)
+	mockDB.EXPECT().GetByID(gomock.Any(), 1).Return(expectedUser, nil)
 
-	user, err := mockDB.GetByID(1)
+	user, err := mockDB.GetByID(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 }
@@ -63,9 +67,9 @@ func TestUserPostgres_Delete(t *testing.T) {
 
 	userID := 1
 
-	mockDB.EXPECT().Delete(userID).Return(nil)
+	mockDB.EXPECT().Delete(gomock.Any(), userID).Return(nil)
 
-	err := mockDB.Delete(userID)
+	err := mockDB.Delete(context.Background(), userID)
 	assert.NoError(t, err)
 }
 
@@ -77,8 +81,23 @@ func TestUserPostgres_Update(t *testing.T) {
 
 	updatedUser := models.User{ID: 1, Name: "Updated User"}
 
-	mockDB.EXPECT().Update(updatedUser).Return(nil)
+	mockDB.EXPECT().Update(gomock.Any(), updatedUser).Return(nil)
+
+	err := mockDB.Update(context.Background(), updatedUser)
+	assert.NoError(t, err)
+}
+
+func TestUserPostgres_GetByEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockUsers(ctrl)
+
+	expectedUser := models.User{ID: 1, Name: "User 1", Email: "user1@example.com"}
+
+	mockDB.EXPECT().GetByEmail(gomock.Any(), "user1@example.com").Return(expectedUser, nil)
 
-	err := mockDB.Update(updatedUser)
+	user, err := mockDB.GetByEmail(context.Background(), "user1@example.com")
 	assert.NoError(t, err)
+	assert.Equal(t, expectedUser, user)
 }
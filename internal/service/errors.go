@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"library/internal/repository"
+)
+
+// Kinds of domain errors. The controller maps each kind to an HTTP status.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthenticated is returned when credentials or a token are wrong.
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// Error is a domain error. Message is safe to show to clients and Code is a
+// stable machine-readable identifier of the error; errors.Is matches Kind.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func notFound(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func conflict(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func invalid(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func unauthenticated(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnauthenticated, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ruleErrors are the lending rules enforced by the repositories.
+var ruleErrors = []struct {
+	err  error
+	kind error
+	code string
+}{
+	{repository.ErrCopyMismatch, ErrValidation, "copy_mismatch"},
+	{repository.ErrCopyUnavailable, ErrConflict, "copy_unavailable"},
+	{repository.ErrNoCopyAvailable, ErrConflict, "no_copy_available"},
+	{repository.ErrNotRented, ErrNotFound, "not_rented"},
+	{repository.ErrRenewalLimit, ErrConflict, "renewal_limit_reached"},
+	{repository.ErrLoanOverdue, ErrConflict, "loan_overdue"},
+	{repository.ErrConcurrentUpdate, ErrConflict, "concurrent_update"},
+	{repository.ErrCopyRented, ErrConflict, "copy_rented"},
+	{repository.ErrHoldExists, ErrConflict, "hold_exists"},
+	{repository.ErrAlreadyRenting, ErrConflict, "already_renting"},
+	{repository.ErrBookAvailable, ErrConflict, "book_available"},
+	{repository.ErrHoldNotActive, ErrConflict, "hold_not_active"},
+	{repository.ErrFineWaived, ErrConflict, "fine_waived"},
+	{repository.ErrAPIKeyRevoked, ErrConflict, "key_revoked"},
+	{repository.ErrOpenLoans, ErrConflict, "open_loans"},
+	{repository.ErrActiveHolds, ErrConflict, "active_holds"},
+	{repository.ErrAuthorArchived, ErrConflict, "author_archived"},
+}
+
+// translate turns an error from a repository call about entity into a
+// domain error. Errors it does not recognise are faults and are returned
+// unchanged.
+func translate(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return &Error{Kind: ErrNotFound, Code: "not_found", Message: entity + " not found", Err: err}
+	case errors.Is(err, repository.ErrDuplicate):
+		return &Error{Kind: ErrConflict, Code: "already_exists", Message: entity + " already exists", Err: err}
+	case errors.Is(err, repository.ErrForeignKey):
+		return &Error{Kind: ErrValidation, Code: "unknown_reference", Message: entity + " refers to a record that does not exist", Err: err}
+	}
+
+	for _, rule := range ruleErrors {
+		if errors.Is(err, rule.err) {
+			return &Error{Kind: rule.kind, Code: rule.code, Message: err.Error(), Err: err}
+		}
+	}
+	return err
+}
+
+// translateDelete is translate for deletes, where a foreign key violation
+// means other records still refer to the one being deleted.
+func translateDelete(err error, entity string) error {
+	if errors.Is(err, repository.ErrForeignKey) {
+		return &Error{Kind: ErrConflict, Code: "in_use", Message: entity + " is still referenced by other records", Err: err}
+	}
+	return translate(err, entity)
+}
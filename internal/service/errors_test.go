@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthors := repository.NewMockAuthors(ctrl)
+	s := service.NewAuthorsService(mockAuthors)
+
+	fault := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		repoErr error
+		call    func() error
+		kind    error
+		code    string
+		message string
+	}{
+		{
+			name:    "not found",
+			repoErr: repository.ErrNotFound,
+			call:    func() error { _, err := s.GetByID(context.Background(), 1); return err },
+			kind:    service.ErrNotFound,
+			code:    "not_found",
+			message: "author not found",
+		},
+		{
+			name:    "delete still referenced",
+			repoErr: repository.ErrForeignKey,
+			call:    func() error { return s.Delete(context.Background(), 1) },
+			kind:    service.ErrConflict,
+			code:    "in_use",
+			message: "author is still referenced by other records",
+		},
+		{
+			name:    "duplicate",
+			repoErr: repository.ErrDuplicate,
+			call:    func() error { return s.Create(context.Background(), models.Author{Name: "Author"}) },
+			kind:    service.ErrConflict,
+			code:    "already_exists",
+			message: "author already exists",
+		},
+		{
+			name:    "rule",
+			repoErr: fmt.Errorf("%w: 2 renewals allowed", repository.ErrRenewalLimit),
+			call:    func() error { return s.Update(context.Background(), models.Author{ID: 1, Name: "Author"}) },
+			kind:    service.ErrConflict,
+			code:    "renewal_limit_reached",
+			message: "renewal limit reached: 2 renewals allowed",
+		},
+	}
+
+	mockAuthors.EXPECT().GetByID(gomock.Any(), 1).Return(models.Author{}, tests[0].repoErr)
+	mockAuthors.EXPECT().Delete(gomock.Any(), 1).Return(tests[1].repoErr)
+	mockAuthors.EXPECT().Create(gomock.Any(), models.Author{Name: "Author"}).Return(tests[2].repoErr)
+	mockAuthors.EXPECT().Update(gomock.Any(), models.Author{ID: 1, Name: "Author"}).Return(tests[3].repoErr)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.ErrorIs(t, err, tt.kind)
+			assert.ErrorIs(t, err, tt.repoErr)
+
+			var domainErr *service.Error
+			if assert.ErrorAs(t, err, &domainErr) {
+				assert.Equal(t, tt.code, domainErr.Code)
+				assert.Equal(t, tt.message, domainErr.Message)
+			}
+		})
+	}
+
+	t.Run("fault", func(t *testing.T) {
+		mockAuthors.EXPECT().Delete(gomock.Any(), 2).Return(fault)
+
+		err := s.Delete(context.Background(), 2)
+		assert.Equal(t, fault, err)
+	})
+}
+
+func TestBookService_Create_DuplicateISBN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	book := models.Book{Title: "Book", Contributors: []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}, ISBN: "9780306406157"}
+	mockBooks.EXPECT().Create(gomock.Any(), book).Return(repository.ErrDuplicate)
+
+	err := s.Create(context.Background(), book)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, "a book with ISBN 9780306406157 already exists")
+}
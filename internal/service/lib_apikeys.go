@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"library/internal/policy"
+	"library/internal/repository"
+	"library/models"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so leaked keys are easy to spot.
+	apiKeyPrefix = "lib_"
+	// apiKeyShownLength is how much of a key is kept in the clear to tell
+	// keys apart.
+	apiKeyShownLength = 12
+)
+
+type APIKeyService struct {
+	repo repository.APIKeys
+}
+
+func NewAPIKeysService(repo repository.APIKeys) APIKeys {
+	return &APIKeyService{repo: repo}
+}
+
+// Create issues an API key with the name, scopes and expiry of key. The key
+// itself is returned only here: what is stored is its hash.
+func (s *APIKeyService) Create(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return models.APIKey{}, "", invalid("name_required", "name is required")
+	}
+	if len(key.Scopes) == 0 {
+		return models.APIKey{}, "", invalid("scopes_required", "at least one scope is required")
+	}
+	for _, scope := range key.Scopes {
+		if !policy.ValidPermission(policy.Permission(scope)) {
+			return models.APIKey{}, "", invalid("unknown_scope", "unknown scope %q", scope)
+		}
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return models.APIKey{}, "", invalid("invalid_expiry", "expiry must be in the future")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return models.APIKey{}, "", err
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	key.ID = 0
+	key.Prefix = secret[:apiKeyShownLength]
+	key.KeyHash = hashAPIKey(secret)
+	key.CreatedAt = now
+	key.RevokedAt = nil
+	key.LastUsedAt = nil
+
+	created, err := s.repo.Create(ctx, key)
+	if err != nil {
+		return models.APIKey{}, "", translate(err, "API key")
+	}
+	return created, secret, nil
+}
+
+func (s *APIKeyService) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id int) error {
+	return translate(s.repo.Revoke(ctx, id, time.Now()), "API key")
+}
+
+// Authenticate returns the identity of the holder of an API key and records
+// that it was used.
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (models.Identity, error) {
+	key, err := s.repo.GetByHash(ctx, hashAPIKey(secret))
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Identity{}, unauthenticated("invalid_api_key", "API key is not valid")
+	}
+	if err != nil {
+		return models.Identity{}, err
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil {
+		return models.Identity{}, unauthenticated("invalid_api_key", "API key is not valid")
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return models.Identity{}, unauthenticated("api_key_expired", "API key has expired")
+	}
+
+	if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+		return models.Identity{}, err
+	}
+	return models.Identity{APIKeyID: key.ID, Scopes: key.Scopes}, nil
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := repository.NewMockAPIKeys(ctrl)
+	s := service.NewAPIKeysService(mockAPIKeys)
+
+	var stored models.APIKey
+	mockAPIKeys.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key models.APIKey) (models.APIKey, error) {
+		stored = key
+		key.ID = 1
+		return key, nil
+	})
+
+	created, secret, err := s.Create(context.Background(), models.APIKey{Name: " kiosk ", Scopes: []string{"loans:write"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, "kiosk", created.Name)
+	assert.True(t, strings.HasPrefix(secret, "lib_"))
+	assert.Equal(t, secret[:12], stored.Prefix)
+
+	// only the hash of the key is stored
+	sum := sha256.Sum256([]byte(secret))
+	assert.Equal(t, hex.EncodeToString(sum[:]), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, secret)
+}
+
+func TestAPIKeyService_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewAPIKeysService(repository.NewMockAPIKeys(ctrl))
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		key  models.APIKey
+		code string
+	}{
+		{models.APIKey{Scopes: []string{"loans:write"}}, "name_required"},
+		{models.APIKey{Name: "kiosk"}, "scopes_required"},
+		{models.APIKey{Name: "kiosk", Scopes: []string{"loans:write", "everything"}}, "unknown_scope"},
+		{models.APIKey{Name: "kiosk", Scopes: []string{"loans:write"}, ExpiresAt: &past}, "invalid_expiry"},
+	}
+	for _, tt := range tests {
+		_, _, err := s.Create(context.Background(), tt.key)
+		assert.ErrorIs(t, err, service.ErrValidation, tt.code)
+		assert.Equal(t, tt.code, err.(*service.Error).Code)
+	}
+}
+
+func TestAPIKeyService_Revoke_AlreadyRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := repository.NewMockAPIKeys(ctrl)
+	s := service.NewAPIKeysService(mockAPIKeys)
+
+	mockAPIKeys.EXPECT().Revoke(gomock.Any(), 1, gomock.Any()).Return(repository.ErrAPIKeyRevoked)
+
+	err := s.Revoke(context.Background(), 1)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.Equal(t, "key_revoked", err.(*service.Error).Code)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeys := repository.NewMockAPIKeys(ctrl)
+	s := service.NewAPIKeysService(mockAPIKeys)
+
+	sum := sha256.Sum256([]byte("lib_secret"))
+	key := models.APIKey{ID: 4, Scopes: []string{"catalog:read"}}
+	mockAPIKeys.EXPECT().GetByHash(gomock.Any(), hex.EncodeToString(sum[:])).Return(key, nil)
+	mockAPIKeys.EXPECT().Touch(gomock.Any(), 4, gomock.Any()).Return(nil)
+
+	identity, err := s.Authenticate(context.Background(), "lib_secret")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Identity{APIKeyID: 4, Scopes: []string{"catalog:read"}}, identity)
+}
+
+func TestAPIKeyService_Authenticate_Refused(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		key  models.APIKey
+		err  error
+		code string
+	}{
+		{"unknown", models.APIKey{}, repository.ErrNotFound, "invalid_api_key"},
+		{"revoked", models.APIKey{ID: 1, RevokedAt: &past}, nil, "invalid_api_key"},
+		{"expired", models.APIKey{ID: 1, ExpiresAt: &past}, nil, "api_key_expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPIKeys := repository.NewMockAPIKeys(ctrl)
+			s := service.NewAPIKeysService(mockAPIKeys)
+
+			mockAPIKeys.EXPECT().GetByHash(gomock.Any(), gomock.Any()).Return(tt.key, tt.err)
+
+			_, err := s.Authenticate(context.Background(), "lib_secret")
+			assert.ErrorIs(t, err, service.ErrUnauthenticated)
+			assert.Equal(t, tt.code, err.(*service.Error).Code)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"library/internal/policy"
+	"library/internal/repository"
+	"library/models"
+)
+
+const (
+	tokenIssuer = "library"
+
+	accessToken  = "access"
+	refreshToken = "refresh"
+
+	minPasswordLength = 8
+	// bcrypt ignores everything after the first 72 bytes of a password.
+	maxPasswordLength = 72
+)
+
+// dummyHash is compared against when a login names an unknown email, so that
+// the answer takes as long as for a known one.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+// tokenClaims are the claims of the tokens issued on login. The subject is
+// the user ID and Type tells access tokens from refresh tokens. Role is the
+// role of the user when the token was issued.
+type tokenClaims struct {
+	Type string `json:"typ"`
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type AuthService struct {
+	users repository.Users
+	cfg   Config
+}
+
+func NewAuthService(users repository.Users, cfg Config) Auth {
+	return &AuthService{users: users, cfg: cfg}
+}
+
+// Register creates a user who can log in with password.
+func (s *AuthService) Register(ctx context.Context, user models.User, password string) error {
+	user.Name = strings.TrimSpace(user.Name)
+	if user.Name == "" {
+		return invalid("name_required", "name is required")
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(user.Email))
+	if err != nil || address.Name != "" {
+		return invalid("invalid_email", "%q is not a valid email address", user.Email)
+	}
+	user.Email = normalizeEmail(address.Address)
+
+	if len(password) < minPasswordLength {
+		return invalid("password_too_short", "password must be at least %d characters", minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return invalid("password_too_long", "password must be at most %d bytes", maxPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hash)
+	user.LoanPolicyID = nil
+	user.Role = models.RolePatron
+
+	return translateUserWrite(s.users.Create(ctx, user), user)
+}
+
+// Login checks the password of the user with email and issues their tokens.
+func (s *AuthService) Login(ctx context.Context, email, password string) (models.TokenPair, error) {
+	user, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return models.TokenPair{}, err
+	}
+
+	registered := err == nil && user.PasswordHash != ""
+	hash := dummyHash
+	if registered {
+		hash = []byte(user.PasswordHash)
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || !registered {
+		return models.TokenPair{}, unauthenticated("invalid_credentials", "wrong email or password")
+	}
+
+	return s.issue(user)
+}
+
+// Refresh issues a new pair of tokens for a valid refresh token, as long as
+// its user still exists. The new tokens carry the current role of the user.
+func (s *AuthService) Refresh(ctx context.Context, token string) (models.TokenPair, error) {
+	identity, err := s.parse(token, refreshToken)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	user, err := s.users.GetByID(ctx, identity.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.TokenPair{}, unauthenticated("invalid_token", "user of the token no longer exists")
+		}
+		return models.TokenPair{}, err
+	}
+	return s.issue(user)
+}
+
+// Authenticate tells who a request with the access token is made by.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (models.Identity, error) {
+	return s.parse(token, accessToken)
+}
+
+func (s *AuthService) issue(user models.User) (models.TokenPair, error) {
+	access, err := s.sign(user, accessToken, s.cfg.AccessTokenTTL)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	refresh, err := s.sign(user, refreshToken, s.cfg.RefreshTokenTTL)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	return models.TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: s.cfg.AccessTokenTTL}, nil
+}
+
+func (s *AuthService) sign(user models.User, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		Type: tokenType,
+		Role: user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.Itoa(user.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.cfg.TokenSecret)
+}
+
+// parse verifies the signature, expiry and type of token and returns who it
+// was issued to.
+func (s *AuthService) parse(token, tokenType string) (models.Identity, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.cfg.TokenSecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return models.Identity{}, unauthenticated("token_expired", "%s token has expired", tokenType)
+	case err != nil || claims.Type != tokenType || !policy.ValidRole(claims.Role):
+		return models.Identity{}, unauthenticated("invalid_token", "%s token is not valid", tokenType)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return models.Identity{}, unauthenticated("invalid_token", "%s token is not valid", tokenType)
+	}
+	return models.Identity{UserID: userID, Role: claims.Role}, nil
+}
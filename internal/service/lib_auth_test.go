@@ -0,0 +1,208 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var authConfig = service.Config{
+	TokenSecret:     []byte("test secret"),
+	AccessTokenTTL:  15 * time.Minute,
+	RefreshTokenTTL: 24 * time.Hour,
+}
+
+func hashPassword(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return string(hash)
+}
+
+func TestAuthService_Register(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewAuthService(mockUsers, authConfig)
+
+	var created models.User
+	mockUsers.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user models.User) error {
+		created = user
+		return nil
+	})
+
+	err := s.Register(context.Background(), models.User{Name: " Ada ", Email: " Ada@Example.com", Role: models.RoleAdmin}, "correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, "Ada", created.Name)
+	assert.Equal(t, "ada@example.com", created.Email)
+	assert.Equal(t, models.RolePatron, created.Role)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.PasswordHash), []byte("correct horse")))
+}
+
+func TestAuthService_Register_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     models.User
+		password string
+		code     string
+	}{
+		{"no name", models.User{Email: "ada@example.com"}, "correct horse", "name_required"},
+		{"bad email", models.User{Name: "Ada", Email: "ada"}, "correct horse", "invalid_email"},
+		{"short password", models.User{Name: "Ada", Email: "ada@example.com"}, "horse", "password_too_short"},
+		{"long password", models.User{Name: "Ada", Email: "ada@example.com"}, strings.Repeat("horse", 15), "password_too_long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := service.NewAuthService(repository.NewMockUsers(ctrl), authConfig)
+
+			err := s.Register(context.Background(), tt.user, tt.password)
+			assert.ErrorIs(t, err, service.ErrValidation)
+			var domainErr *service.Error
+			if assert.ErrorAs(t, err, &domainErr) {
+				assert.Equal(t, tt.code, domainErr.Code)
+			}
+		})
+	}
+}
+
+func TestAuthService_Register_EmailTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewAuthService(mockUsers, authConfig)
+
+	mockUsers.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrDuplicate)
+
+	err := s.Register(context.Background(), models.User{Name: "Ada", Email: "ada@example.com"}, "correct horse")
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, "a user with email ada@example.com already exists")
+}
+
+func TestAuthService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewAuthService(mockUsers, authConfig)
+
+	mockUsers.EXPECT().GetByEmail(gomock.Any(), "ada@example.com").
+		Return(models.User{ID: 4, Email: "ada@example.com", Role: models.RoleLibrarian, PasswordHash: hashPassword(t, "correct horse")}, nil)
+
+	tokens, err := s.Login(context.Background(), "ada@example.com", "correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, tokens.ExpiresIn)
+
+	identity, err := s.Authenticate(context.Background(), tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Identity{UserID: 4, Role: models.RoleLibrarian}, identity)
+}
+
+func TestAuthService_Login_WrongCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     models.User
+		err      error
+		password string
+	}{
+		{"wrong password", models.User{ID: 4, PasswordHash: hashPassword(t, "correct horse")}, nil, "wrong"},
+		{"unknown email", models.User{}, repository.ErrNotFound, "not a password"},
+		{"never registered", models.User{ID: 4}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUsers := repository.NewMockUsers(ctrl)
+			s := service.NewAuthService(mockUsers, authConfig)
+
+			mockUsers.EXPECT().GetByEmail(gomock.Any(), "ada@example.com").Return(tt.user, tt.err)
+
+			_, err := s.Login(context.Background(), "ada@example.com", tt.password)
+			assert.ErrorIs(t, err, service.ErrUnauthenticated)
+			assert.EqualError(t, err, "wrong email or password")
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewAuthService(mockUsers, authConfig)
+
+	mockUsers.EXPECT().GetByEmail(gomock.Any(), "ada@example.com").
+		Return(models.User{ID: 4, Role: models.RolePatron, PasswordHash: hashPassword(t, "correct horse")}, nil)
+	tokens, err := s.Login(context.Background(), "ada@example.com", "correct horse")
+	assert.NoError(t, err)
+
+	// the user was made a librarian since logging in
+	mockUsers.EXPECT().GetByID(gomock.Any(), 4).Return(models.User{ID: 4, Role: models.RoleLibrarian}, nil)
+	refreshed, err := s.Refresh(context.Background(), tokens.RefreshToken)
+	assert.NoError(t, err)
+
+	identity, err := s.Authenticate(context.Background(), refreshed.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Identity{UserID: 4, Role: models.RoleLibrarian}, identity)
+
+	mockUsers.EXPECT().GetByID(gomock.Any(), 4).Return(models.User{}, repository.ErrNotFound)
+	_, err = s.Refresh(context.Background(), tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrUnauthenticated)
+}
+
+func TestAuthService_Authenticate_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	mockUsers.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).
+		Return(models.User{ID: 4, Role: models.RolePatron, PasswordHash: hashPassword(t, "correct horse")}, nil).AnyTimes()
+
+	s := service.NewAuthService(mockUsers, authConfig)
+	tokens, err := s.Login(context.Background(), "ada@example.com", "correct horse")
+	assert.NoError(t, err)
+
+	expired := authConfig
+	expired.AccessTokenTTL = -time.Minute
+	expiredTokens, err := service.NewAuthService(mockUsers, expired).Login(context.Background(), "ada@example.com", "correct horse")
+	assert.NoError(t, err)
+
+	otherSecret := authConfig
+	otherSecret.TokenSecret = []byte("other secret")
+	forgedTokens, err := service.NewAuthService(mockUsers, otherSecret).Login(context.Background(), "ada@example.com", "correct horse")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		code  string
+	}{
+		{"refresh token", tokens.RefreshToken, "invalid_token"},
+		{"expired", expiredTokens.AccessToken, "token_expired"},
+		{"other secret", forgedTokens.AccessToken, "invalid_token"},
+		{"garbage", "not a token", "invalid_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authenticate(context.Background(), tt.token)
+			assert.ErrorIs(t, err, service.ErrUnauthenticated)
+			var domainErr *service.Error
+			if assert.ErrorAs(t, err, &domainErr) {
+				assert.Equal(t, tt.code, domainErr.Code)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"library/internal/repository"
 	"library/models"
 )
@@ -13,22 +14,52 @@ func NewAuthorsService(repo repository.Authors) *AuthorService {
 	return &AuthorService{repo: repo}
 }
 
-func (s *AuthorService) GetAll() ([]models.Author, error) {
-	return s.repo.GetAll()
+func (s *AuthorService) GetAll(ctx context.Context, query models.AuthorQuery) (models.AuthorPage, error) {
+	if err := checkSort(query.Sort, "id", "name"); err != nil {
+		return models.AuthorPage{}, err
+	}
+	query.Page, query.PageSize = pageBounds(query.Page, query.PageSize)
+
+	authors, total, err := s.repo.GetAll(ctx, query)
+	if err != nil {
+		return models.AuthorPage{}, err
+	}
+
+	return models.AuthorPage{
+		Authors:  authors,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+func (s *AuthorService) Create(ctx context.Context, author models.Author) error {
+	return translate(s.repo.Create(ctx, author), "author")
+}
+
+func (s *AuthorService) GetByID(ctx context.Context, id int) (models.Author, error) {
+	author, err := s.repo.GetByID(ctx, id)
+	return author, translate(err, "author")
 }
 
-func (s *AuthorService) Create(author models.Author) error {
-	return s.repo.Create(author)
+// GetBooks lists the books an author contributed to, with their role.
+func (s *AuthorService) GetBooks(ctx context.Context, id int) ([]models.BookContributor, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, translate(err, "author")
+	}
+	return s.repo.GetBooks(ctx, id)
 }
 
-func (s *AuthorService) GetByID(id int) (models.Author, error) {
-	return s.repo.GetByID(id)
+// Delete archives an author and the books they contributed to.
+func (s *AuthorService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "author")
 }
 
-func (s *AuthorService) Delete(id int) error {
-	return s.repo.Delete(id)
+// Restore brings back an archived author and the books archived with them.
+func (s *AuthorService) Restore(ctx context.Context, id int) error {
+	return translate(s.repo.Restore(ctx, id), "archived author")
 }
 
-func (s *AuthorService) Update(author models.Author) error {
-	return s.repo.Update(author)
+func (s *AuthorService) Update(ctx context.Context, author models.Author) error {
+	return translate(s.repo.Update(ctx, author), "author")
 }
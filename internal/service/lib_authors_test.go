@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorService_GetBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthors := repository.NewMockAuthors(ctrl)
+	s := service.NewAuthorsService(mockAuthors)
+
+	credits := []models.BookContributor{
+		{BookID: 1, AuthorID: 7, Role: models.RoleAuthor, Position: 1, Book: models.Book{ID: 1, Title: "Book 1"}},
+		{BookID: 2, AuthorID: 7, Role: models.RoleTranslator, Position: 2, Book: models.Book{ID: 2, Title: "Book 2"}},
+	}
+	mockAuthors.EXPECT().GetByID(gomock.Any(), 7).Return(models.Author{ID: 7, Name: "Author"}, nil)
+	mockAuthors.EXPECT().GetBooks(gomock.Any(), 7).Return(credits, nil)
+
+	books, err := s.GetBooks(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, credits, books)
+}
+
+func TestAuthorService_GetBooks_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthors := repository.NewMockAuthors(ctrl)
+	s := service.NewAuthorsService(mockAuthors)
+
+	mockAuthors.EXPECT().GetByID(gomock.Any(), 7).Return(models.Author{}, repository.ErrNotFound)
+
+	_, err := s.GetBooks(context.Background(), 7)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.EqualError(t, err, "author not found")
+}
+
+func TestAuthorService_Restore_NotArchived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthors := repository.NewMockAuthors(ctrl)
+	s := service.NewAuthorsService(mockAuthors)
+
+	mockAuthors.EXPECT().Restore(gomock.Any(), 7).Return(repository.ErrNotFound)
+
+	err := s.Restore(context.Background(), 7)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.EqualError(t, err, "archived author not found")
+}
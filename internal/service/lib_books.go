@@ -1,42 +1,421 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"library/internal/isbn"
 	"library/internal/repository"
 	"library/models"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// LoanLimitError is returned when a rental would take a user over the number
+// of concurrent loans allowed by their loan policy.
+type LoanLimitError struct {
+	UserID int
+	Policy string
+	Limit  int
+	Loans  int
+}
+
+func (e *LoanLimitError) Error() string {
+	return fmt.Sprintf("user %d already has %d of %d loans allowed by the %q policy", e.UserID, e.Loans, e.Limit, e.Policy)
+}
+
+func (e *LoanLimitError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// FinesOwedError is returned when a user owes more in fines than the
+// threshold up to which they may still rent.
+type FinesOwedError struct {
+	UserID    int
+	Balance   int64
+	Threshold int64
+}
+
+func (e *FinesOwedError) Error() string {
+	return fmt.Sprintf("user %d owes %s in fines, more than the %s allowed to rent", e.UserID, formatCents(e.Balance), formatCents(e.Threshold))
+}
+
+func (e *FinesOwedError) Is(target error) bool {
+	return target == ErrConflict
+}
+
 type BookService struct {
-	repo repository.Books
+	repo   repository.Books
+	copies repository.Copies
+	holds  repository.Holds
+	users  repository.Users
+	fines  repository.Fines
+	tx     repository.Transactor
+	cfg    Config
+}
+
+func NewBooksService(repo repository.Books, copies repository.Copies, holds repository.Holds, users repository.Users, fines repository.Fines, tx repository.Transactor, cfg Config) Books {
+	return &BookService{repo: repo, copies: copies, holds: holds, users: users, fines: fines, tx: tx, cfg: cfg}
+}
+
+func (s *BookService) GetAll(ctx context.Context, query models.BookQuery) (models.BookPage, error) {
+	if err := checkSort(query.Sort, "id", "title", "published_at"); err != nil {
+		return models.BookPage{}, err
+	}
+	if query.PublishedFrom != nil && query.PublishedTo != nil && query.PublishedFrom.After(*query.PublishedTo) {
+		return models.BookPage{}, invalid("invalid_date_range", "published_from is after published_to")
+	}
+	query.Tag = strings.ToLower(strings.TrimSpace(query.Tag))
+	query.Page, query.PageSize = pageBounds(query.Page, query.PageSize)
+
+	books, total, err := s.repo.GetAll(ctx, query)
+	if err != nil {
+		return models.BookPage{}, err
+	}
+	for i := range books {
+		books[i].ISBN = displayISBN(books[i].ISBN)
+	}
+
+	return models.BookPage{
+		Books:    books,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// Search finds books by words of their title or author name, or by ISBN.
+func (s *BookService) Search(ctx context.Context, query models.SearchQuery) (models.SearchPage, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return models.SearchPage{}, invalid("query_required", "search query is required")
+	}
+	if utf8.RuneCountInString(query.Text) > maxSearchLength {
+		return models.SearchPage{}, invalid("query_too_long", "search query is longer than %d characters", maxSearchLength)
+	}
+	query.Page, query.PageSize = pageBounds(query.Page, query.PageSize)
+
+	hits, total, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return models.SearchPage{}, err
+	}
+	for i := range hits {
+		hits[i].ISBN = displayISBN(hits[i].ISBN)
+	}
+
+	return models.SearchPage{
+		Hits:     hits,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+func (s *BookService) Create(ctx context.Context, book models.Book) error {
+	if err := checkISBN(&book); err != nil {
+		return err
+	}
+	if err := checkContributors(book.Contributors); err != nil {
+		return err
+	}
+	if err := checkClassification(&book); err != nil {
+		return err
+	}
+	return translateBookWrite(s.repo.Create(ctx, book), book)
+}
+
+func (s *BookService) GetByID(ctx context.Context, id int) (models.Book, error) {
+	book, err := s.repo.GetByID(ctx, id)
+	book.ISBN = displayISBN(book.ISBN)
+	return book, translate(err, "book")
+}
+
+// Delete archives a book.
+func (s *BookService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "book")
+}
+
+func (s *BookService) Restore(ctx context.Context, id int) error {
+	return translate(s.repo.Restore(ctx, id), "archived book")
+}
+
+func (s *BookService) Update(ctx context.Context, book models.Book) error {
+	if err := checkISBN(&book); err != nil {
+		return err
+	}
+	if err := checkContributors(book.Contributors); err != nil {
+		return err
+	}
+	if err := checkClassification(&book); err != nil {
+		return err
+	}
+	return translateBookWrite(s.repo.Update(ctx, book), book)
+}
+
+// checkISBN validates the ISBN of a book and brings it into the stored
+// form: ISBN-13 digits without hyphens.
+func checkISBN(book *models.Book) error {
+	normalized, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		return invalid("invalid_isbn", "%q is not a valid ISBN: %s", book.ISBN, err)
+	}
+	book.ISBN = normalized
+	return nil
 }
 
-func NewBooksService(repo repository.Books) Books {
-	return &BookService{repo: repo}
+// displayISBN hyphenates a stored ISBN. ISBNs recorded before they were
+// validated are shown as they are.
+func displayISBN(stored string) string {
+	if formatted, err := isbn.Format(stored); err == nil {
+		return formatted
+	}
+	return stored
 }
 
-func (s *BookService) GetAll() ([]models.Book, error) {
-	return s.repo.GetAll()
+var contributorRoles = map[string]bool{
+	models.RoleAuthor:      true,
+	models.RoleEditor:      true,
+	models.RoleTranslator:  true,
+	models.RoleIllustrator: true,
 }
 
-func (s *BookService) Create(book models.Book) error {
-	return s.repo.Create(book)
+// checkContributors validates the credits of a book and numbers them in the
+// order given. A contributor without a role is credited as an author.
+func checkContributors(contributors []models.BookContributor) error {
+	if len(contributors) == 0 {
+		return invalid("contributors_required", "a book needs at least one contributor")
+	}
+
+	type credit struct {
+		authorID int
+		role     string
+	}
+	seen := make(map[credit]bool, len(contributors))
+	for i := range contributors {
+		contributor := &contributors[i]
+		if contributor.Role == "" {
+			contributor.Role = models.RoleAuthor
+		}
+		if !contributorRoles[contributor.Role] {
+			return invalid("invalid_role", "unknown contributor role %q", contributor.Role)
+		}
+		key := credit{contributor.AuthorID, contributor.Role}
+		if seen[key] {
+			return invalid("duplicate_contributor", "author %d is credited as %s twice", contributor.AuthorID, contributor.Role)
+		}
+		seen[key] = true
+		contributor.Position = i + 1
+	}
+	return nil
 }
 
-func (s *BookService) GetByID(id int) (models.Book, error) {
-	return s.repo.GetByID(id)
+// checkClassification drops repeated subjects and tags from a book and
+// normalizes its tag names.
+func checkClassification(book *models.Book) error {
+	var subjects []models.Subject
+	seenSubjects := make(map[int]bool, len(book.Subjects))
+	for _, subject := range book.Subjects {
+		if !seenSubjects[subject.ID] {
+			seenSubjects[subject.ID] = true
+			subjects = append(subjects, models.Subject{ID: subject.ID})
+		}
+	}
+
+	var tags []models.Tag
+	seenTags := make(map[string]bool, len(book.Tags))
+	for _, tag := range book.Tags {
+		name, err := normalizeTag(tag.Name)
+		if err != nil {
+			return err
+		}
+		if !seenTags[name] {
+			seenTags[name] = true
+			tags = append(tags, models.Tag{Name: name})
+		}
+	}
+
+	book.Subjects, book.Tags = subjects, tags
+	return nil
 }
 
-func (s *BookService) Delete(id int) error {
-	return s.repo.Delete(id)
+func translateBookWrite(err error, book models.Book) error {
+	switch {
+	case errors.Is(err, repository.ErrUnknownSubject):
+		return invalid("unknown_subject", "a subject of the book does not exist")
+	case errors.Is(err, repository.ErrDuplicate):
+		return conflict("isbn_taken", "a book with ISBN %s already exists", book.ISBN)
+	case errors.Is(err, repository.ErrForeignKey):
+		return invalid("unknown_author", "a contributor of the book is not a known author")
+	}
+	return translate(err, "book")
 }
 
-func (s *BookService) Update(book models.Book) error {
-	return s.repo.Update(book)
+// RentBook lends a book once the user is found within the limits of their
+// loan policy and the fines they may owe. The user is locked meanwhile, so
+// rentals of theirs at the same time cannot both pass the checks.
+func (s *BookService) RentBook(ctx context.Context, userID, bookID, copyID int) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.users.Lock(ctx, userID)
+		if err != nil {
+			return translate(err, "user")
+		}
+		policy := s.policyOf(user)
+
+		loans, err := s.repo.CountOpenLoans(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if loans >= policy.MaxLoans {
+			return &LoanLimitError{UserID: userID, Policy: policy.Name, Limit: policy.MaxLoans, Loans: loans}
+		}
+
+		balance, err := s.fines.Balance(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if balance > s.cfg.FineThreshold {
+			return &FinesOwedError{UserID: userID, Balance: balance, Threshold: s.cfg.FineThreshold}
+		}
+
+		bookID, err := s.bookOf(ctx, bookID, copyID)
+		if err != nil {
+			return err
+		}
+
+		// let expired holds pass to the next patron before deciding who may rent
+		if err := s.holds.ProcessQueue(ctx, bookID, s.cfg.PickupWindow); err != nil {
+			return err
+		}
+		return translate(s.repo.RentBook(ctx, userID, bookID, copyID, policy.LoanPeriod()), "copy")
+	})
 }
 
-func (s *BookService) RentBook(userID, bookID int) error {
-	return s.repo.RentBook(userID, bookID)
+// ReturnBook closes the loan, fines it if it is late and passes the copy on
+// to the hold queue, all or nothing: a loan is never closed without its fine.
+func (s *BookService) ReturnBook(ctx context.Context, userID, bookID, copyID int) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		rentedBook, err := s.repo.ReturnBook(ctx, userID, bookID, copyID)
+		if err != nil {
+			return translate(err, "loan")
+		}
+
+		if fine, ok := s.fineFor(rentedBook); ok {
+			if err := s.fines.Create(ctx, fine); err != nil {
+				return err
+			}
+		}
+
+		return s.holds.ProcessQueue(ctx, rentedBook.BookID, s.cfg.PickupWindow)
+	})
 }
 
-func (s *BookService) ReturnBook(userID, bookID int) error {
-	return s.repo.ReturnBook(userID, bookID)
+// RenewBook extends a loan, unless another patron is waiting for a copy of
+// the book. Free copies are set aside for the queue first, so the holds
+// still waiting are those no copy is left for.
+func (s *BookService) RenewBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error) {
+	policy, err := s.policyFor(ctx, userID)
+	if err != nil {
+		return models.RentedBook{}, err
+	}
+
+	var rentedBook models.RentedBook
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		bookID, err := s.bookOf(ctx, bookID, copyID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.holds.ProcessQueue(ctx, bookID, s.cfg.PickupWindow); err != nil {
+			return err
+		}
+		holds, err := s.holds.GetByBook(ctx, bookID)
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if hold.Status == models.HoldWaiting && hold.UserID != userID {
+				return conflict("on_hold", "book is on hold for another user")
+			}
+		}
+
+		rentedBook, err = s.repo.RenewBook(ctx, userID, bookID, copyID, policy.LoanPeriod(), policy.MaxRenewals)
+		return translate(err, "loan")
+	})
+	return rentedBook, err
+}
+
+func (s *BookService) GetOverdue(ctx context.Context) ([]models.RentedBook, error) {
+	return s.repo.GetOverdue(ctx)
+}
+
+func (s *BookService) GetLoans(ctx context.Context, query models.LoanQuery) (models.LoanPage, error) {
+	switch query.Status {
+	case "", models.LoanOpen, models.LoanReturned:
+	default:
+		return models.LoanPage{}, invalid("invalid_status", "unknown loan status %q", query.Status)
+	}
+	query.Page, query.PageSize = pageBounds(query.Page, query.PageSize)
+
+	loans, total, err := s.repo.GetLoans(ctx, query)
+	if err != nil {
+		return models.LoanPage{}, err
+	}
+
+	return models.LoanPage{
+		Loans:    loans,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// bookOf is bookID, or the book of copyID when bookID is 0.
+func (s *BookService) bookOf(ctx context.Context, bookID, copyID int) (int, error) {
+	if bookID != 0 {
+		return bookID, nil
+	}
+	bookCopy, err := s.copies.GetByID(ctx, copyID)
+	if err != nil {
+		return 0, translate(err, "copy")
+	}
+	return bookCopy.BookID, nil
+}
+
+func (s *BookService) policyFor(ctx context.Context, userID int) (models.LoanPolicy, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return models.LoanPolicy{}, translate(err, "user")
+	}
+	return s.policyOf(user), nil
+}
+
+// policyOf is the loan policy of user, the default one unless they are
+// assigned another.
+func (s *BookService) policyOf(user models.User) models.LoanPolicy {
+	if user.LoanPolicyID == nil {
+		return s.cfg.DefaultPolicy
+	}
+	return user.LoanPolicy
+}
+
+// fineFor computes the fine for a returned loan, if it came back late.
+func (s *BookService) fineFor(rentedBook models.RentedBook) (models.Fine, bool) {
+	if rentedBook.ReturnedAt == nil || !rentedBook.ReturnedAt.After(rentedBook.DueAt) || s.cfg.FineDailyRate <= 0 {
+		return models.Fine{}, false
+	}
+
+	day := 24 * time.Hour
+	daysLate := int((rentedBook.ReturnedAt.Sub(rentedBook.DueAt) + day - 1) / day)
+	amount := int64(daysLate) * s.cfg.FineDailyRate
+	if s.cfg.FineCap > 0 && amount > s.cfg.FineCap {
+		amount = s.cfg.FineCap
+	}
+
+	return models.Fine{
+		UserID:       rentedBook.UserID,
+		RentedBookID: rentedBook.ID,
+		DaysLate:     daysLate,
+		Amount:       amount,
+	}, true
 }
@@ -0,0 +1,508 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = service.Config{
+	DefaultPolicy: models.LoanPolicy{Name: "default", MaxLoans: 5, LoanDays: 14, MaxRenewals: 2},
+	PickupWindow:  3 * 24 * time.Hour,
+	FineDailyRate: 25,
+	FineCap:       1000,
+	FineThreshold: 500,
+}
+
+// transactor is a Transactor that runs what it is given, as though every
+// transaction committed.
+func transactor(ctrl *gomock.Controller) repository.Transactor {
+	mockTransactor := repository.NewMockTransactor(ctrl)
+	mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	return mockTransactor
+}
+
+func TestBookService_RentBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, mockFines, transactor(ctrl), testConfig)
+
+	gomock.InOrder(
+		mockUsers.EXPECT().Lock(gomock.Any(), 1).Return(models.User{ID: 1}, nil),
+		mockBooks.EXPECT().CountOpenLoans(gomock.Any(), 1).Return(4, nil),
+		mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(500), nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil),
+		mockBooks.EXPECT().RentBook(gomock.Any(), 1, 2, 0, 14*24*time.Hour).Return(nil),
+	)
+
+	assert.NoError(t, s.RentBook(context.Background(), 1, 2, 0))
+}
+
+func TestBookService_RentBook_ByCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockCopies := repository.NewMockCopies(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, mockCopies, mockHolds, mockUsers, mockFines, transactor(ctrl), testConfig)
+
+	mockUsers.EXPECT().Lock(gomock.Any(), 1).Return(models.User{ID: 1}, nil)
+	mockBooks.EXPECT().CountOpenLoans(gomock.Any(), 1).Return(0, nil)
+	mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(0), nil)
+	gomock.InOrder(
+		mockCopies.EXPECT().GetByID(gomock.Any(), 7).Return(models.BookCopy{ID: 7, BookID: 2}, nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil),
+		mockBooks.EXPECT().RentBook(gomock.Any(), 1, 2, 7, 14*24*time.Hour).Return(nil),
+	)
+
+	assert.NoError(t, s.RentBook(context.Background(), 1, 0, 7))
+}
+
+func TestBookService_RentBook_UserPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, mockFines, transactor(ctrl), testConfig)
+
+	policyID := 2
+	user := models.User{
+		ID:           1,
+		LoanPolicyID: &policyID,
+		LoanPolicy:   models.LoanPolicy{ID: policyID, Name: "staff", MaxLoans: 20, LoanDays: 28, MaxRenewals: 5},
+	}
+
+	mockUsers.EXPECT().Lock(gomock.Any(), 1).Return(user, nil)
+	mockBooks.EXPECT().CountOpenLoans(gomock.Any(), 1).Return(10, nil)
+	mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(0), nil)
+	mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil)
+	mockBooks.EXPECT().RentBook(gomock.Any(), 1, 2, 0, 28*24*time.Hour).Return(nil)
+
+	assert.NoError(t, s.RentBook(context.Background(), 1, 2, 0))
+}
+
+func TestBookService_RentBook_OverLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	mockTransactor := repository.NewMockTransactor(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, mockFines, mockTransactor, testConfig)
+
+	// the user is locked and their loans counted in the transaction that
+	// would lend the book, so two rentals cannot both pass the limit
+	txCtx := context.WithValue(context.Background(), txMarker{}, true)
+	mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(txCtx)
+		})
+	gomock.InOrder(
+		mockUsers.EXPECT().Lock(txCtx, 1).Return(models.User{ID: 1}, nil),
+		mockBooks.EXPECT().CountOpenLoans(txCtx, 1).Return(5, nil),
+	)
+
+	err := s.RentBook(context.Background(), 1, 2, 0)
+	assert.Equal(t, &service.LoanLimitError{UserID: 1, Policy: "default", Limit: 5, Loans: 5}, err)
+	assert.EqualError(t, err, `user 1 already has 5 of 5 loans allowed by the "default" policy`)
+}
+
+func TestBookService_ReturnBook_ProcessesHoldQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, repository.NewMockUsers(ctrl), mockFines, transactor(ctrl), testConfig)
+
+	returnedAt := time.Now()
+	rental := models.RentedBook{ID: 1, UserID: 1, BookID: 2, DueAt: returnedAt.Add(time.Hour), ReturnedAt: &returnedAt}
+
+	gomock.InOrder(
+		mockBooks.EXPECT().ReturnBook(gomock.Any(), 1, 2, 0).Return(rental, nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil),
+	)
+
+	assert.NoError(t, s.ReturnBook(context.Background(), 1, 2, 0))
+}
+
+func TestBookService_RentBook_FinesOwed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, mockFines, transactor(ctrl), testConfig)
+
+	mockUsers.EXPECT().Lock(gomock.Any(), 1).Return(models.User{ID: 1}, nil)
+	mockBooks.EXPECT().CountOpenLoans(gomock.Any(), 1).Return(0, nil)
+	mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(525), nil)
+
+	err := s.RentBook(context.Background(), 1, 2, 0)
+	assert.Equal(t, &service.FinesOwedError{UserID: 1, Balance: 525, Threshold: 500}, err)
+	assert.EqualError(t, err, "user 1 owes 5.25 in fines, more than the 5.00 allowed to rent")
+}
+
+func TestBookService_ReturnBook_Late(t *testing.T) {
+	tests := []struct {
+		name     string
+		late     time.Duration
+		daysLate int
+		amount   int64
+	}{
+		{name: "part of a day", late: time.Hour, daysLate: 1, amount: 25},
+		{name: "three days", late: 3 * 24 * time.Hour, daysLate: 3, amount: 75},
+		{name: "capped", late: 60 * 24 * time.Hour, daysLate: 60, amount: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockBooks := repository.NewMockBooks(ctrl)
+			mockHolds := repository.NewMockHolds(ctrl)
+			mockFines := repository.NewMockFines(ctrl)
+			s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, repository.NewMockUsers(ctrl), mockFines, transactor(ctrl), testConfig)
+
+			returnedAt := time.Now()
+			rental := models.RentedBook{ID: 7, UserID: 1, BookID: 2, DueAt: returnedAt.Add(-tt.late), ReturnedAt: &returnedAt}
+
+			gomock.InOrder(
+				mockBooks.EXPECT().ReturnBook(gomock.Any(), 1, 2, 0).Return(rental, nil),
+				mockFines.EXPECT().Create(gomock.Any(), models.Fine{UserID: 1, RentedBookID: 7, DaysLate: tt.daysLate, Amount: tt.amount}).Return(nil),
+				mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil),
+			)
+
+			assert.NoError(t, s.ReturnBook(context.Background(), 1, 2, 0))
+		})
+	}
+}
+
+type txMarker struct{}
+
+func TestBookService_ReturnBook_FineFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockTransactor := repository.NewMockTransactor(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), mockFines, mockTransactor, testConfig)
+
+	returnedAt := time.Now()
+	rental := models.RentedBook{ID: 7, UserID: 1, BookID: 2, DueAt: returnedAt.Add(-48 * time.Hour), ReturnedAt: &returnedAt}
+	fineErr := errors.New("insert failed")
+
+	// the loan and its fine go through the context of one transaction, which
+	// gets the error and so rolls the return back
+	txCtx := context.WithValue(context.Background(), txMarker{}, true)
+	mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			err := fn(txCtx)
+			assert.ErrorIs(t, err, fineErr)
+			return err
+		})
+	gomock.InOrder(
+		mockBooks.EXPECT().ReturnBook(txCtx, 1, 2, 0).Return(rental, nil),
+		mockFines.EXPECT().Create(txCtx, gomock.Any()).Return(fineErr),
+	)
+
+	assert.ErrorIs(t, s.ReturnBook(context.Background(), 1, 2, 0), fineErr)
+}
+
+func TestBookService_RenewBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, mockFines, transactor(ctrl), testConfig)
+
+	copyID := 8
+	expectedRental := models.RentedBook{ID: 1, UserID: 1, BookID: 2, RenewalCount: 1}
+	gomock.InOrder(
+		mockUsers.EXPECT().GetByID(gomock.Any(), 1).Return(models.User{ID: 1}, nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil),
+		// a patron whose copy is set aside already needs this one no more
+		mockHolds.EXPECT().GetByBook(gomock.Any(), 2).Return([]models.Hold{{ID: 1, UserID: 3, BookID: 2, Status: models.HoldReady, CopyID: &copyID}}, nil),
+		mockBooks.EXPECT().RenewBook(gomock.Any(), 1, 2, 0, 14*24*time.Hour, 2).Return(expectedRental, nil),
+	)
+
+	rental, err := s.RenewBook(context.Background(), 1, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRental, rental)
+}
+
+func TestBookService_RenewBook_ByCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockCopies := repository.NewMockCopies(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, mockCopies, mockHolds, mockUsers, repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	expectedRental := models.RentedBook{ID: 1, UserID: 1, BookID: 2, CopyID: 7, RenewalCount: 1}
+	mockUsers.EXPECT().GetByID(gomock.Any(), 1).Return(models.User{ID: 1}, nil)
+	mockCopies.EXPECT().GetByID(gomock.Any(), 7).Return(models.BookCopy{ID: 7, BookID: 2}, nil)
+	mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil)
+	mockHolds.EXPECT().GetByBook(gomock.Any(), 2).Return(nil, nil)
+	mockBooks.EXPECT().RenewBook(gomock.Any(), 1, 2, 7, 14*24*time.Hour, 2).Return(expectedRental, nil)
+
+	rental, err := s.RenewBook(context.Background(), 1, 0, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRental, rental)
+}
+
+func TestBookService_RenewBook_HeldByAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	mockHolds := repository.NewMockHolds(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), mockHolds, mockUsers, repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	mockUsers.EXPECT().GetByID(gomock.Any(), 1).Return(models.User{ID: 1}, nil)
+	mockHolds.EXPECT().ProcessQueue(gomock.Any(), 2, testConfig.PickupWindow).Return(nil)
+	mockHolds.EXPECT().GetByBook(gomock.Any(), 2).Return([]models.Hold{{ID: 1, UserID: 3, BookID: 2, Status: models.HoldWaiting}}, nil)
+
+	_, err := s.RenewBook(context.Background(), 1, 2, 0)
+	assert.EqualError(t, err, "book is on hold for another user")
+}
+
+func TestBookService_GetLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	loans := []models.RentedBook{{ID: 1, UserID: 1, BookID: 2}}
+	mockBooks.EXPECT().GetLoans(gomock.Any(), models.LoanQuery{UserID: 1, Status: models.LoanOpen, Page: 1, PageSize: 20}).Return(loans, int64(41), nil)
+	mockBooks.EXPECT().GetLoans(gomock.Any(), models.LoanQuery{BookID: 2, Page: 3, PageSize: 100}).Return(nil, int64(0), nil)
+
+	page, err := s.GetLoans(context.Background(), models.LoanQuery{UserID: 1, Status: models.LoanOpen})
+	assert.NoError(t, err)
+	assert.Equal(t, models.LoanPage{Loans: loans, Total: 41, Page: 1, PageSize: 20}, page)
+
+	_, err = s.GetLoans(context.Background(), models.LoanQuery{BookID: 2, Page: 3, PageSize: 500})
+	assert.NoError(t, err)
+
+	_, err = s.GetLoans(context.Background(), models.LoanQuery{UserID: 1, Status: "lost"})
+	assert.EqualError(t, err, `unknown loan status "lost"`)
+}
+
+func TestBookService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	books := []models.Book{{ID: 1, Title: "Book 1"}}
+	mockBooks.EXPECT().GetAll(gomock.Any(), models.BookQuery{Title: "book", Sort: "-published_at", Page: 1, PageSize: 20}).Return(books, int64(21), nil)
+
+	page, err := s.GetAll(context.Background(), models.BookQuery{Title: "book", Sort: "-published_at"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.BookPage{Books: books, Total: 21, Page: 1, PageSize: 20}, page)
+}
+
+func TestBookService_GetAll_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewBooksService(repository.NewMockBooks(ctrl), repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	_, err := s.GetAll(context.Background(), models.BookQuery{Sort: "-isbn"})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, `cannot sort by "isbn", expected one of id, title, published_at`)
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(-1, 0, 0)
+	_, err = s.GetAll(context.Background(), models.BookQuery{PublishedFrom: &from, PublishedTo: &to})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "published_from is after published_to")
+}
+
+func TestBookService_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	hits := []models.SearchHit{{BookID: 1, Title: "War and Peace", TitleSnippet: "<mark>War</mark> and Peace"}}
+	mockBooks.EXPECT().Search(gomock.Any(), models.SearchQuery{Text: "war", Page: 1, PageSize: 20}).Return(hits, int64(1), nil)
+
+	page, err := s.Search(context.Background(), models.SearchQuery{Text: "  war "})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SearchPage{Hits: hits, Total: 1, Page: 1, PageSize: 20}, page)
+}
+
+func TestBookService_Search_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewBooksService(repository.NewMockBooks(ctrl), repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	_, err := s.Search(context.Background(), models.SearchQuery{Text: "   "})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "search query is required")
+
+	_, err = s.Search(context.Background(), models.SearchQuery{Text: strings.Repeat("a", 201)})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "search query is longer than 200 characters")
+}
+
+func TestBookService_Create_Contributors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	mockBooks.EXPECT().Create(gomock.Any(), models.Book{Title: "War and Peace", ISBN: "9780306406157", Contributors: []models.BookContributor{
+		{AuthorID: 1, Role: models.RoleAuthor, Position: 1},
+		{AuthorID: 2, Role: models.RoleTranslator, Position: 2},
+		{AuthorID: 3, Role: models.RoleTranslator, Position: 3},
+	}}).Return(nil)
+
+	err := s.Create(context.Background(), models.Book{Title: "War and Peace", ISBN: "9780306406157", Contributors: []models.BookContributor{
+		{AuthorID: 1},
+		{AuthorID: 2, Role: models.RoleTranslator},
+		{AuthorID: 3, Role: models.RoleTranslator, Position: 7},
+	}})
+	assert.NoError(t, err)
+}
+
+func TestBookService_Create_InvalidContributors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewBooksService(repository.NewMockBooks(ctrl), repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	tests := []struct {
+		name         string
+		contributors []models.BookContributor
+		message      string
+	}{
+		{
+			name:    "none",
+			message: "a book needs at least one contributor",
+		},
+		{
+			name:         "unknown role",
+			contributors: []models.BookContributor{{AuthorID: 1, Role: "narrator"}},
+			message:      `unknown contributor role "narrator"`,
+		},
+		{
+			name:         "credited twice",
+			contributors: []models.BookContributor{{AuthorID: 1}, {AuthorID: 1, Role: models.RoleAuthor}},
+			message:      "author 1 is credited as author twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Create(context.Background(), models.Book{Title: "Book", ISBN: "9780306406157", Contributors: tt.contributors})
+			assert.ErrorIs(t, err, service.ErrValidation)
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+}
+
+func TestBookService_Update_Classification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	contributors := []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}
+	mockBooks.EXPECT().Update(gomock.Any(), models.Book{
+		ID:           1,
+		ISBN:         "9780306406157",
+		Contributors: contributors,
+		Subjects:     []models.Subject{{ID: 2}, {ID: 3}},
+		Tags:         []models.Tag{{Name: "classic"}, {Name: "russian"}},
+	}).Return(repository.ErrUnknownSubject)
+
+	err := s.Update(context.Background(), models.Book{
+		ID:           1,
+		ISBN:         "978-0-306-40615-7",
+		Contributors: contributors,
+		Subjects:     []models.Subject{{ID: 2, Name: "Fiction"}, {ID: 3}, {ID: 2}},
+		Tags:         []models.Tag{{Name: "Classic"}, {Name: " russian"}, {Name: "classic "}},
+	})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "a subject of the book does not exist")
+}
+
+func TestBookService_Create_ISBN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	contributors := []models.BookContributor{{AuthorID: 1, Role: models.RoleAuthor, Position: 1}}
+	mockBooks.EXPECT().Create(gomock.Any(), models.Book{Title: "Book", ISBN: "9780306406157", Contributors: contributors}).Return(nil)
+
+	err := s.Create(context.Background(), models.Book{Title: "Book", ISBN: "0-306-40615-2", Contributors: contributors})
+	assert.NoError(t, err)
+
+	err = s.Create(context.Background(), models.Book{Title: "Book", ISBN: "0-306-40615-3", Contributors: contributors})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, `"0-306-40615-3" is not a valid ISBN: ISBN check digit does not match`)
+}
+
+func TestBookService_GetByID_FormatsISBN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBooks := repository.NewMockBooks(ctrl)
+	s := service.NewBooksService(mockBooks, repository.NewMockCopies(ctrl), repository.NewMockHolds(ctrl), repository.NewMockUsers(ctrl), repository.NewMockFines(ctrl), transactor(ctrl), testConfig)
+
+	mockBooks.EXPECT().GetByID(gomock.Any(), 1).Return(models.Book{ID: 1, ISBN: "9780306406157"}, nil)
+	mockBooks.EXPECT().GetByID(gomock.Any(), 2).Return(models.Book{ID: 2, ISBN: "123-4567-890"}, nil)
+
+	book, err := s.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "978-0-306-40615-7", book.ISBN)
+
+	book, err = s.GetByID(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "123-4567-890", book.ISBN)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/models"
+)
+
+var copyConditions = map[string]bool{
+	models.CopyConditionNew:     true,
+	models.CopyConditionGood:    true,
+	models.CopyConditionFair:    true,
+	models.CopyConditionPoor:    true,
+	models.CopyConditionDamaged: true,
+}
+
+type CopyService struct {
+	repo repository.Copies
+}
+
+func NewCopiesService(repo repository.Copies) Copies {
+	return &CopyService{repo: repo}
+}
+
+func (s *CopyService) GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error) {
+	return s.repo.GetByBook(ctx, bookID)
+}
+
+func (s *CopyService) Create(ctx context.Context, bookCopy models.BookCopy) error {
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = models.CopyConditionGood
+	}
+	if err := validateCopy(bookCopy); err != nil {
+		return err
+	}
+	return translateCopyWrite(s.repo.Create(ctx, bookCopy), bookCopy)
+}
+
+func (s *CopyService) GetByID(ctx context.Context, id int) (models.BookCopy, error) {
+	bookCopy, err := s.repo.GetByID(ctx, id)
+	return bookCopy, translate(err, "copy")
+}
+
+func (s *CopyService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "copy")
+}
+
+func (s *CopyService) Update(ctx context.Context, bookCopy models.BookCopy) error {
+	if err := validateCopy(bookCopy); err != nil {
+		return err
+	}
+
+	// a copy always stays with the book it was catalogued under
+	existing, err := s.repo.GetByID(ctx, bookCopy.ID)
+	if err != nil {
+		return translate(err, "copy")
+	}
+	bookCopy.BookID = existing.BookID
+
+	return translateCopyWrite(s.repo.Update(ctx, bookCopy), bookCopy)
+}
+
+func translateCopyWrite(err error, bookCopy models.BookCopy) error {
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return conflict("barcode_taken", "a copy with barcode %s already exists", bookCopy.Barcode)
+	case errors.Is(err, repository.ErrForeignKey):
+		return invalid("unknown_book", "book %d does not exist", bookCopy.BookID)
+	}
+	return translate(err, "copy")
+}
+
+func validateCopy(bookCopy models.BookCopy) error {
+	if bookCopy.Barcode == "" {
+		return invalid("barcode_required", "barcode is required")
+	}
+	if !copyConditions[bookCopy.Condition] {
+		return invalid("invalid_condition", "unknown copy condition %q", bookCopy.Condition)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyService_Create_DefaultsCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopies := repository.NewMockCopies(ctrl)
+	s := service.NewCopiesService(mockCopies)
+
+	mockCopies.EXPECT().Create(gomock.Any(), models.BookCopy{BookID: 1, Barcode: "000000101", Condition: models.CopyConditionGood}).Return(nil)
+
+	assert.NoError(t, s.Create(context.Background(), models.BookCopy{BookID: 1, Barcode: "000000101"}))
+}
+
+func TestCopyService_Create_UnknownCondition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewCopiesService(repository.NewMockCopies(ctrl))
+
+	err := s.Create(context.Background(), models.BookCopy{BookID: 1, Barcode: "000000101", Condition: "soggy"})
+	assert.EqualError(t, err, `unknown copy condition "soggy"`)
+}
+
+func TestCopyService_Update_KeepsBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopies := repository.NewMockCopies(ctrl)
+	s := service.NewCopiesService(mockCopies)
+
+	mockCopies.EXPECT().GetByID(gomock.Any(), 1).Return(models.BookCopy{ID: 1, BookID: 7, Barcode: "000000701"}, nil)
+	mockCopies.EXPECT().Update(gomock.Any(), models.BookCopy{ID: 1, BookID: 7, Barcode: "000000701", ShelfLocation: "B-2", Condition: models.CopyConditionPoor}).Return(nil)
+
+	err := s.Update(context.Background(), models.BookCopy{ID: 1, Barcode: "000000701", ShelfLocation: "B-2", Condition: models.CopyConditionPoor})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"library/internal/repository"
+	"library/models"
+)
+
+type FineService struct {
+	repo  repository.Fines
+	users repository.Users
+	tx    repository.Transactor
+}
+
+func NewFinesService(repo repository.Fines, users repository.Users, tx repository.Transactor) Fines {
+	return &FineService{repo: repo, users: users, tx: tx}
+}
+
+func (s *FineService) GetBalance(ctx context.Context, userID int) (models.FineBalance, error) {
+	balance, err := s.repo.Balance(ctx, userID)
+	if err != nil {
+		return models.FineBalance{}, err
+	}
+
+	fines, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return models.FineBalance{}, err
+	}
+
+	payments, err := s.repo.GetPayments(ctx, userID)
+	if err != nil {
+		return models.FineBalance{}, err
+	}
+
+	return models.FineBalance{
+		UserID:   userID,
+		Balance:  balance,
+		Fines:    fines,
+		Payments: payments,
+	}, nil
+}
+
+// Pay records a payment of at most what the user owes. The user is locked
+// from reading the balance to recording the payment, so payments and
+// waivers at the same time cannot both count on the same balance.
+func (s *FineService) Pay(ctx context.Context, userID int, amount int64, note string) error {
+	if amount <= 0 {
+		return invalid("invalid_amount", "payment amount must be positive")
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.users.Lock(ctx, userID); err != nil {
+			return translate(err, "user")
+		}
+
+		balance, err := s.repo.Balance(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if amount > balance {
+			return invalid("amount_exceeds_balance", "payment of %s exceeds the outstanding balance of %s", formatCents(amount), formatCents(balance))
+		}
+
+		return translate(s.repo.CreatePayment(ctx, models.Payment{UserID: userID, Amount: amount, Note: note}), "payment")
+	})
+}
+
+// Waive waives a fine the user still owes in full. Payments are not made
+// towards a particular fine, so a fine the balance no longer covers has been
+// paid at least in part, and waiving it would leave the user in credit.
+func (s *FineService) Waive(ctx context.Context, id int, reason string) error {
+	if reason == "" {
+		return invalid("reason_required", "a reason is required to waive a fine")
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		fine, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return translate(err, "fine")
+		}
+		if _, err := s.users.Lock(ctx, fine.UserID); err != nil {
+			return translate(err, "user")
+		}
+
+		if fine.WaivedAt == nil {
+			balance, err := s.repo.Balance(ctx, fine.UserID)
+			if err != nil {
+				return err
+			}
+			if balance < fine.Amount {
+				return conflict("fine_paid", "fine of %s is already paid in part, only %s is outstanding", formatCents(fine.Amount), formatCents(balance))
+			}
+		}
+
+		return translate(s.repo.Waive(ctx, id, reason), "fine")
+	})
+}
+
+func formatCents(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
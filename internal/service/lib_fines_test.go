@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFineService_GetBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, transactor(ctrl))
+
+	fines := []models.Fine{{ID: 1, UserID: 1, Amount: 75}}
+	payments := []models.Payment{{ID: 1, UserID: 1, Amount: 25}}
+	mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(50), nil)
+	mockFines.EXPECT().GetByUser(gomock.Any(), 1).Return(fines, nil)
+	mockFines.EXPECT().GetPayments(gomock.Any(), 1).Return(payments, nil)
+
+	balance, err := s.GetBalance(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.FineBalance{UserID: 1, Balance: 50, Fines: fines, Payments: payments}, balance)
+}
+
+func TestFineService_Pay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	mockTransactor := repository.NewMockTransactor(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, mockTransactor)
+
+	// the balance is read and the payment recorded with the user locked in
+	// one transaction
+	txCtx := context.WithValue(context.Background(), txMarker{}, true)
+	mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(txCtx)
+		})
+	gomock.InOrder(
+		mockUsers.EXPECT().Lock(txCtx, 1).Return(models.User{ID: 1}, nil),
+		mockFines.EXPECT().Balance(txCtx, 1).Return(int64(75), nil),
+		mockFines.EXPECT().CreatePayment(txCtx, models.Payment{UserID: 1, Amount: 75, Note: "cash"}).Return(nil),
+	)
+
+	assert.NoError(t, s.Pay(context.Background(), 1, 75, "cash"))
+}
+
+func TestFineService_Pay_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, transactor(ctrl))
+
+	assert.EqualError(t, s.Pay(context.Background(), 1, 0, ""), "payment amount must be positive")
+
+	mockUsers.EXPECT().Lock(gomock.Any(), 1).Return(models.User{ID: 1}, nil)
+	mockFines.EXPECT().Balance(gomock.Any(), 1).Return(int64(75), nil)
+	assert.EqualError(t, s.Pay(context.Background(), 1, 100, ""), "payment of 1.00 exceeds the outstanding balance of 0.75")
+}
+
+func TestFineService_Waive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, transactor(ctrl))
+
+	assert.EqualError(t, s.Waive(context.Background(), 1, ""), "a reason is required to waive a fine")
+
+	gomock.InOrder(
+		mockFines.EXPECT().GetByID(gomock.Any(), 1).Return(models.Fine{ID: 1, UserID: 2, Amount: 75}, nil),
+		mockUsers.EXPECT().Lock(gomock.Any(), 2).Return(models.User{ID: 2}, nil),
+		mockFines.EXPECT().Balance(gomock.Any(), 2).Return(int64(100), nil),
+		mockFines.EXPECT().Waive(gomock.Any(), 1, "damaged return slot").Return(nil),
+	)
+	assert.NoError(t, s.Waive(context.Background(), 1, "damaged return slot"))
+}
+
+func TestFineService_Waive_AfterPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, transactor(ctrl))
+
+	// a 0.75 fine of which 0.50 has been paid: waiving it would leave the
+	// user 0.50 in credit
+	mockFines.EXPECT().GetByID(gomock.Any(), 1).Return(models.Fine{ID: 1, UserID: 2, Amount: 75}, nil)
+	mockUsers.EXPECT().Lock(gomock.Any(), 2).Return(models.User{ID: 2}, nil)
+	mockFines.EXPECT().Balance(gomock.Any(), 2).Return(int64(25), nil)
+
+	err := s.Waive(context.Background(), 1, "damaged return slot")
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, "fine of 0.75 is already paid in part, only 0.25 is outstanding")
+}
+
+func TestFineService_Waive_AlreadyWaived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFines := repository.NewMockFines(ctrl)
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewFinesService(mockFines, mockUsers, transactor(ctrl))
+
+	waivedAt := time.Now()
+	mockFines.EXPECT().GetByID(gomock.Any(), 1).Return(models.Fine{ID: 1, UserID: 2, Amount: 75, WaivedAt: &waivedAt}, nil)
+	mockUsers.EXPECT().Lock(gomock.Any(), 2).Return(models.User{ID: 2}, nil)
+	mockFines.EXPECT().Waive(gomock.Any(), 1, "twice").Return(repository.ErrFineWaived)
+
+	err := s.Waive(context.Background(), 1, "twice")
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, "fine is already waived")
+}
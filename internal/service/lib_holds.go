@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/models"
+)
+
+type HoldService struct {
+	repo repository.Holds
+	tx   repository.Transactor
+	cfg  Config
+}
+
+func NewHoldsService(repo repository.Holds, tx repository.Transactor, cfg Config) Holds {
+	return &HoldService{repo: repo, tx: tx, cfg: cfg}
+}
+
+// Place puts a book on hold once its queue is up to date, so a copy whose
+// pickup window has just passed goes to the patrons already waiting.
+func (s *HoldService) Place(ctx context.Context, userID, bookID int) (models.Hold, error) {
+	var hold models.Hold
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.ProcessQueue(ctx, bookID, s.cfg.PickupWindow); err != nil {
+			return err
+		}
+		var err error
+		hold, err = s.repo.Create(ctx, userID, bookID)
+		return translate(err, "hold")
+	})
+	return hold, err
+}
+
+func (s *HoldService) GetByID(ctx context.Context, id int) (models.Hold, error) {
+	var hold models.Hold
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if hold, err = s.repo.GetByID(ctx, id); err != nil {
+			return translate(err, "hold")
+		}
+		if err := s.repo.ProcessQueue(ctx, hold.BookID, s.cfg.PickupWindow); err != nil {
+			return err
+		}
+		hold, err = s.repo.GetByID(ctx, id)
+		return translate(err, "hold")
+	})
+	return hold, err
+}
+
+func (s *HoldService) GetByBook(ctx context.Context, bookID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.ProcessQueue(ctx, bookID, s.cfg.PickupWindow); err != nil {
+			return err
+		}
+		var err error
+		holds, err = s.repo.GetByBook(ctx, bookID)
+		return err
+	})
+	return holds, err
+}
+
+// GetByUser returns the active holds of a user after bringing the queues of
+// the books they hold up to date.
+func (s *HoldService) GetByUser(ctx context.Context, userID int) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		held, err := s.repo.GetByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, hold := range held {
+			if err := s.repo.ProcessQueue(ctx, hold.BookID, s.cfg.PickupWindow); err != nil {
+				return err
+			}
+		}
+		holds, err = s.repo.GetByUser(ctx, userID)
+		return err
+	})
+	return holds, err
+}
+
+func (s *HoldService) Cancel(ctx context.Context, id int) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		hold, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return translate(err, "hold")
+		}
+		if err := s.repo.Cancel(ctx, id); err != nil {
+			return translate(err, "hold")
+		}
+		// a cancelled ready hold frees the book for the next patron in the queue
+		return s.repo.ProcessQueue(ctx, hold.BookID, s.cfg.PickupWindow)
+	})
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldService_Place(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	expectedHold := models.Hold{ID: 1, UserID: 2, BookID: 3, Status: models.HoldWaiting}
+	gomock.InOrder(
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil),
+		mockHolds.EXPECT().Create(gomock.Any(), 2, 3).Return(expectedHold, nil),
+	)
+
+	hold, err := s.Place(context.Background(), 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHold, hold)
+}
+
+func TestHoldService_Place_BookAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil)
+	mockHolds.EXPECT().Create(gomock.Any(), 2, 3).Return(models.Hold{}, repository.ErrBookAvailable)
+
+	_, err := s.Place(context.Background(), 2, 3)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	var domainErr *service.Error
+	if assert.True(t, errors.As(err, &domainErr)) {
+		assert.Equal(t, "book_available", domainErr.Code)
+	}
+}
+
+func TestHoldService_GetByID_ProcessesItsQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	ready := models.Hold{ID: 1, UserID: 2, BookID: 3, Status: models.HoldReady}
+	gomock.InOrder(
+		mockHolds.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{ID: 1, UserID: 2, BookID: 3, Status: models.HoldWaiting}, nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil),
+		mockHolds.EXPECT().GetByID(gomock.Any(), 1).Return(ready, nil),
+	)
+
+	hold, err := s.GetByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ready, hold)
+}
+
+func TestHoldService_GetByID_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	mockHolds.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{}, repository.ErrNotFound)
+
+	_, err := s.GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.EqualError(t, err, "hold not found")
+}
+
+func TestHoldService_GetByBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	queue := []models.Hold{{ID: 1, BookID: 3, Status: models.HoldReady}, {ID: 2, BookID: 3, Status: models.HoldWaiting}}
+	gomock.InOrder(
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil),
+		mockHolds.EXPECT().GetByBook(gomock.Any(), 3).Return(queue, nil),
+	)
+
+	holds, err := s.GetByBook(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, queue, holds)
+}
+
+func TestHoldService_GetByUser_ProcessesTheirQueues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	held := []models.Hold{{ID: 1, UserID: 2, BookID: 3}, {ID: 4, UserID: 2, BookID: 5}}
+	gomock.InOrder(
+		mockHolds.EXPECT().GetByUser(gomock.Any(), 2).Return(held, nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 5, testConfig.PickupWindow).Return(nil),
+		mockHolds.EXPECT().GetByUser(gomock.Any(), 2).Return(held[1:], nil),
+	)
+
+	holds, err := s.GetByUser(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, held[1:], holds)
+}
+
+func TestHoldService_Cancel_PassesTheCopyOn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	gomock.InOrder(
+		mockHolds.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{ID: 1, BookID: 3, Status: models.HoldReady}, nil),
+		mockHolds.EXPECT().Cancel(gomock.Any(), 1).Return(nil),
+		mockHolds.EXPECT().ProcessQueue(gomock.Any(), 3, testConfig.PickupWindow).Return(nil),
+	)
+
+	assert.NoError(t, s.Cancel(context.Background(), 1))
+}
+
+func TestHoldService_Cancel_NotActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHolds := repository.NewMockHolds(ctrl)
+	s := service.NewHoldsService(mockHolds, transactor(ctrl), testConfig)
+
+	mockHolds.EXPECT().GetByID(gomock.Any(), 1).Return(models.Hold{ID: 1, BookID: 3, Status: models.HoldExpired}, nil)
+	mockHolds.EXPECT().Cancel(gomock.Any(), 1).Return(repository.ErrHoldNotActive)
+
+	err := s.Cancel(context.Background(), 1)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, "hold is not active")
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/models"
+)
+
+type LoanPolicyService struct {
+	repo repository.LoanPolicies
+}
+
+func NewLoanPoliciesService(repo repository.LoanPolicies) LoanPolicies {
+	return &LoanPolicyService{repo: repo}
+}
+
+func (s *LoanPolicyService) GetAll(ctx context.Context) ([]models.LoanPolicy, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *LoanPolicyService) Create(ctx context.Context, policy models.LoanPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+	return translatePolicyWrite(s.repo.Create(ctx, policy), policy)
+}
+
+func (s *LoanPolicyService) GetByID(ctx context.Context, id int) (models.LoanPolicy, error) {
+	policy, err := s.repo.GetByID(ctx, id)
+	return policy, translate(err, "loan policy")
+}
+
+func (s *LoanPolicyService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "loan policy")
+}
+
+func (s *LoanPolicyService) Update(ctx context.Context, policy models.LoanPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+	return translatePolicyWrite(s.repo.Update(ctx, policy), policy)
+}
+
+func translatePolicyWrite(err error, policy models.LoanPolicy) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return conflict("policy_name_taken", "a loan policy named %q already exists", policy.Name)
+	}
+	return translate(err, "loan policy")
+}
+
+func validatePolicy(policy models.LoanPolicy) error {
+	if policy.Name == "" {
+		return invalid("name_required", "policy name is required")
+	}
+	if policy.MaxLoans < 0 || policy.MaxRenewals < 0 {
+		return invalid("invalid_limits", "loan and renewal limits cannot be negative")
+	}
+	if policy.LoanDays <= 0 {
+		return invalid("invalid_loan_days", "loan days must be positive")
+	}
+	return nil
+}
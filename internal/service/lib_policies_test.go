@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoanPolicyService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPolicies := repository.NewMockLoanPolicies(ctrl)
+	s := service.NewLoanPoliciesService(mockPolicies)
+
+	policy := models.LoanPolicy{Name: "student", MaxLoans: 3, LoanDays: 7, MaxRenewals: 1}
+	mockPolicies.EXPECT().Create(gomock.Any(), policy).Return(nil)
+
+	assert.NoError(t, s.Create(context.Background(), policy))
+}
+
+func TestLoanPolicyService_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewLoanPoliciesService(repository.NewMockLoanPolicies(ctrl))
+
+	assert.EqualError(t, s.Create(context.Background(), models.LoanPolicy{MaxLoans: 3, LoanDays: 7}), "policy name is required")
+	assert.EqualError(t, s.Create(context.Background(), models.LoanPolicy{Name: "student", MaxLoans: -1, LoanDays: 7}), "loan and renewal limits cannot be negative")
+	assert.EqualError(t, s.Create(context.Background(), models.LoanPolicy{Name: "student", MaxLoans: 3}), "loan days must be positive")
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/models"
+	"strings"
+)
+
+type SubjectService struct {
+	repo repository.Subjects
+	tx   repository.Transactor
+}
+
+func NewSubjectsService(repo repository.Subjects, tx repository.Transactor) Subjects {
+	return &SubjectService{repo: repo, tx: tx}
+}
+
+// GetAll returns the taxonomy as a tree: the top-level subjects with their
+// descendants nested under Children, each level ordered by name.
+func (s *SubjectService) GetAll(ctx context.Context) ([]models.Subject, error) {
+	subjects, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return subjectTree(subjects), nil
+}
+
+func (s *SubjectService) Create(ctx context.Context, subject models.Subject) error {
+	subject.Name = strings.TrimSpace(subject.Name)
+	if subject.Name == "" {
+		return invalid("name_required", "subject name is required")
+	}
+	return translateSubjectWrite(s.repo.Create(ctx, subject), subject)
+}
+
+func (s *SubjectService) GetByID(ctx context.Context, id int) (models.Subject, error) {
+	subject, err := s.repo.GetByID(ctx, id)
+	return subject, translate(err, "subject")
+}
+
+// Delete removes a subject that has no children and no books filed under it.
+func (s *SubjectService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "subject")
+}
+
+// Update renames a subject or moves it, with everything below it, under
+// another parent. A subject cannot be moved below itself. The subject and
+// the path above its new parent are locked from the check to the move, so
+// that moves at the same time cannot together make a cycle.
+func (s *SubjectService) Update(ctx context.Context, subject models.Subject) error {
+	subject.Name = strings.TrimSpace(subject.Name)
+	if subject.Name == "" {
+		return invalid("name_required", "subject name is required")
+	}
+
+	parentID := 0
+	if subject.ParentID != nil {
+		parentID = *subject.ParentID
+		if parentID == subject.ID {
+			return invalid("subject_cycle", "a subject cannot be its own parent")
+		}
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockPath(ctx, subject.ID, parentID); err != nil {
+			return translate(err, "subject")
+		}
+
+		if parentID != 0 {
+			below, err := s.repo.IsDescendant(ctx, parentID, subject.ID)
+			if err != nil {
+				return err
+			}
+			if below {
+				return invalid("subject_cycle", "subject %d is below subject %d", parentID, subject.ID)
+			}
+		}
+
+		return translateSubjectWrite(s.repo.Update(ctx, subject), subject)
+	})
+}
+
+func translateSubjectWrite(err error, subject models.Subject) error {
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return conflict("subject_name_taken", "a subject named %q already exists there", subject.Name)
+	case errors.Is(err, repository.ErrForeignKey):
+		return invalid("unknown_parent", "parent subject %d does not exist", *subject.ParentID)
+	}
+	return translate(err, "subject")
+}
+
+// subjectTree nests a flat list of subjects under their parents, keeping
+// the order of the list within each level.
+func subjectTree(subjects []models.Subject) []models.Subject {
+	children := make(map[int][]models.Subject)
+	for _, subject := range subjects {
+		parent := 0
+		if subject.ParentID != nil {
+			parent = *subject.ParentID
+		}
+		children[parent] = append(children[parent], subject)
+	}
+
+	var build func(parent int) []models.Subject
+	build = func(parent int) []models.Subject {
+		level := children[parent]
+		for i := range level {
+			level[i].Children = build(level[i].ID)
+		}
+		return level
+	}
+	return build(0)
+}
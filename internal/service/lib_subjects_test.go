@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjects := repository.NewMockSubjects(ctrl)
+	s := service.NewSubjectsService(mockSubjects, transactor(ctrl))
+
+	fiction, fantasy, history := 1, 2, 4
+	mockSubjects.EXPECT().GetAll(gomock.Any()).Return([]models.Subject{
+		{ID: 5, Name: "Ancient", ParentID: &history},
+		{ID: 2, Name: "Fantasy", ParentID: &fiction},
+		{ID: 1, Name: "Fiction"},
+		{ID: 4, Name: "History"},
+		{ID: 3, Name: "High fantasy", ParentID: &fantasy},
+	}, nil)
+
+	subjects, err := s.GetAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Subject{
+		{ID: 1, Name: "Fiction", Children: []models.Subject{
+			{ID: 2, Name: "Fantasy", ParentID: &fiction, Children: []models.Subject{
+				{ID: 3, Name: "High fantasy", ParentID: &fantasy},
+			}},
+		}},
+		{ID: 4, Name: "History", Children: []models.Subject{
+			{ID: 5, Name: "Ancient", ParentID: &history},
+		}},
+	}, subjects)
+}
+
+func TestSubjectService_Update_Cycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjects := repository.NewMockSubjects(ctrl)
+	s := service.NewSubjectsService(mockSubjects, transactor(ctrl))
+
+	self, child := 1, 3
+	err := s.Update(context.Background(), models.Subject{ID: 1, Name: "Fiction", ParentID: &self})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "a subject cannot be its own parent")
+
+	mockSubjects.EXPECT().LockPath(gomock.Any(), 1, 3).Return(nil)
+	mockSubjects.EXPECT().IsDescendant(gomock.Any(), 3, 1).Return(true, nil)
+	err = s.Update(context.Background(), models.Subject{ID: 1, Name: "Fiction", ParentID: &child})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "subject 3 is below subject 1")
+}
+
+func TestSubjectService_Update_Move(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjects := repository.NewMockSubjects(ctrl)
+	mockTransactor := repository.NewMockTransactor(ctrl)
+	s := service.NewSubjectsService(mockSubjects, mockTransactor)
+
+	// the path is locked before the cycle check, and the check and the move
+	// share its transaction
+	txCtx := context.WithValue(context.Background(), txMarker{}, true)
+	mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(txCtx)
+		})
+	parent := 4
+	gomock.InOrder(
+		mockSubjects.EXPECT().LockPath(txCtx, 2, 4).Return(nil),
+		mockSubjects.EXPECT().IsDescendant(txCtx, 4, 2).Return(false, nil),
+		mockSubjects.EXPECT().Update(txCtx, models.Subject{ID: 2, Name: "Fantasy", ParentID: &parent}).Return(nil),
+	)
+
+	err := s.Update(context.Background(), models.Subject{ID: 2, Name: " Fantasy ", ParentID: &parent})
+	assert.NoError(t, err)
+}
+
+func TestSubjectService_Update_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjects := repository.NewMockSubjects(ctrl)
+	s := service.NewSubjectsService(mockSubjects, transactor(ctrl))
+
+	mockSubjects.EXPECT().LockPath(gomock.Any(), 7, 0).Return(repository.ErrNotFound)
+	err := s.Update(context.Background(), models.Subject{ID: 7, Name: "Poetry"})
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestSubjectService_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubjects := repository.NewMockSubjects(ctrl)
+	s := service.NewSubjectsService(mockSubjects, transactor(ctrl))
+
+	err := s.Create(context.Background(), models.Subject{Name: "  "})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "subject name is required")
+
+	parent := 9
+	mockSubjects.EXPECT().Create(gomock.Any(), models.Subject{Name: "Poetry", ParentID: &parent}).Return(repository.ErrForeignKey)
+	err = s.Create(context.Background(), models.Subject{Name: "Poetry", ParentID: &parent})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "parent subject 9 does not exist")
+
+	mockSubjects.EXPECT().Create(gomock.Any(), models.Subject{Name: "Poetry"}).Return(repository.ErrDuplicate)
+	err = s.Create(context.Background(), models.Subject{Name: "Poetry"})
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, `a subject named "Poetry" already exists there`)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/internal/repository"
+	"library/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTagLength caps the length of a tag name.
+const maxTagLength = 50
+
+type TagService struct {
+	repo repository.Tags
+}
+
+func NewTagsService(repo repository.Tags) Tags {
+	return &TagService{repo: repo}
+}
+
+func (s *TagService) GetAll(ctx context.Context) ([]models.Tag, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Delete removes a tag from every book it is on.
+func (s *TagService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "tag")
+}
+
+// Update renames a tag on every book it is on.
+func (s *TagService) Update(ctx context.Context, tag models.Tag) error {
+	name, err := normalizeTag(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+
+	err = s.repo.Update(ctx, tag)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return conflict("tag_name_taken", "a tag named %q already exists", tag.Name)
+	}
+	return translate(err, "tag")
+}
+
+// normalizeTag trims and lower-cases a tag name so that tags differing only
+// in case or surrounding space are the same tag.
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", invalid("invalid_tag", "tag names cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", invalid("invalid_tag", "tag %q is longer than %d characters", name, maxTagLength)
+	}
+	return name, nil
+}
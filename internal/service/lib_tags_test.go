@@ -0,0 +1,43 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTags := repository.NewMockTags(ctrl)
+	s := service.NewTagsService(mockTags)
+
+	mockTags.EXPECT().Update(gomock.Any(), models.Tag{ID: 1, Name: "science fiction"}).Return(nil)
+	assert.NoError(t, s.Update(context.Background(), models.Tag{ID: 1, Name: " Science Fiction "}))
+
+	mockTags.EXPECT().Update(gomock.Any(), models.Tag{ID: 2, Name: "sf"}).Return(repository.ErrDuplicate)
+	err := s.Update(context.Background(), models.Tag{ID: 2, Name: "SF"})
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.EqualError(t, err, `a tag named "sf" already exists`)
+}
+
+func TestTagService_Update_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewTagsService(repository.NewMockTags(ctrl))
+
+	err := s.Update(context.Background(), models.Tag{ID: 1, Name: " "})
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, "tag names cannot be empty")
+
+	err = s.Update(context.Background(), models.Tag{ID: 1, Name: strings.Repeat("x", 51)})
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
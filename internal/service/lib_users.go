@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"library/internal/policy"
 	"library/internal/repository"
 	"library/models"
+	"strings"
 )
 
 type UserService struct {
@@ -13,22 +17,78 @@ func NewUsersService(repo repository.Users) Users {
 	return &UserService{repo: repo}
 }
 
-func (s *UserService) GetAll() ([]models.User, error) {
-	return s.repo.GetAll()
+func (s *UserService) GetAll(ctx context.Context, query models.UserQuery) (models.UserPage, error) {
+	if err := checkSort(query.Sort, "id", "name", "email"); err != nil {
+		return models.UserPage{}, err
+	}
+	query.Page, query.PageSize = pageBounds(query.Page, query.PageSize)
+
+	users, total, err := s.repo.GetAll(ctx, query)
+	if err != nil {
+		return models.UserPage{}, err
+	}
+
+	return models.UserPage{
+		Users:    users,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// Create stores a user with their email lower-cased, as Register does, so
+// that it is unique whatever its case.
+func (s *UserService) Create(ctx context.Context, user models.User) error {
+	if user.Role != "" && !policy.ValidRole(user.Role) {
+		return unknownRole(user.Role)
+	}
+	user.Email = normalizeEmail(user.Email)
+	return translateUserWrite(s.repo.Create(ctx, user), user)
+}
+
+func (s *UserService) GetByID(ctx context.Context, id int) (models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	return user, translate(err, "user")
+}
+
+// Delete archives a user.
+func (s *UserService) Delete(ctx context.Context, id int) error {
+	return translateDelete(s.repo.Delete(ctx, id), "user")
+}
+
+func (s *UserService) Restore(ctx context.Context, id int) error {
+	return translate(s.repo.Restore(ctx, id), "archived user")
+}
+
+func (s *UserService) Update(ctx context.Context, user models.User) error {
+	user.Email = normalizeEmail(user.Email)
+	return translateUserWrite(s.repo.Update(ctx, user), user)
 }
 
-func (s *UserService) Create(user models.User) error {
-	return s.repo.Create(user)
+// SetRole gives a user one of the roles of package policy.
+func (s *UserService) SetRole(ctx context.Context, id int, role string) error {
+	if !policy.ValidRole(role) {
+		return unknownRole(role)
+	}
+	return translate(s.repo.SetRole(ctx, id, role), "user")
 }
 
-func (s *UserService) GetByID(id int) (models.User, error) {
-	return s.repo.GetByID(id)
+// normalizeEmail is the form emails are stored in. Users log in with any
+// case.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
-func (s *UserService) Delete(id int) error {
-	return s.repo.Delete(id)
+func unknownRole(role string) error {
+	return invalid("unknown_role", "unknown role %q, expected one of %s", role, strings.Join(policy.Roles(), ", "))
 }
 
-func (s *UserService) Update(user models.User) error {
-	return s.repo.Update(user)
+func translateUserWrite(err error, user models.User) error {
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return conflict("email_taken", "a user with email %s already exists", user.Email)
+	case errors.Is(err, repository.ErrForeignKey):
+		return invalid("unknown_policy", "loan policy does not exist")
+	}
+	return translate(err, "user")
 }
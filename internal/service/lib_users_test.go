@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"library/internal/repository"
+	"library/internal/service"
+	"library/models"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserService_SetRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewUsersService(mockUsers)
+
+	mockUsers.EXPECT().SetRole(gomock.Any(), 3, models.RoleLibrarian).Return(nil)
+
+	assert.NoError(t, s.SetRole(context.Background(), 3, models.RoleLibrarian))
+}
+
+func TestUserService_SetRole_Unknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewUsersService(repository.NewMockUsers(ctrl))
+
+	err := s.SetRole(context.Background(), 3, "root")
+	assert.ErrorIs(t, err, service.ErrValidation)
+	assert.EqualError(t, err, `unknown role "root", expected one of patron, librarian, admin`)
+}
+
+func TestUserService_SetRole_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewUsersService(mockUsers)
+
+	mockUsers.EXPECT().SetRole(gomock.Any(), 3, models.RoleAdmin).Return(repository.ErrNotFound)
+
+	err := s.SetRole(context.Background(), 3, models.RoleAdmin)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestUserService_Create_LowerCasesEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewUsersService(mockUsers)
+
+	mockUsers.EXPECT().Create(gomock.Any(), models.User{Name: "Bob", Email: "bob@example.com"}).Return(nil)
+
+	assert.NoError(t, s.Create(context.Background(), models.User{Name: "Bob", Email: " Bob@Example.com "}))
+}
+
+func TestUserService_Update_LowerCasesEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewUsersService(mockUsers)
+
+	mockUsers.EXPECT().Update(gomock.Any(), models.User{ID: 3, Name: "Bob", Email: "bob@example.com"}).Return(nil)
+
+	assert.NoError(t, s.Update(context.Background(), models.User{ID: 3, Name: "Bob", Email: "Bob@Example.com"}))
+}
+
+func TestUserService_Create_UnknownRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := service.NewUsersService(repository.NewMockUsers(ctrl))
+
+	err := s.Create(context.Background(), models.User{Name: "Ada", Email: "ada@example.com", Role: "root"})
+	assert.ErrorIs(t, err, service.ErrValidation)
+}
+
+func TestUserService_Delete_OpenLoans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsers := repository.NewMockUsers(ctrl)
+	s := service.NewUsersService(mockUsers)
+
+	mockUsers.EXPECT().Delete(gomock.Any(), 3).Return(repository.ErrOpenLoans)
+
+	err := s.Delete(context.Background(), 3)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	var domainErr *service.Error
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, "open_loans", domainErr.Code)
+	}
+}
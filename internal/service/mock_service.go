@@ -5,12 +5,178 @@
 package service
 
 import (
+	context "context"
 	models "library/models"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockAPIKeys is a mock of APIKeys interface.
+type MockAPIKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysMockRecorder
+}
+
+// MockAPIKeysMockRecorder is the mock recorder for MockAPIKeys.
+type MockAPIKeysMockRecorder struct {
+	mock *MockAPIKeys
+}
+
+// NewMockAPIKeys creates a new mock instance.
+func NewMockAPIKeys(ctrl *gomock.Controller) *MockAPIKeys {
+	mock := &MockAPIKeys{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeys) EXPECT() *MockAPIKeysMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeys) Authenticate(ctx context.Context, secret string) (models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, secret)
+	ret0, _ := ret[0].(models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeysMockRecorder) Authenticate(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeys)(nil).Authenticate), ctx, secret)
+}
+
+// Create mocks base method.
+func (m *MockAPIKeys) Create(ctx context.Context, key models.APIKey) (models.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeysMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeys)(nil).Create), ctx, key)
+}
+
+// GetAll mocks base method.
+func (m *MockAPIKeys) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAPIKeysMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAPIKeys)(nil).GetAll), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeys) Revoke(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeysMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeys)(nil).Revoke), ctx, id)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuth) Authenticate(ctx context.Context, accessToken string) (models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, accessToken)
+	ret0, _ := ret[0].(models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthMockRecorder) Authenticate(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuth)(nil).Authenticate), ctx, accessToken)
+}
+
+// Login mocks base method.
+func (m *MockAuth) Login(ctx context.Context, email, password string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, email, password)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthMockRecorder) Login(ctx, email, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuth)(nil).Login), ctx, email, password)
+}
+
+// Refresh mocks base method.
+func (m *MockAuth) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthMockRecorder) Refresh(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuth)(nil).Refresh), ctx, refreshToken)
+}
+
+// Register mocks base method.
+func (m *MockAuth) Register(ctx context.Context, user models.User, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, user, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockAuthMockRecorder) Register(ctx, user, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuth)(nil).Register), ctx, user, password)
+}
+
 // MockAuthors is a mock of Authors interface.
 type MockAuthors struct {
 	ctrl     *gomock.Controller
@@ -35,75 +201,104 @@ func (m *MockAuthors) EXPECT() *MockAuthorsMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockAuthors) Create(author models.Author) error {
+func (m *MockAuthors) Create(ctx context.Context, author models.Author) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", author)
+	ret := m.ctrl.Call(m, "Create", ctx, author)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockAuthorsMockRecorder) Create(author interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Create(ctx, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthors)(nil).Create), author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthors)(nil).Create), ctx, author)
 }
 
 // Delete mocks base method.
-func (m *MockAuthors) Delete(id int) error {
+func (m *MockAuthors) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockAuthorsMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthors)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthors)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockAuthors) GetAll() ([]models.Author, error) {
+func (m *MockAuthors) GetAll(ctx context.Context, query models.AuthorQuery) (models.AuthorPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]models.Author)
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
+	ret0, _ := ret[0].(models.AuthorPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockAuthorsMockRecorder) GetAll() *gomock.Call {
+func (mr *MockAuthorsMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthors)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuthors)(nil).GetAll), ctx, query)
+}
+
+// GetBooks mocks base method.
+func (m *MockAuthors) GetBooks(ctx context.Context, id int) ([]models.BookContributor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooks", ctx, id)
+	ret0, _ := ret[0].([]models.BookContributor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockAuthorsMockRecorder) GetBooks(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockAuthors)(nil).GetBooks), ctx, id)
 }
 
 // GetByID mocks base method.
-func (m *MockAuthors) GetByID(id int) (models.Author, error) {
+func (m *MockAuthors) GetByID(ctx context.Context, id int) (models.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Author)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockAuthorsMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthors)(nil).GetByID), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockAuthors) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockAuthorsMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuthors)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAuthors)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockAuthors) Update(author models.Author) error {
+func (m *MockAuthors) Update(ctx context.Context, author models.Author) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", author)
+	ret := m.ctrl.Call(m, "Update", ctx, author)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAuthorsMockRecorder) Update(author interface{}) *gomock.Call {
+func (mr *MockAuthorsMockRecorder) Update(ctx, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthors)(nil).Update), author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthors)(nil).Update), ctx, author)
 }
 
 // MockBooks is a mock of Books interface.
@@ -130,103 +325,691 @@ func (m *MockBooks) EXPECT() *MockBooksMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockBooks) Create(book models.Book) error {
+func (m *MockBooks) Create(ctx context.Context, book models.Book) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", book)
+	ret := m.ctrl.Call(m, "Create", ctx, book)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockBooksMockRecorder) Create(book interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) Create(ctx, book interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBooks)(nil).Create), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBooks)(nil).Create), ctx, book)
 }
 
 // Delete mocks base method.
-func (m *MockBooks) Delete(id int) error {
+func (m *MockBooks) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBooksMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBooks)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBooks)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockBooks) GetAll() ([]models.Book, error) {
+func (m *MockBooks) GetAll(ctx context.Context, query models.BookQuery) (models.BookPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]models.Book)
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
+	ret0, _ := ret[0].(models.BookPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockBooksMockRecorder) GetAll() *gomock.Call {
+func (mr *MockBooksMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBooks)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBooks)(nil).GetAll), ctx, query)
 }
 
 // GetByID mocks base method.
-func (m *MockBooks) GetByID(id int) (models.Book, error) {
+func (m *MockBooks) GetByID(ctx context.Context, id int) (models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockBooksMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBooks)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBooks)(nil).GetByID), ctx, id)
+}
+
+// GetLoans mocks base method.
+func (m *MockBooks) GetLoans(ctx context.Context, query models.LoanQuery) (models.LoanPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", ctx, query)
+	ret0, _ := ret[0].(models.LoanPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockBooksMockRecorder) GetLoans(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockBooks)(nil).GetLoans), ctx, query)
+}
+
+// GetOverdue mocks base method.
+func (m *MockBooks) GetOverdue(ctx context.Context) ([]models.RentedBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdue", ctx)
+	ret0, _ := ret[0].([]models.RentedBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdue indicates an expected call of GetOverdue.
+func (mr *MockBooksMockRecorder) GetOverdue(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdue", reflect.TypeOf((*MockBooks)(nil).GetOverdue), ctx)
+}
+
+// RenewBook mocks base method.
+func (m *MockBooks) RenewBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", ctx, userID, bookID, copyID)
+	ret0, _ := ret[0].(models.RentedBook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockBooksMockRecorder) RenewBook(ctx, userID, bookID, copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockBooks)(nil).RenewBook), ctx, userID, bookID, copyID)
 }
 
 // RentBook mocks base method.
-func (m *MockBooks) RentBook(userID, bookID int) error {
+func (m *MockBooks) RentBook(ctx context.Context, userID, bookID, copyID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RentBook", userID, bookID)
+	ret := m.ctrl.Call(m, "RentBook", ctx, userID, bookID, copyID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RentBook indicates an expected call of RentBook.
-func (mr *MockBooksMockRecorder) RentBook(userID, bookID interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) RentBook(ctx, userID, bookID, copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RentBook", reflect.TypeOf((*MockBooks)(nil).RentBook), ctx, userID, bookID, copyID)
+}
+
+// Restore mocks base method.
+func (m *MockBooks) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBooksMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RentBook", reflect.TypeOf((*MockBooks)(nil).RentBook), userID, bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBooks)(nil).Restore), ctx, id)
 }
 
 // ReturnBook mocks base method.
-func (m *MockBooks) ReturnBook(userID, bookID int) error {
+func (m *MockBooks) ReturnBook(ctx context.Context, userID, bookID, copyID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReturnBook", userID, bookID)
+	ret := m.ctrl.Call(m, "ReturnBook", ctx, userID, bookID, copyID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReturnBook indicates an expected call of ReturnBook.
-func (mr *MockBooksMockRecorder) ReturnBook(userID, bookID interface{}) *gomock.Call {
+func (mr *MockBooksMockRecorder) ReturnBook(ctx, userID, bookID, copyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockBooks)(nil).ReturnBook), ctx, userID, bookID, copyID)
+}
+
+// Search mocks base method.
+func (m *MockBooks) Search(ctx context.Context, query models.SearchQuery) (models.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].(models.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockBooksMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockBooks)(nil).Search), ctx, query)
+}
+
+// Update mocks base method.
+func (m *MockBooks) Update(ctx context.Context, book models.Book) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, book)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBooksMockRecorder) Update(ctx, book interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBooks)(nil).Update), ctx, book)
+}
+
+// MockCopies is a mock of Copies interface.
+type MockCopies struct {
+	ctrl     *gomock.Controller
+	recorder *MockCopiesMockRecorder
+}
+
+// MockCopiesMockRecorder is the mock recorder for MockCopies.
+type MockCopiesMockRecorder struct {
+	mock *MockCopies
+}
+
+// NewMockCopies creates a new mock instance.
+func NewMockCopies(ctrl *gomock.Controller) *MockCopies {
+	mock := &MockCopies{ctrl: ctrl}
+	mock.recorder = &MockCopiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCopies) EXPECT() *MockCopiesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCopies) Create(ctx context.Context, bookCopy models.BookCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, bookCopy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCopiesMockRecorder) Create(ctx, bookCopy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCopies)(nil).Create), ctx, bookCopy)
+}
+
+// Delete mocks base method.
+func (m *MockCopies) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCopiesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCopies)(nil).Delete), ctx, id)
+}
+
+// GetByBook mocks base method.
+func (m *MockCopies) GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID)
+	ret0, _ := ret[0].([]models.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockCopiesMockRecorder) GetByBook(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockCopies)(nil).GetByBook), ctx, bookID)
+}
+
+// GetByID mocks base method.
+func (m *MockCopies) GetByID(ctx context.Context, id int) (models.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCopiesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCopies)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockCopies) Update(ctx context.Context, bookCopy models.BookCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, bookCopy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCopiesMockRecorder) Update(ctx, bookCopy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCopies)(nil).Update), ctx, bookCopy)
+}
+
+// MockFines is a mock of Fines interface.
+type MockFines struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinesMockRecorder
+}
+
+// MockFinesMockRecorder is the mock recorder for MockFines.
+type MockFinesMockRecorder struct {
+	mock *MockFines
+}
+
+// NewMockFines creates a new mock instance.
+func NewMockFines(ctrl *gomock.Controller) *MockFines {
+	mock := &MockFines{ctrl: ctrl}
+	mock.recorder = &MockFinesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFines) EXPECT() *MockFinesMockRecorder {
+	return m.recorder
+}
+
+// GetBalance mocks base method.
+func (m *MockFines) GetBalance(ctx context.Context, userID int) (models.FineBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, userID)
+	ret0, _ := ret[0].(models.FineBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockFinesMockRecorder) GetBalance(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockFines)(nil).GetBalance), ctx, userID)
+}
+
+// Pay mocks base method.
+func (m *MockFines) Pay(ctx context.Context, userID int, amount int64, note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pay", ctx, userID, amount, note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pay indicates an expected call of Pay.
+func (mr *MockFinesMockRecorder) Pay(ctx, userID, amount, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pay", reflect.TypeOf((*MockFines)(nil).Pay), ctx, userID, amount, note)
+}
+
+// Waive mocks base method.
+func (m *MockFines) Waive(ctx context.Context, id int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Waive", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Waive indicates an expected call of Waive.
+func (mr *MockFinesMockRecorder) Waive(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Waive", reflect.TypeOf((*MockFines)(nil).Waive), ctx, id, reason)
+}
+
+// MockHolds is a mock of Holds interface.
+type MockHolds struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldsMockRecorder
+}
+
+// MockHoldsMockRecorder is the mock recorder for MockHolds.
+type MockHoldsMockRecorder struct {
+	mock *MockHolds
+}
+
+// NewMockHolds creates a new mock instance.
+func NewMockHolds(ctrl *gomock.Controller) *MockHolds {
+	mock := &MockHolds{ctrl: ctrl}
+	mock.recorder = &MockHoldsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHolds) EXPECT() *MockHoldsMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockHolds) Cancel(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockHoldsMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockHolds)(nil).Cancel), ctx, id)
+}
+
+// GetByBook mocks base method.
+func (m *MockHolds) GetByBook(ctx context.Context, bookID int) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBook", ctx, bookID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBook indicates an expected call of GetByBook.
+func (mr *MockHoldsMockRecorder) GetByBook(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBook", reflect.TypeOf((*MockHolds)(nil).GetByBook), ctx, bookID)
+}
+
+// GetByID mocks base method.
+func (m *MockHolds) GetByID(ctx context.Context, id int) (models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockHoldsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHolds)(nil).GetByID), ctx, id)
+}
+
+// GetByUser mocks base method.
+func (m *MockHolds) GetByUser(ctx context.Context, userID int) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockHoldsMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockHolds)(nil).GetByUser), ctx, userID)
+}
+
+// Place mocks base method.
+func (m *MockHolds) Place(ctx context.Context, userID, bookID int) (models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Place", ctx, userID, bookID)
+	ret0, _ := ret[0].(models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Place indicates an expected call of Place.
+func (mr *MockHoldsMockRecorder) Place(ctx, userID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Place", reflect.TypeOf((*MockHolds)(nil).Place), ctx, userID, bookID)
+}
+
+// MockLoanPolicies is a mock of LoanPolicies interface.
+type MockLoanPolicies struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanPoliciesMockRecorder
+}
+
+// MockLoanPoliciesMockRecorder is the mock recorder for MockLoanPolicies.
+type MockLoanPoliciesMockRecorder struct {
+	mock *MockLoanPolicies
+}
+
+// NewMockLoanPolicies creates a new mock instance.
+func NewMockLoanPolicies(ctrl *gomock.Controller) *MockLoanPolicies {
+	mock := &MockLoanPolicies{ctrl: ctrl}
+	mock.recorder = &MockLoanPoliciesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanPolicies) EXPECT() *MockLoanPoliciesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockLoanPolicies) Create(ctx context.Context, policy models.LoanPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLoanPoliciesMockRecorder) Create(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLoanPolicies)(nil).Create), ctx, policy)
+}
+
+// Delete mocks base method.
+func (m *MockLoanPolicies) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLoanPoliciesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLoanPolicies)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockLoanPolicies) GetAll(ctx context.Context) ([]models.LoanPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.LoanPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockLoanPoliciesMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockBooks)(nil).ReturnBook), userID, bookID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLoanPolicies)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockLoanPolicies) GetByID(ctx context.Context, id int) (models.LoanPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.LoanPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockLoanPoliciesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockLoanPolicies)(nil).GetByID), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockBooks) Update(book models.Book) error {
+func (m *MockLoanPolicies) Update(ctx context.Context, policy models.LoanPolicy) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", book)
+	ret := m.ctrl.Call(m, "Update", ctx, policy)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBooksMockRecorder) Update(book interface{}) *gomock.Call {
+func (mr *MockLoanPoliciesMockRecorder) Update(ctx, policy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBooks)(nil).Update), book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLoanPolicies)(nil).Update), ctx, policy)
+}
+
+// MockSubjects is a mock of Subjects interface.
+type MockSubjects struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubjectsMockRecorder
+}
+
+// MockSubjectsMockRecorder is the mock recorder for MockSubjects.
+type MockSubjectsMockRecorder struct {
+	mock *MockSubjects
+}
+
+// NewMockSubjects creates a new mock instance.
+func NewMockSubjects(ctrl *gomock.Controller) *MockSubjects {
+	mock := &MockSubjects{ctrl: ctrl}
+	mock.recorder = &MockSubjectsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubjects) EXPECT() *MockSubjectsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubjects) Create(ctx context.Context, subject models.Subject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubjectsMockRecorder) Create(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubjects)(nil).Create), ctx, subject)
+}
+
+// Delete mocks base method.
+func (m *MockSubjects) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubjectsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubjects)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockSubjects) GetAll(ctx context.Context) ([]models.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSubjectsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSubjects)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockSubjects) GetByID(ctx context.Context, id int) (models.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSubjectsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSubjects)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockSubjects) Update(ctx context.Context, subject models.Subject) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubjectsMockRecorder) Update(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubjects)(nil).Update), ctx, subject)
+}
+
+// MockTags is a mock of Tags interface.
+type MockTags struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsMockRecorder
+}
+
+// MockTagsMockRecorder is the mock recorder for MockTags.
+type MockTagsMockRecorder struct {
+	mock *MockTags
+}
+
+// NewMockTags creates a new mock instance.
+func NewMockTags(ctrl *gomock.Controller) *MockTags {
+	mock := &MockTags{ctrl: ctrl}
+	mock.recorder = &MockTagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTags) EXPECT() *MockTagsMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTags) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTags)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockTags) GetAll(ctx context.Context) ([]models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagsMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTags)(nil).GetAll), ctx)
+}
+
+// Update mocks base method.
+func (m *MockTags) Update(ctx context.Context, tag models.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsMockRecorder) Update(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTags)(nil).Update), ctx, tag)
 }
 
 // MockUsers is a mock of Users interface.
@@ -253,73 +1036,101 @@ func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockUsers) Create(user models.User) error {
+func (m *MockUsers) Create(ctx context.Context, user models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUsersMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockUsersMockRecorder) Create(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsers)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsers)(nil).Create), ctx, user)
 }
 
 // Delete mocks base method.
-func (m *MockUsers) Delete(id int) error {
+func (m *MockUsers) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUsersMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockUsersMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsers)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsers)(nil).Delete), ctx, id)
 }
 
 // GetAll mocks base method.
-func (m *MockUsers) GetAll() ([]models.User, error) {
+func (m *MockUsers) GetAll(ctx context.Context, query models.UserQuery) (models.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]models.User)
+	ret := m.ctrl.Call(m, "GetAll", ctx, query)
+	ret0, _ := ret[0].(models.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockUsersMockRecorder) GetAll() *gomock.Call {
+func (mr *MockUsersMockRecorder) GetAll(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsers)(nil).GetAll), ctx, query)
 }
 
 // GetByID mocks base method.
-func (m *MockUsers) GetByID(id int) (models.User, error) {
+func (m *MockUsers) GetByID(ctx context.Context, id int) (models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockUsersMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockUsersMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockUsers) Restore(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUsersMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUsers)(nil).Restore), ctx, id)
+}
+
+// SetRole mocks base method.
+func (m *MockUsers) SetRole(ctx context.Context, id int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUsersMockRecorder) SetRole(ctx, id, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUsers)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUsers)(nil).SetRole), ctx, id, role)
 }
 
 // Update mocks base method.
-func (m *MockUsers) Update(user models.User) error {
+func (m *MockUsers) Update(ctx context.Context, user models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", user)
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUsersMockRecorder) Update(user interface{}) *gomock.Call {
+func (mr *MockUsersMockRecorder) Update(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), ctx, user)
 }
@@ -0,0 +1,42 @@
+package service
+
+import "strings"
+
+// Listings are paged by defaultPageSize unless the client asks for a
+// different size, which may not exceed maxPageSize.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// maxSearchLength caps the length of a catalogue search query.
+const maxSearchLength = 200
+
+// pageBounds fills in the defaults of a requested page.
+func pageBounds(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// checkSort validates a sort key such as "-title" against the fields a
+// listing can be sorted by.
+func checkSort(sort string, fields ...string) error {
+	if sort == "" {
+		return nil
+	}
+	field := strings.TrimPrefix(sort, "-")
+	for _, allowed := range fields {
+		if field == allowed {
+			return nil
+		}
+	}
+	return invalid("invalid_sort", "cannot sort by %q, expected one of %s", field, strings.Join(fields, ", "))
+}
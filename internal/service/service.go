@@ -1,47 +1,150 @@
 package service
 
 import (
+	"context"
 	"library/internal/repository"
 	"library/models"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mock_service.go -package=service
+type APIKeys interface {
+	Create(ctx context.Context, key models.APIKey) (models.APIKey, string, error)
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, secret string) (models.Identity, error)
+}
+
+type Auth interface {
+	Register(ctx context.Context, user models.User, password string) error
+	Login(ctx context.Context, email, password string) (models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	Authenticate(ctx context.Context, accessToken string) (models.Identity, error)
+}
+
 type Authors interface {
-	GetAll() ([]models.Author, error)
-	Create(author models.Author) error
-	GetByID(id int) (models.Author, error)
-	Delete(id int) error
-	Update(author models.Author) error
+	GetAll(ctx context.Context, query models.AuthorQuery) (models.AuthorPage, error)
+	Create(ctx context.Context, author models.Author) error
+	GetByID(ctx context.Context, id int) (models.Author, error)
+	GetBooks(ctx context.Context, id int) ([]models.BookContributor, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, author models.Author) error
 }
 
 type Books interface {
-	GetAll() ([]models.Book, error)
-	Create(book models.Book) error
-	GetByID(id int) (models.Book, error)
-	Delete(id int) error
-	Update(book models.Book) error
-	RentBook(userID, bookID int) error
-	ReturnBook(userID, bookID int) error
+	GetAll(ctx context.Context, query models.BookQuery) (models.BookPage, error)
+	Search(ctx context.Context, query models.SearchQuery) (models.SearchPage, error)
+	Create(ctx context.Context, book models.Book) error
+	GetByID(ctx context.Context, id int) (models.Book, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, book models.Book) error
+	RentBook(ctx context.Context, userID, bookID, copyID int) error
+	ReturnBook(ctx context.Context, userID, bookID, copyID int) error
+	RenewBook(ctx context.Context, userID, bookID, copyID int) (models.RentedBook, error)
+	GetOverdue(ctx context.Context) ([]models.RentedBook, error)
+	GetLoans(ctx context.Context, query models.LoanQuery) (models.LoanPage, error)
+}
+
+type Copies interface {
+	GetByBook(ctx context.Context, bookID int) ([]models.BookCopy, error)
+	Create(ctx context.Context, bookCopy models.BookCopy) error
+	GetByID(ctx context.Context, id int) (models.BookCopy, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, bookCopy models.BookCopy) error
+}
+
+type Fines interface {
+	GetBalance(ctx context.Context, userID int) (models.FineBalance, error)
+	Pay(ctx context.Context, userID int, amount int64, note string) error
+	Waive(ctx context.Context, id int, reason string) error
+}
+
+type Holds interface {
+	Place(ctx context.Context, userID, bookID int) (models.Hold, error)
+	GetByID(ctx context.Context, id int) (models.Hold, error)
+	GetByBook(ctx context.Context, bookID int) ([]models.Hold, error)
+	GetByUser(ctx context.Context, userID int) ([]models.Hold, error)
+	Cancel(ctx context.Context, id int) error
+}
+
+type LoanPolicies interface {
+	GetAll(ctx context.Context) ([]models.LoanPolicy, error)
+	Create(ctx context.Context, policy models.LoanPolicy) error
+	GetByID(ctx context.Context, id int) (models.LoanPolicy, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, policy models.LoanPolicy) error
+}
+
+type Subjects interface {
+	GetAll(ctx context.Context) ([]models.Subject, error)
+	Create(ctx context.Context, subject models.Subject) error
+	GetByID(ctx context.Context, id int) (models.Subject, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, subject models.Subject) error
+}
+
+type Tags interface {
+	GetAll(ctx context.Context) ([]models.Tag, error)
+	Delete(ctx context.Context, id int) error
+	Update(ctx context.Context, tag models.Tag) error
 }
 
 type Users interface {
-	GetAll() ([]models.User, error)
-	Create(user models.User) error
-	GetByID(id int) (models.User, error)
-	Delete(id int) error
-	Update(user models.User) error
+	GetAll(ctx context.Context, query models.UserQuery) (models.UserPage, error)
+	Create(ctx context.Context, user models.User) error
+	GetByID(ctx context.Context, id int) (models.User, error)
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	Update(ctx context.Context, user models.User) error
+	SetRole(ctx context.Context, id int, role string) error
+}
+
+type Config struct {
+	// DefaultPolicy applies to users that have no loan policy assigned.
+	DefaultPolicy models.LoanPolicy
+	PickupWindow  time.Duration
+	// Fine amounts are in cents. A loan is fined FineDailyRate for every
+	// started day it is late, up to FineCap; users owing more than
+	// FineThreshold cannot rent.
+	FineDailyRate int64
+	FineCap       int64
+	FineThreshold int64
+	// Tokens are signed with TokenSecret. An access token authenticates
+	// requests for AccessTokenTTL, a refresh token gets new tokens for
+	// RefreshTokenTTL.
+	TokenSecret     []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 type Service struct {
+	APIKeys
+	Auth
 	Authors
 	Books
+	Copies
+	Fines
+	Holds
+	LoanPolicies
+	Subjects
+	Tags
 	Users
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, cfg Config) *Service {
 	return &Service{
-		Authors: NewAuthorsService(repos.Authors),
-		Books:   NewBooksService(repos.Books),
-		Users:   NewUsersService(repos.Users),
+		APIKeys:      NewAPIKeysService(repos.APIKeys),
+		Auth:         NewAuthService(repos.Users, cfg),
+		Authors:      NewAuthorsService(repos.Authors),
+		Books:        NewBooksService(repos.Books, repos.Copies, repos.Holds, repos.Users, repos.Fines, repos.Transactor, cfg),
+		Copies:       NewCopiesService(repos.Copies),
+		Fines:        NewFinesService(repos.Fines, repos.Users, repos.Transactor),
+		Holds:        NewHoldsService(repos.Holds, repos.Transactor, cfg),
+		LoanPolicies: NewLoanPoliciesService(repos.LoanPolicies),
+		Subjects:     NewSubjectsService(repos.Subjects, repos.Transactor),
+		Tags:         NewTagsService(repos.Tags),
+		Users:        NewUsersService(repos.Users),
 	}
 }
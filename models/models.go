@@ -3,33 +3,222 @@ package models
 import "time"
 
 type Author struct {
-	ID    int    `gorm:"primaryKey"`
-	Name  string `gorm:"not null"`
-	Books []Book
+	ID   int    `gorm:"primaryKey"`
+	Name string `gorm:"not null"`
+	// DeletedAt is when the author was archived, nil while they are not.
+	DeletedAt *time.Time
 }
 
 type Book struct {
-	ID          int       `gorm:"primaryKey"`
-	Title       string    `gorm:"not null"`
-	AuthorID    int       `gorm:"not null"`
-	PublishedAt time.Time `gorm:"not null"`
-	ISBN        string    `gorm:"unique;not null"`
-	Author      Author
-	RentedBooks []RentedBook
+	ID              int       `gorm:"primaryKey"`
+	Title           string    `gorm:"not null"`
+	PublishedAt     time.Time `gorm:"not null"`
+	ISBN            string    `gorm:"unique;not null"`
+	AvailableCopies int       `gorm:"->;-:migration"`
+	Contributors    []BookContributor
+	Subjects        []Subject `gorm:"many2many:book_subjects"`
+	Tags            []Tag     `gorm:"many2many:book_tags"`
+	Copies          []BookCopy
+	RentedBooks     []RentedBook
+	// DeletedAt is when the book was archived, nil while it is not.
+	DeletedAt *time.Time
+}
+
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+// BookContributor credits an author with a role on a book. A book lists its
+// contributors by Position, starting at 1.
+type BookContributor struct {
+	BookID   int    `gorm:"primaryKey"`
+	AuthorID int    `gorm:"primaryKey"`
+	Role     string `gorm:"primaryKey"`
+	Position int    `gorm:"not null"`
+	Author   Author
+	Book     Book
+}
+
+// Subject is a node of the subject and genre taxonomy that librarians
+// classify books under. A subject without a parent is at the top level.
+type Subject struct {
+	ID       int    `gorm:"primaryKey"`
+	Name     string `gorm:"not null"`
+	ParentID *int
+	Children []Subject `gorm:"foreignKey:ParentID"`
+}
+
+// Tag is a free-form label on books. Names are stored lower-case.
+type Tag struct {
+	ID   int    `gorm:"primaryKey"`
+	Name string `gorm:"unique;not null"`
+}
+
+const (
+	CopyConditionNew     = "new"
+	CopyConditionGood    = "good"
+	CopyConditionFair    = "fair"
+	CopyConditionPoor    = "poor"
+	CopyConditionDamaged = "damaged"
+)
+
+// BookCopy is a physical item of a Book that can be lent out.
+type BookCopy struct {
+	ID            int    `gorm:"primaryKey"`
+	BookID        int    `gorm:"not null"`
+	Barcode       string `gorm:"unique;not null"`
+	ShelfLocation string
+	Condition     string `gorm:"not null;default:good"`
 }
 
 type User struct {
+	ID           int    `gorm:"primaryKey"`
+	Name         string `gorm:"not null"`
+	Email        string `gorm:"unique;not null"`
+	LoanPolicyID *int
+	LoanPolicy   LoanPolicy
+	RentedBooks  []RentedBook
+	// Role decides what the user may do, see package policy.
+	Role string `gorm:"not null;default:patron"`
+	// PasswordHash is the bcrypt hash of the user's password, empty for users
+	// that never registered. It is never read from or written to JSON.
+	PasswordHash string `gorm:"not null;default:''" json:"-"`
+	// DeletedAt is when the user was archived, nil while they are not.
+	DeletedAt *time.Time
+}
+
+// Roles of a user.
+const (
+	RolePatron    = "patron"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
+// Identity is who a request is made by: a user, or the holder of an API
+// key, who is no user and has the permissions named by Scopes.
+type Identity struct {
+	UserID   int
+	Role     string
+	APIKeyID int
+	Scopes   []string
+}
+
+// TokenPair is issued when a user logs in. The access token authenticates
+// requests until ExpiresIn has passed; the refresh token gets a new pair.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// LoanPolicy is a patron category that decides how many books a user may
+// have out at once, for how long and how often a loan may be renewed.
+type LoanPolicy struct {
 	ID          int    `gorm:"primaryKey"`
-	Name        string `gorm:"not null"`
-	Email       string `gorm:"unique;not null"`
-	RentedBooks []RentedBook
+	Name        string `gorm:"unique;not null"`
+	MaxLoans    int    `gorm:"not null"`
+	LoanDays    int    `gorm:"not null"`
+	MaxRenewals int    `gorm:"not null"`
+}
+
+func (p LoanPolicy) LoanPeriod() time.Duration {
+	return time.Duration(p.LoanDays) * 24 * time.Hour
 }
 
 type RentedBook struct {
-	ID         int       `gorm:"primaryKey"`
-	UserID     int       `gorm:"not null"`
-	BookID     int       `gorm:"not null"`
-	RentedAt   time.Time `gorm:"not null"`
-	ReturnedAt *time.Time
-	Book       Book
+	ID           int       `gorm:"primaryKey"`
+	UserID       int       `gorm:"not null"`
+	BookID       int       `gorm:"not null"`
+	CopyID       int       `gorm:"not null;uniqueIndex:idx_rented_books_open_copy,where:returned_at IS NULL"`
+	RentedAt     time.Time `gorm:"not null"`
+	DueAt        time.Time `gorm:"not null"`
+	RenewalCount int       `gorm:"not null;default:0"`
+	ReturnedAt   *time.Time
+	User         User
+	Book         Book
+	Copy         BookCopy
+}
+
+const (
+	HoldWaiting   = "waiting"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+	HoldExpired   = "expired"
+)
+
+type Hold struct {
+	ID        int `gorm:"primaryKey"`
+	UserID    int `gorm:"not null"`
+	BookID    int `gorm:"not null"`
+	CopyID    *int
+	Status    string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	ReadyAt   *time.Time
+	ExpiresAt *time.Time
+	ClosedAt  *time.Time
+	User      User
+	Book      Book
+}
+
+// Fine is charged to a user for a loan returned after its due date.
+// Amounts are in cents.
+type Fine struct {
+	ID           int       `gorm:"primaryKey"`
+	UserID       int       `gorm:"not null"`
+	RentedBookID int       `gorm:"unique;not null"`
+	DaysLate     int       `gorm:"not null"`
+	Amount       int64     `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	WaivedAt     *time.Time
+	WaiveReason  string
+	RentedBook   RentedBook
+}
+
+// Payment is money received from a user towards their fines, in cents.
+type Payment struct {
+	ID        int   `gorm:"primaryKey"`
+	UserID    int   `gorm:"not null"`
+	Amount    int64 `gorm:"not null"`
+	Note      string
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// FineBalance is a user's fines ledger together with what they still owe.
+type FineBalance struct {
+	UserID   int
+	Balance  int64
+	Fines    []Fine
+	Payments []Payment
+}
+
+// SearchHit is a book found by a catalogue search. Contributors are the
+// names of its contributors in order. The snippets are the title and those
+// names with the matched words wrapped in <mark> tags.
+type SearchHit struct {
+	BookID              int
+	Title               string
+	ISBN                string
+	Contributors        string
+	Rank                float64
+	TitleSnippet        string
+	ContributorsSnippet string
+}
+
+// APIKey lets another system call the API without a user login. Only a
+// hash of the key is stored; Prefix is its start, to tell keys apart.
+type APIKey struct {
+	ID          int      `gorm:"primaryKey"`
+	Name        string   `gorm:"not null"`
+	Prefix      string   `gorm:"not null"`
+	KeyHash     string   `gorm:"unique;not null" json:"-"`
+	Scopes      []string `gorm:"serializer:json;not null"`
+	CreatedByID *int
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   *time.Time
+	RevokedAt   *time.Time
+	LastUsedAt  *time.Time
 }
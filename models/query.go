@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Queries select a page of a listing. Page is 1-based; Sort names the field
+// to order by, prefixed with "-" for descending order. Listings leave out
+// archived records, unless Deleted asks for those instead.
+
+type BookQuery struct {
+	Title         string
+	AuthorID      int
+	SubjectID     int
+	Tag           string
+	PublishedFrom *time.Time
+	PublishedTo   *time.Time
+	Available     *bool
+	Deleted       bool
+	Sort          string
+	Page          int
+	PageSize      int
+}
+
+type AuthorQuery struct {
+	Name     string
+	Deleted  bool
+	Sort     string
+	Page     int
+	PageSize int
+}
+
+type UserQuery struct {
+	Name     string
+	Email    string
+	Deleted  bool
+	Sort     string
+	Page     int
+	PageSize int
+}
+
+// SearchQuery selects a page of catalogue search results for free text,
+// best matches first.
+type SearchQuery struct {
+	Text     string
+	Page     int
+	PageSize int
+}
+
+// Loan status filters for loan listings.
+const (
+	LoanOpen     = "open"
+	LoanReturned = "returned"
+)
+
+// LoanQuery selects a page of loans of a user or a book, newest first
+// unless Ascending is set.
+type LoanQuery struct {
+	UserID    int
+	BookID    int
+	Status    string
+	Ascending bool
+	Page      int
+	PageSize  int
+}
+
+// Pages are one page of a listing with the total number of items matching
+// the query and, when there are more, a link to the next page.
+
+type BookPage struct {
+	Books    []Book
+	Total    int64
+	Page     int
+	PageSize int
+	Next     string
+}
+
+type AuthorPage struct {
+	Authors  []Author
+	Total    int64
+	Page     int
+	PageSize int
+	Next     string
+}
+
+type UserPage struct {
+	Users    []User
+	Total    int64
+	Page     int
+	PageSize int
+	Next     string
+}
+
+type SearchPage struct {
+	Hits     []SearchHit
+	Total    int64
+	Page     int
+	PageSize int
+	Next     string
+}
+
+type LoanPage struct {
+	Loans    []RentedBook
+	Total    int64
+	Page     int
+	PageSize int
+	Next     string
+}